@@ -2,8 +2,13 @@ package main
 
 import (
 	"app/internal/application"
+	"app/platform/auth"
+	"app/platform/logger"
+	"app/platform/trace"
+	"app/platform/webhook"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
@@ -16,19 +21,79 @@ func main() {
 		fmt.Println(err)
 		return
 	}
-	// ...
+	// - logger
+	lg, err := logger.New(os.Stdout, logger.Config{
+		Level:  os.Getenv("LOG_LEVEL"),
+		Format: os.Getenv("LOG_FORMAT"),
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	// - tracer
+	tr, err := trace.New(trace.Config{
+		Exporter:    os.Getenv("TRACE_EXPORTER"),
+		Path:        os.Getenv("TRACE_FILE"),
+		ServiceName: "fantasy-products",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer tr.Close()
+	// - auth
+	apiKeys, err := auth.ParseAPIKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	// - reorder webhook, only the alerts are saved without one
+	var reorderWebhook *webhook.Config
+	if url := os.Getenv("REORDER_WEBHOOK_URL"); url != "" {
+		reorderWebhook = &webhook.Config{URL: url, Secret: os.Getenv("REORDER_WEBHOOK_SECRET")}
+	}
 
 	// app
 	// - config
 	cfg := &application.ConfigApplicationDefault{
-		Db: &mysql.Config{
-			User:   "root",
-			Passwd: os.Getenv("SERVER_PASSWD"),
-			Net:    "tcp",
-			Addr:   "localhost:3306",
-			DBName: "fantasy_products",
+		Db: &application.ConfigStorage{
+			Driver: os.Getenv("DB_DRIVER"),
+			MySQL: &mysql.Config{
+				User:   "root",
+				Passwd: os.Getenv("SERVER_PASSWD"),
+				Net:    "tcp",
+				Addr:   "localhost:3306",
+				DBName: "fantasy_products",
+			},
+			PostgresDSN: os.Getenv("POSTGRES_DSN"),
+			SQLitePath:  os.Getenv("SQLITE_PATH"),
+		},
+		Addr:   "127.0.0.1:8080",
+		Logger: lg,
+		Tracer: tr,
+		Auth: &auth.Config{
+			APIKeys:   apiKeys,
+			JWTSecret: []byte(os.Getenv("JWT_SECRET")),
+			JWTIssuer: os.Getenv("JWT_ISSUER"),
+		},
+		Cache: &application.ConfigCache{
+			Capacity: 128,
+			TTL:      time.Minute,
+		},
+		CacheControl: map[string]string{
+			"/customers/top":            "private, max-age=5",
+			"/invoices/total/condition": "private, max-age=5",
+		},
+		RateLimit: &application.ConfigRateLimit{
+			Default: application.ConfigBucket{Rate: 20, Burst: 40},
+			Reports: application.ConfigBucket{Rate: 1, Burst: 5},
+		},
+		MaxBodySize:   64 << 10,
+		ShutdownDelay: 5 * time.Second,
+		Reorder: &application.ConfigReorder{
+			Interval: time.Hour,
+			Webhook:  reorderWebhook,
 		},
-		Addr: "127.0.0.1:8080",
 	}
 
 	// Comment this after load
@@ -38,6 +103,8 @@ func main() {
 	// 	InvoicePath:  os.Getenv("INVOICE_PATH"),
 	// 	ProductPath:  os.Getenv("PRODUCT_PATH"),
 	// 	SalePath:     os.Getenv("SALE_PATH"),
+	// 	Logger:       lg,
+	// 	Tracer:       tr,
 	// }
 
 	// loaderApp := application.NewApplicationLoader(cfgLoader)
@@ -48,13 +115,13 @@ func main() {
 	// - set up
 	err = app.SetUp()
 	if err != nil {
-		fmt.Println(err)
+		lg.Error("error setting up the application", "error", err)
 		return
 	}
 	// - run
 	err = app.Run()
 	if err != nil {
-		fmt.Println(err)
+		lg.Error("error running the application", "error", err)
 		return
 	}
 }
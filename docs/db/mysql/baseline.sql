@@ -0,0 +1,8 @@
+-- Records the migrations of a database whose schema was created or upgraded by hand with the former scripts of this
+-- directory, up to the customer contact details, so that the application does not apply them again on start.
+-- See MySQLMigrations in internal/repository/mysql.go.
+USE `fantasy_products`;
+
+CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY);
+
+INSERT IGNORE INTO schema_migrations (version) VALUES (1), (2), (3), (4), (5), (6), (7), (8), (9), (10), (11), (12);
@@ -1,3 +1,4 @@
+-- Sample data, loaded once the application has created the schema.
 USE `fantasy_products`;
 
 -- Add data to table customers
@@ -12,15 +13,15 @@ INSERT INTO `customers` (`first_name`,`last_name`,`condition`) VALUES
 ('Jane','Smith',0);
 
 -- Add data to table invoices
-INSERT INTO `invoices` (`datetime`,`customer_id`,`total`) VALUES
-('2019-01-01',1,100.00),
-('2019-01-01',2,200.00),
-('2019-01-01',3,300.00),
-('2019-01-01',4,400.00),
-('2019-01-01',5,500.00),
-('2019-01-01',6,600.00),
-('2019-01-01',7,700.00),
-('2019-01-01',8,800.00);
+INSERT INTO `invoices` (`datetime`,`customer_id`,`subtotal`,`tax`,`total`) VALUES
+('2019-01-01',1,100.00,0.00,100.00),
+('2019-01-01',2,200.00,0.00,200.00),
+('2019-01-01',3,300.00,0.00,300.00),
+('2019-01-01',4,400.00,0.00,400.00),
+('2019-01-01',5,500.00,0.00,500.00),
+('2019-01-01',6,600.00,0.00,600.00),
+('2019-01-01',7,700.00,0.00,700.00),
+('2019-01-01',8,800.00,0.00,800.00);
 
 -- Add data to table products
 INSERT INTO `products` (`description`,`price`) VALUES
@@ -33,13 +34,17 @@ INSERT INTO `products` (`description`,`price`) VALUES
 ('Product 7',700.00),
 ('Product 8',800.00);
 
+-- Add data to table product_prices
+INSERT INTO `product_prices` (`product_id`,`price`,`currency`,`valid_from`)
+SELECT `id`,`price`,`currency`,'2019-01-01 00:00:00' FROM `products`;
+
 -- Add data to table sales
-INSERT INTO `sales` (`quantity`,`invoice_id`,`product_id`) VALUES
-(1,1,1),
-(2,2,2),
-(3,3,3),
-(4,4,4),
-(5,5,5),
-(6,6,6),
-(7,7,7),
-(8,8,8);
+INSERT INTO `sales` (`quantity`,`invoice_id`,`product_id`,`unit_price`) VALUES
+(1,1,1,100.00),
+(2,2,2,200.00),
+(3,3,3,300.00),
+(4,4,4,400.00),
+(5,5,5,500.00),
+(6,6,6,600.00),
+(7,7,7,700.00),
+(8,8,8,800.00);
@@ -1,31 +1,119 @@
 package application
 
 import (
+	"app/internal"
 	"app/internal/handler"
 	"app/internal/repository"
 	"app/internal/service"
-	"database/sql"
+	"app/platform/auth"
+	"app/platform/cache"
+	"app/platform/idempotency"
+	"app/platform/metrics"
+	"app/platform/ratelimit"
+	"app/platform/trace"
+	"app/platform/web/middleware"
+	"app/platform/web/response"
+	"app/platform/webhook"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-sql-driver/mysql"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 )
 
 // ConfigApplicationDefault is the configuration for NewApplicationDefault.
 type ConfigApplicationDefault struct {
-	// Db is the database configuration.
-	Db *mysql.Config
+	// Db is the storage configuration.
+	Db *ConfigStorage
 	// Addr is the server address.
 	Addr string
+	// Cache is the reports cache configuration. Nil disables the cache.
+	Cache *ConfigCache
+	// Logger is the structured logger. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Tracer traces the requests down to the queries. Nil disables tracing.
+	Tracer *trace.Tracer
+	// Auth is the authentication configuration. Nil accepts no credentials, so only the public routes are reachable.
+	Auth *auth.Config
+	// CacheControl is the Cache-Control header of the read endpoints, by route path (e.g. "/customers/top").
+	// Routes not present default to "no-cache", so clients always revalidate their ETag.
+	CacheControl map[string]string
+	// RateLimit is the rate limiting configuration. Nil disables rate limiting.
+	RateLimit *ConfigRateLimit
+	// MaxBodySize is the maximum size of the request bodies, in bytes. Defaults to 1 MiB.
+	MaxBodySize int64
+	// IdempotencyTTL is the time the successful responses to the create requests carrying an Idempotency-Key are kept. Defaults to 24 hours.
+	IdempotencyTTL time.Duration
+	// ReadinessTimeout is the time limit of the readiness checks. Defaults to 2 seconds.
+	ReadinessTimeout time.Duration
+	// ShutdownDelay is the time the server keeps serving once draining, so probes notice it is not ready.
+	ShutdownDelay time.Duration
+	// ShutdownTimeout is the time limit for the in-flight requests to complete on shutdown. Defaults to 10 seconds.
+	ShutdownTimeout time.Duration
+	// Reorder is the low-stock alerts configuration. Nil disables the alerts job, and the reorder suggestions
+	// are computed with internal.ReorderPolicyDefault.
+	Reorder *ConfigReorder
+}
+
+// ConfigCache is the configuration of the reports cache.
+type ConfigCache struct {
+	// Capacity is the maximum number of cached entries.
+	Capacity int
+	// TTL is the time to live of the cached reports.
+	TTL time.Duration
+}
+
+// ConfigRateLimit is the configuration of the rate limiting, per client.
+// The health checks are not rate limited.
+type ConfigRateLimit struct {
+	// Default is the bucket of every route, per client IP address. It is taken before the credentials are
+	// authenticated, so the requests with invalid credentials count too.
+	Default ConfigBucket
+	// Reports is the bucket of the aggregate report routes, per API key or token subject, or IP address if anonymous,
+	// taken on top of Default.
+	Reports ConfigBucket
+}
+
+// ConfigBucket is the configuration of a token bucket.
+type ConfigBucket struct {
+	// Rate is the number of requests per second.
+	Rate float64
+	// Burst is the number of requests allowed at once.
+	Burst int
+}
+
+// ConfigReorder is the configuration of the low-stock alerts job, which raises an alert for every product whose
+// stock runs low and posts the alerts to the outbound webhook.
+type ConfigReorder struct {
+	// Policy is the policy of the reorder suggestions. Zero fields default to the ones of internal.ReorderPolicyDefault.
+	Policy internal.ReorderPolicy
+	// Interval is the time between two runs of the job. Defaults to 1 hour.
+	Interval time.Duration
+	// Webhook is the outbound webhook the alerts are posted to. Nil only saves the alerts.
+	Webhook *webhook.Config
+	// WebhookMaxAttempts is the number of attempts after which a delivery is given up. Defaults to 5.
+	WebhookMaxAttempts int
 }
 
 // NewApplicationDefault creates a new ApplicationDefault.
 func NewApplicationDefault(config *ConfigApplicationDefault) *ApplicationDefault {
 	// default values
 	defaultCfg := &ConfigApplicationDefault{
-		Db:   nil,
-		Addr: ":8080",
+		Db:               nil,
+		Addr:             ":8080",
+		Logger:           slog.Default(),
+		MaxBodySize:      1 << 20,
+		IdempotencyTTL:   24 * time.Hour,
+		ReadinessTimeout: 2 * time.Second,
+		ShutdownTimeout:  10 * time.Second,
 	}
 	if config != nil {
 		if config.Db != nil {
@@ -34,98 +122,469 @@ func NewApplicationDefault(config *ConfigApplicationDefault) *ApplicationDefault
 		if config.Addr != "" {
 			defaultCfg.Addr = config.Addr
 		}
+		if config.Cache != nil {
+			defaultCfg.Cache = config.Cache
+		}
+		if config.Logger != nil {
+			defaultCfg.Logger = config.Logger
+		}
+		if config.Tracer != nil {
+			defaultCfg.Tracer = config.Tracer
+		}
+		if config.Auth != nil {
+			defaultCfg.Auth = config.Auth
+		}
+		if config.CacheControl != nil {
+			defaultCfg.CacheControl = config.CacheControl
+		}
+		if config.RateLimit != nil {
+			defaultCfg.RateLimit = config.RateLimit
+		}
+		if config.MaxBodySize != 0 {
+			defaultCfg.MaxBodySize = config.MaxBodySize
+		}
+		if config.IdempotencyTTL != 0 {
+			defaultCfg.IdempotencyTTL = config.IdempotencyTTL
+		}
+		if config.ReadinessTimeout != 0 {
+			defaultCfg.ReadinessTimeout = config.ReadinessTimeout
+		}
+		if config.ShutdownDelay != 0 {
+			defaultCfg.ShutdownDelay = config.ShutdownDelay
+		}
+		if config.ShutdownTimeout != 0 {
+			defaultCfg.ShutdownTimeout = config.ShutdownTimeout
+		}
+		if config.Reorder != nil {
+			defaultCfg.Reorder = config.Reorder
+		}
 	}
 
 	return &ApplicationDefault{
-		cfgDb:   defaultCfg.Db,
-		cfgAddr: defaultCfg.Addr,
+		cfgDb:           defaultCfg.Db,
+		cfgAddr:         defaultCfg.Addr,
+		cfgCache:        defaultCfg.Cache,
+		cfgCacheControl: defaultCfg.CacheControl,
+		cfgReadiness:    defaultCfg.ReadinessTimeout,
+		cfgShutdown:     defaultCfg.ShutdownTimeout,
+		cfgDelay:        defaultCfg.ShutdownDelay,
+		logger:          defaultCfg.Logger,
+		tracer:          defaultCfg.Tracer,
+		cfgAuth:         defaultCfg.Auth,
+		cfgRateLimit:    defaultCfg.RateLimit,
+		cfgMaxBodySize:  defaultCfg.MaxBodySize,
+		cfgIdempotency:  defaultCfg.IdempotencyTTL,
+		cfgReorder:      defaultCfg.Reorder,
 	}
 }
 
 // ApplicationDefault is an implementation of the Application interface.
 type ApplicationDefault struct {
-	// cfgDb is the database configuration.
-	cfgDb *mysql.Config
+	// cfgDb is the storage configuration.
+	cfgDb *ConfigStorage
 	// cfgAddr is the server address.
 	cfgAddr string
-	// db is the database connection.
-	db *sql.DB
+	// cfgCache is the reports cache configuration.
+	cfgCache *ConfigCache
+	// cfgCacheControl is the Cache-Control header of the read endpoints, by route path.
+	cfgCacheControl map[string]string
+	// cfgAuth is the authentication configuration.
+	cfgAuth *auth.Config
+	// cfgRateLimit is the rate limiting configuration.
+	cfgRateLimit *ConfigRateLimit
+	// cfgMaxBodySize is the maximum size of the request bodies.
+	cfgMaxBodySize int64
+	// cfgIdempotency is the time the responses to the idempotent requests are kept.
+	cfgIdempotency time.Duration
+	// cfgReadiness is the time limit of the readiness checks.
+	cfgReadiness time.Duration
+	// cfgShutdown is the time limit for the in-flight requests to complete on shutdown.
+	cfgShutdown time.Duration
+	// cfgDelay is the time the server keeps serving once draining.
+	cfgDelay time.Duration
+	// cfgReorder is the low-stock alerts configuration, nil if the job is disabled.
+	cfgReorder *ConfigReorder
+	// draining is set once the graceful shutdown has started.
+	draining atomic.Bool
+	// logger is the structured logger.
+	logger *slog.Logger
+	// tracer is the tracer, nil if tracing is disabled.
+	tracer *trace.Tracer
+	// st is the storage, holding the database connection and the repositories.
+	st storage
 	// router is the chi router.
 	router *chi.Mux
+	// svReorder is the reorder service run by the alerts job.
+	svReorder internal.ServiceReorder
+	// svWebhook is the webhook service run by the alerts job, nil if there is no webhook.
+	svWebhook internal.ServiceWebhook
 }
 
 // SetUp sets up the application.
 func (a *ApplicationDefault) SetUp() (err error) {
 	// dependencies
-	// - db: init
-	a.db, err = sql.Open("mysql", a.cfgDb.FormatDSN())
-	if err != nil {
-		return
-	}
-	// - db: ping
-	err = a.db.Ping()
+	// - storage: db and repository
+	a.st, err = openStorage(a.cfgDb)
 	if err != nil {
 		return
 	}
-	// - repository
-	rpCustomer := repository.NewCustomersMySQL(a.db)
-	rpProduct := repository.NewProductsMySQL(a.db)
-	rpInvoice := repository.NewInvoicesMySQL(a.db)
-	rpSale := repository.NewSalesMySQL(a.db)
 	// - service
-	svCustomer := service.NewCustomersDefault(rpCustomer)
-	svProduct := service.NewProductsDefault(rpProduct)
-	svInvoice := service.NewInvoicesDefault(rpInvoice)
-	svSale := service.NewSalesDefault(rpSale)
+	var svCustomer internal.ServiceCustomer = service.NewCustomersDefault(a.st.rpCustomer)
+	var svProduct internal.ServiceProduct = service.NewProductsDefault(a.st.rpProduct)
+	var svInvoice internal.ServiceInvoice = service.NewInvoicesDefault(a.st.rpInvoice)
+	var svSale internal.ServiceSale = service.NewSalesDefault(a.st.rpSale)
+	var svExchangeRate internal.ServiceExchangeRate = service.NewExchangeRatesDefault(a.st.rpExchangeRate)
+	var svTaxRate internal.ServiceTaxRate = service.NewTaxRatesDefault(a.st.rpTaxRate)
+	var svPromotion internal.ServicePromotion = service.NewPromotionsDefault(a.st.rpPromotion)
+	var svStock internal.ServiceStock = service.NewStocksDefault(a.st.rpStock)
+	var svCategory internal.ServiceCategory = service.NewCategoriesDefault(a.st.rpCategory)
+	a.svReorder = service.NewReorderDefault(a.st.rpStock, a.st.rpStockAlert, a.reorderPolicy(), a.cfgReorder != nil && a.cfgReorder.Webhook != nil)
+	if a.cfgReorder != nil && a.cfgReorder.Webhook != nil {
+		a.svWebhook = service.NewWebhookDefault(a.st.rpWebhook, webhook.New(*a.cfgReorder.Webhook), a.cfgReorder.WebhookMaxAttempts, 0)
+	}
+	svAudit := service.NewAuditDefault(a.st.rpAudit)
+	// - service: cache
+	var ch cache.Cache
+	if a.cfgCache != nil {
+		ch = cache.NewLRU(a.cfgCache.Capacity)
+		svCustomer = service.NewCustomersCached(svCustomer, ch, a.cfgCache.TTL)
+		svProduct = service.NewProductsCached(svProduct, ch, a.cfgCache.TTL)
+		svInvoice = service.NewInvoicesCached(svInvoice, ch, a.cfgCache.TTL)
+		svSale = service.NewSalesCached(svSale, ch)
+		svExchangeRate = service.NewExchangeRatesCached(svExchangeRate, ch)
+	}
+	// - service: tracing, outermost so cache hits are traced too
+	if a.tracer != nil {
+		svCustomer = service.NewCustomersTraced(svCustomer)
+		svProduct = service.NewProductsTraced(svProduct)
+		svInvoice = service.NewInvoicesTraced(svInvoice)
+		svSale = service.NewSalesTraced(svSale)
+		svExchangeRate = service.NewExchangeRatesTraced(svExchangeRate)
+		svTaxRate = service.NewTaxRatesTraced(svTaxRate)
+		svPromotion = service.NewPromotionsTraced(svPromotion)
+		svStock = service.NewStocksTraced(svStock)
+		svCategory = service.NewCategoriesTraced(svCategory)
+		a.svReorder = service.NewReorderTraced(a.svReorder)
+		if a.svWebhook != nil {
+			a.svWebhook = service.NewWebhookTraced(a.svWebhook)
+		}
+	}
 	// - handler
 	hdCustomer := handler.NewCustomersDefault(svCustomer)
 	hdProduct := handler.NewProductsDefault(svProduct)
 	hdInvoice := handler.NewInvoicesDefault(svInvoice)
 	hdSale := handler.NewSalesDefault(svSale)
+	hdExchangeRate := handler.NewExchangeRatesDefault(svExchangeRate)
+	hdTaxRate := handler.NewTaxRatesDefault(svTaxRate)
+	hdPromotion := handler.NewPromotionsDefault(svPromotion)
+	hdStock := handler.NewStocksDefault(svStock)
+	hdCategory := handler.NewCategoriesDefault(svCategory)
+	hdReorder := handler.NewReorderDefault(a.svReorder)
+	hdAudit := handler.NewAuditDefault(svAudit)
+	hdHealth := handler.NewHealthDefault(a.draining.Load, a.cfgReadiness, a.healthChecks()...)
 
 	// routes
 	// - router
 	a.router = chi.NewRouter()
+	// - errors of the unknown routes and methods, as problem+json like every other error
+	a.router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		response.Error(w, http.StatusNotFound, "route not found")
+	})
+	a.router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		response.Errorf(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	})
 	// - middlewares
-	a.router.Use(middleware.Logger)
-	a.router.Use(middleware.Recoverer)
+	a.router.Use(middleware.RequestID)
+	if a.tracer != nil {
+		a.router.Use(middleware.Trace(a.tracer))
+	}
+	a.router.Use(middleware.Logger(a.logger))
+	a.router.Use(middleware.Metrics(metrics.Default))
+	a.router.Use(chimiddleware.Recoverer)
+	a.router.Use(middleware.MaxBodySize(a.cfgMaxBodySize))
+	// - rate limits: every route but the health checks per client IP address, before authenticating so invalid
+	// credentials are limited too, and the aggregate reports per authenticated client on top
+	limit := func(next http.Handler) http.Handler { return next }
+	reports := limit
+	if a.cfgRateLimit != nil {
+		limit = middleware.RateLimit(ratelimit.NewLimiter(a.cfgRateLimit.Default.Rate, a.cfgRateLimit.Default.Burst))
+		reports = middleware.RateLimit(ratelimit.NewLimiter(a.cfgRateLimit.Reports.Rate, a.cfgRateLimit.Reports.Burst))
+	}
+	// - the changes are audited as made by the authenticated subject
+	authenticate := middleware.Authenticate(a.authenticator(), func(ctx context.Context, p auth.Principal) context.Context {
+		return internal.WithActor(ctx, p.Subject)
+	})
+	// - policies: reader reads, clerk records customers, invoices and sales, admin manages the catalog, its categories and its stock, the exchange rates, the tax rates and the promotions and runs bulk operations
+	reader := middleware.RequireRole(auth.RoleReader)
+	clerk := middleware.RequireRole(auth.RoleClerk)
+	admin := middleware.RequireRole(auth.RoleAdmin)
+	// - idempotency keys on the create routes, after the policies so rejected requests do not reserve a key
+	idem := middleware.NewIdempotency(idempotency.NewMemory(), a.cfgIdempotency).Handler
 	// - endpoints
-	a.router.Route("/customers", func(r chi.Router) {
+	// - GET /healthz and /readyz
+	a.router.Get("/healthz", hdHealth.Healthz())
+	a.router.Get("/readyz", hdHealth.Readyz())
+	// - every other endpoint is rate limited and authenticated
+	api := a.router.With(limit, authenticate)
+	api.Route("/customers", func(r chi.Router) {
 		// - GET /customers
-		r.Get("/", hdCustomer.GetAll())
-		r.Get("/top", hdCustomer.GetTopCustomers())
+		r.With(reader, a.conditional("/customers")).Get("/", hdCustomer.GetAll())
+		r.With(reader, reports, a.conditional("/customers/top", "currency")).Get("/top", hdCustomer.GetTopCustomers())
+		// - GET /customers/search
+		r.With(reader, a.conditional("/customers/search", "q", "limit", "offset")).Get("/search", hdCustomer.Search())
 		// - POST /customers
-		r.Post("/", hdCustomer.Create())
+		r.With(clerk, idem).Post("/", hdCustomer.Create())
+		// - PUT /customers/{id}
+		r.With(clerk).Put("/{id}", hdCustomer.Update())
 	})
-	a.router.Route("/products", func(r chi.Router) {
+	api.Route("/products", func(r chi.Router) {
 		// - GET /products
-		r.Get("/", hdProduct.GetAll())
-		r.Get("/top", hdProduct.GetTopProducts())
+		r.With(reader, a.conditional("/products")).Get("/", hdProduct.GetAll())
+		r.With(reader, reports, a.conditional("/products/top")).Get("/top", hdProduct.GetTopProducts())
+		// - GET /products/search
+		r.With(reader, a.conditional("/products/search", "q", "limit", "offset")).Get("/search", hdProduct.Search())
+		// - GET /products/reorder
+		r.With(reader, reports, a.conditional("/products/reorder")).Get("/reorder", hdReorder.GetSuggestions())
 		// - POST /products
-		r.Post("/", hdProduct.Create())
+		r.With(admin, idem).Post("/", hdProduct.Create())
+		// - GET /products/{id}/prices
+		r.With(reader, a.conditional("/products/{id}/prices")).Get("/{id}/prices", hdProduct.GetPrices())
+		// - PUT /products/{id}/price
+		r.With(admin).Put("/{id}/price", hdProduct.UpdatePrice())
+		// - PUT /products/{id}/archived
+		r.With(admin).Put("/{id}/archived", hdProduct.UpdateArchived())
+		// - GET /products/{id}/stock
+		r.With(reader, a.conditional("/products/{id}/stock")).Get("/{id}/stock", hdStock.Get())
+		// - GET /products/{id}/stock/movements
+		r.With(reader, a.conditional("/products/{id}/stock/movements")).Get("/{id}/stock/movements", hdStock.GetMovements())
+		// - POST /products/{id}/stock/movements
+		r.With(admin, idem).Post("/{id}/stock/movements", hdStock.CreateMovement())
 	})
-	a.router.Route("/invoices", func(r chi.Router) {
+	api.Route("/categories", func(r chi.Router) {
+		// - GET /categories
+		r.With(reader, a.conditional("/categories")).Get("/", hdCategory.GetAll())
+		r.With(reader, reports, a.conditional("/categories/top", "currency", "parent_id")).Get("/top", hdCategory.GetTopCategories())
+		// - POST /categories
+		r.With(admin, idem).Post("/", hdCategory.Create())
+	})
+	api.Route("/invoices", func(r chi.Router) {
 		// - GET /invoices
-		r.Get("/", hdInvoice.GetAll())
+		r.With(reader, a.conditional("/invoices")).Get("/", hdInvoice.GetAll())
 		// - POST /invoices
-		r.Post("/", hdInvoice.Create())
-		r.Put("/update_total", hdInvoice.UpdateInvoicesTotal())
-		r.Get("/total/condition", hdInvoice.InvoicesTotalByCondition())
+		r.With(clerk, idem).Post("/", hdInvoice.Create())
+		r.With(admin).Put("/update_total", hdInvoice.UpdateInvoicesTotal())
+		r.With(reader, reports, a.conditional("/invoices/total/condition", "currency")).Get("/total/condition", hdInvoice.InvoicesTotalByCondition())
 	})
-	a.router.Route("/sales", func(r chi.Router) {
+	api.Route("/sales", func(r chi.Router) {
 		// - GET /sales
-		r.Get("/", hdSale.GetAll())
+		r.With(reader, a.conditional("/sales")).Get("/", hdSale.GetAll())
 		// - POST /sales
-		r.Post("/", hdSale.Create())
+		r.With(clerk, idem).Post("/", hdSale.Create())
+	})
+	api.Route("/exchange_rates", func(r chi.Router) {
+		// - GET /exchange_rates
+		r.With(reader, a.conditional("/exchange_rates")).Get("/", hdExchangeRate.GetAll())
+		// - POST /exchange_rates
+		r.With(admin, idem).Post("/", hdExchangeRate.Create())
+	})
+	api.Route("/tax_rates", func(r chi.Router) {
+		// - GET /tax_rates
+		r.With(reader, a.conditional("/tax_rates")).Get("/", hdTaxRate.GetAll())
+		// - POST /tax_rates
+		r.With(admin, idem).Post("/", hdTaxRate.Create())
 	})
+	api.Route("/promotions", func(r chi.Router) {
+		// - GET /promotions
+		r.With(reader, a.conditional("/promotions")).Get("/", hdPromotion.GetAll())
+		// - POST /promotions
+		r.With(admin, idem).Post("/", hdPromotion.Create())
+	})
+	// - GET /stock_alerts
+	api.With(reader, a.conditional("/stock_alerts")).Get("/stock_alerts", hdReorder.GetAlerts())
+	// - GET /audit
+	api.With(admin).Get("/audit", hdAudit.GetAll())
+	if ch != nil {
+		hdCache := handler.NewCacheDefault(ch)
+		// - GET /cache/stats
+		api.With(admin).Get("/cache/stats", hdCache.GetStats())
+	}
+	// - GET /metrics
+	a.registerMetrics(ch)
+	api.Get("/metrics", metrics.Default.Handler().ServeHTTP)
+
+	return
+}
+
+// authenticator returns the authenticator of the configured credentials, nil if there is none.
+func (a *ApplicationDefault) authenticator() *auth.Authenticator {
+	if a.cfgAuth == nil {
+		return nil
+	}
+	return auth.NewAuthenticator(*a.cfgAuth)
+}
+
+// healthChecks returns the readiness checks of the storage: the database is reachable
+// and its schema is at the version of the last migration.
+func (a *ApplicationDefault) healthChecks() (checks []handler.HealthCheck) {
+	db, migrations := a.st.db, a.st.migrations
+	if db == nil {
+		return
+	}
 
+	checks = append(checks,
+		handler.HealthCheck{Name: "database", Check: db.PingContext},
+		handler.HealthCheck{Name: "schema", Check: func(ctx context.Context) error {
+			return repository.CheckSchemaVersion(ctx, db, migrations)
+		}},
+	)
 	return
 }
 
-// Run runs the application.
+// registerMetrics registers the metrics read on every scrape: the database pool stats and the cache stats.
+func (a *ApplicationDefault) registerMetrics(ch cache.Cache) {
+	if db := a.st.db; db != nil {
+		metrics.Default.GaugeFunc("db_open_connections", "Number of established connections to the database.",
+			func() float64 { return float64(db.Stats().OpenConnections) })
+		metrics.Default.GaugeFunc("db_in_use_connections", "Number of connections currently in use.",
+			func() float64 { return float64(db.Stats().InUse) })
+		metrics.Default.GaugeFunc("db_idle_connections", "Number of idle connections.",
+			func() float64 { return float64(db.Stats().Idle) })
+		metrics.Default.GaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.",
+			func() float64 { return float64(db.Stats().MaxOpenConnections) })
+		metrics.Default.CounterFunc("db_wait_count_total", "Number of connections waited for.",
+			func() float64 { return float64(db.Stats().WaitCount) })
+		metrics.Default.CounterFunc("db_wait_duration_seconds_total", "Time spent waiting for a connection in seconds.",
+			func() float64 { return db.Stats().WaitDuration.Seconds() })
+	}
+
+	if ch != nil {
+		metrics.Default.CounterFunc("cache_hits_total", "Number of reports cache hits.",
+			func() float64 { return float64(ch.Stats().Hits) })
+		metrics.Default.CounterFunc("cache_misses_total", "Number of reports cache misses.",
+			func() float64 { return float64(ch.Stats().Misses) })
+		metrics.Default.CounterFunc("cache_evictions_total", "Number of reports cache evictions.",
+			func() float64 { return float64(ch.Stats().Evictions) })
+		metrics.Default.GaugeFunc("cache_entries", "Number of reports cache entries.",
+			func() float64 { return float64(ch.Stats().Entries) })
+	}
+}
+
+// reorderPolicy returns the configured policy of the reorder suggestions, completed with internal.ReorderPolicyDefault.
+func (a *ApplicationDefault) reorderPolicy() (p internal.ReorderPolicy) {
+	p = internal.ReorderPolicyDefault
+	if a.cfgReorder == nil {
+		return
+	}
+	if a.cfgReorder.Policy.WindowDays > 0 {
+		p.WindowDays = a.cfgReorder.Policy.WindowDays
+	}
+	if a.cfgReorder.Policy.MinDays > 0 {
+		p.MinDays = a.cfgReorder.Policy.MinDays
+	}
+	if a.cfgReorder.Policy.TargetDays > 0 {
+		p.TargetDays = a.cfgReorder.Policy.TargetDays
+	}
+	return
+}
+
+// runReorder runs the low-stock alerts job until ctx is done: on start and then every interval, it raises the
+// alerts of the products whose stock runs low and posts the pending ones to the webhook.
+// A failed run is logged and retried on the next tick.
+func (a *ApplicationDefault) runReorder(ctx context.Context) {
+	interval := a.cfgReorder.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// - alerts
+		al, err := a.svReorder.RaiseAlerts(ctx)
+		if err != nil && ctx.Err() == nil {
+			a.logger.Error("error raising stock alerts", "error", err)
+		} else if len(al) > 0 {
+			a.logger.Info("stock alerts raised", "count", len(al))
+		}
+		// - webhook, including the deliveries left pending by the previous runs
+		if a.svWebhook != nil {
+			n, err := a.svWebhook.Deliver(ctx)
+			if err != nil && ctx.Err() == nil {
+				a.logger.Error("error delivering webhook events", "error", err)
+			} else if n > 0 {
+				a.logger.Info("webhook events delivered", "count", n)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// conditional returns the conditional GET middleware of the read endpoint at path, which reads the query parameters params.
+func (a *ApplicationDefault) conditional(path string, params ...string) func(http.Handler) http.Handler {
+	cacheControl, ok := a.cfgCacheControl[path]
+	if !ok {
+		cacheControl = "no-cache"
+	}
+
+	return middleware.NewETag(cacheControl, params...).Handler
+}
+
+// Run runs the application until it receives SIGINT or SIGTERM, then shuts it down gracefully:
+// readiness fails from then on, and in-flight requests are given time to complete.
 func (a *ApplicationDefault) Run() (err error) {
-	defer a.db.Close()
+	defer a.st.Close()
 
-	err = http.ListenAndServe(a.cfgAddr, a.router)
+	srv := &http.Server{Addr: a.cfgAddr, Handler: a.router}
+
+	// jobs, stopped before the storage is closed
+	ctxJobs, cancelJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+	defer jobs.Wait()
+	defer cancelJobs()
+	if a.cfgReorder != nil {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			a.runReorder(ctxJobs)
+		}()
+	}
+
+	// serve
+	errc := make(chan error, 1)
+	go func() {
+		a.logger.Info("server listening", "addr", a.cfgAddr)
+		errc <- srv.ListenAndServe()
+	}()
+
+	// wait for a signal
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err = <-errc:
+		return
+	case <-ctx.Done():
+	}
+
+	// drain
+	a.draining.Store(true)
+	a.logger.Info("server draining", "delay", a.cfgDelay)
+	time.Sleep(a.cfgDelay)
+
+	// shutdown
+	ctxShutdown, cancel := context.WithTimeout(context.Background(), a.cfgShutdown)
+	defer cancel()
+	err = srv.Shutdown(ctxShutdown)
+	if err != nil {
+		return
+	}
+	if err = <-errc; errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	a.logger.Info("server stopped")
 	return
 }
@@ -1,24 +1,29 @@
 package application
 
 import (
+	"app/internal"
 	"app/internal/loader"
-	"app/internal/repository"
-	"database/sql"
-
-	"github.com/go-sql-driver/mysql"
+	"app/platform/logger"
+	"app/platform/trace"
+	"context"
+	"log/slog"
 )
 
 type ConfigApplicationLoader struct {
-	Db           *mysql.Config
+	Db           *ConfigStorage
 	CustomerPath string
 	InvoicePath  string
 	ProductPath  string
 	SalePath     string
+	// Logger is the structured logger. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Tracer traces the loader stages. Nil disables tracing.
+	Tracer *trace.Tracer
 }
 
 type ApplicationLoader struct {
 	config         *ConfigApplicationLoader
-	db             *sql.DB
+	st             storage
 	customerLoader *loader.CustomerLoader
 	invoiceLoader  *loader.InvoiceLoader
 	productLoader  *loader.ProductLoader
@@ -32,46 +37,59 @@ func NewApplicationLoader(config *ConfigApplicationLoader) *ApplicationLoader {
 }
 
 func (a *ApplicationLoader) SetUp() error {
-	db, err := sql.Open("mysql", a.config.Db.FormatDSN())
+	st, err := openStorage(a.config.Db)
 	if err != nil {
 		return err
 	}
 
-	a.db = db
+	a.st = st
 
-	rc := repository.NewCustomersMySQL(a.db)
-	cl := loader.NewCustomerLoader(a.config.CustomerPath, rc)
+	cl := loader.NewCustomerLoader(a.config.CustomerPath, a.st.rpCustomer)
 	a.customerLoader = cl
 
-	ri := repository.NewInvoicesMySQL(a.db)
-	il := loader.NewInvoiceLoader(a.config.InvoicePath, ri)
+	il := loader.NewInvoiceLoader(a.config.InvoicePath, a.st.rpInvoice)
 	a.invoiceLoader = il
 
-	rp := repository.NewProductsMySQL(a.db)
-	pl := loader.NewProductLoader(a.config.ProductPath, rp)
+	pl := loader.NewProductLoader(a.config.ProductPath, a.st.rpProduct)
 	a.productLoader = pl
 
-	rs := repository.NewSalesMySQL(a.db)
-	sl := loader.NewSaleLoader(a.config.SalePath, rs)
+	sl := loader.NewSaleLoader(a.config.SalePath, a.st.rpSale)
 	a.saleLoader = sl
 
 	return nil
 }
 
-func (a *ApplicationLoader) Run() error {
-	if err := a.customerLoader.LoadAndSave(); err != nil {
+func (a *ApplicationLoader) Run() (err error) {
+	defer a.st.Close()
+
+	l := a.config.Logger
+	if l == nil {
+		l = slog.Default()
+	}
+	ctx := logger.WithContext(context.Background(), l)
+	// - the loaded records are audited as created by the loader, and the loaded products do not track their stock
+	// until their first receipt or adjustment, so their past sales load
+	ctx = internal.WithActor(ctx, "loader")
+	ctx = internal.WithStockUntracked(ctx)
+	if a.config.Tracer != nil {
+		var sp *trace.Span
+		ctx, sp = a.config.Tracer.Start(ctx, "loader")
+		defer func() { sp.End(err) }()
+	}
+
+	if err := a.customerLoader.LoadAndSave(ctx); err != nil {
 		return err
 	}
 
-	if err := a.invoiceLoader.LoadAndSave(); err != nil {
+	if err := a.invoiceLoader.LoadAndSave(ctx); err != nil {
 		return err
 	}
 
-	if err := a.productLoader.LoadAndSave(); err != nil {
+	if err := a.productLoader.LoadAndSave(ctx); err != nil {
 		return err
 	}
 
-	if err := a.saleLoader.LoadAndSave(); err != nil {
+	if err := a.saleLoader.LoadAndSave(ctx); err != nil {
 		return err
 	}
 
@@ -0,0 +1,193 @@
+package application
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// StorageMySQL is the driver for the mysql storage.
+	StorageMySQL = "mysql"
+	// StoragePostgres is the driver for the postgres storage.
+	StoragePostgres = "postgres"
+	// StorageSQLite is the driver for the sqlite storage.
+	StorageSQLite = "sqlite"
+	// StorageMemory is the driver for the in-memory storage.
+	StorageMemory = "memory"
+)
+
+var (
+	// ErrStorageDriverUnknown is used when the storage driver is not supported.
+	ErrStorageDriverUnknown = errors.New("storage driver unknown")
+	// ErrStorageConfigMissing is used when the configuration of the chosen driver is missing.
+	ErrStorageConfigMissing = errors.New("storage config missing")
+)
+
+// ConfigStorage is the driver agnostic storage configuration.
+type ConfigStorage struct {
+	// Driver is the storage driver. Defaults to StorageMySQL.
+	Driver string
+	// MySQL is the mysql configuration, used by StorageMySQL.
+	MySQL *mysql.Config
+	// PostgresDSN is the connection string, used by StoragePostgres.
+	PostgresDSN string
+	// SQLitePath is the path of the database file, used by StorageSQLite.
+	SQLitePath string
+}
+
+// storage is the result of opening a ConfigStorage.
+type storage struct {
+	// db is the database connection, nil for the in-memory storage.
+	db *sql.DB
+	// migrations are the schema migrations applied to db, nil for the in-memory storage.
+	migrations []repository.Migration
+	// rpCustomer is the repository for customer entity.
+	rpCustomer internal.RepositoryCustomer
+	// rpProduct is the repository for product entity.
+	rpProduct internal.RepositoryProduct
+	// rpInvoice is the repository for invoice entity.
+	rpInvoice internal.RepositoryInvoice
+	// rpSale is the repository for sale entity.
+	rpSale internal.RepositorySale
+	// rpAudit is the repository for audit record entity.
+	rpAudit internal.RepositoryAudit
+	// rpExchangeRate is the repository for exchange rate entity.
+	rpExchangeRate internal.RepositoryExchangeRate
+	// rpTaxRate is the repository for tax rate entity.
+	rpTaxRate internal.RepositoryTaxRate
+	// rpPromotion is the repository for promotion entity.
+	rpPromotion internal.RepositoryPromotion
+	// rpStock is the repository for stock entity.
+	rpStock internal.RepositoryStock
+	// rpStockAlert is the repository for stock alert entity.
+	rpStockAlert internal.RepositoryStockAlert
+	// rpWebhook is the repository for webhook delivery entity.
+	rpWebhook internal.RepositoryWebhook
+	// rpCategory is the repository for category entity.
+	rpCategory internal.RepositoryCategory
+}
+
+// openStorage opens the database described by cfg and builds its repositories.
+func openStorage(cfg *ConfigStorage) (st storage, err error) {
+	if cfg == nil {
+		err = fmt.Errorf("%w: nil config", ErrStorageConfigMissing)
+		return
+	}
+
+	switch cfg.Driver {
+	case StorageMySQL, "":
+		if cfg.MySQL == nil {
+			err = fmt.Errorf("%w: %s", ErrStorageConfigMissing, StorageMySQL)
+			return
+		}
+		// - db: init and migrate
+		st.db, err = repository.NewMySQLDB(cfg.MySQL)
+		if err != nil {
+			return
+		}
+		st.migrations = repository.MySQLMigrations
+		// - repository
+		st.rpCustomer = repository.NewCustomersMySQL(st.db)
+		st.rpProduct = repository.NewProductsMySQL(st.db)
+		st.rpInvoice = repository.NewInvoicesMySQL(st.db)
+		st.rpSale = repository.NewSalesMySQL(st.db)
+		st.rpAudit = repository.NewAuditMySQL(st.db)
+		st.rpExchangeRate = repository.NewExchangeRatesMySQL(st.db)
+		st.rpTaxRate = repository.NewTaxRatesMySQL(st.db)
+		st.rpPromotion = repository.NewPromotionsMySQL(st.db)
+		st.rpStock = repository.NewStocksMySQL(st.db)
+		st.rpStockAlert = repository.NewStockAlertsMySQL(st.db)
+		st.rpWebhook = repository.NewWebhooksMySQL(st.db)
+		st.rpCategory = repository.NewCategoriesMySQL(st.db)
+	case StoragePostgres:
+		if cfg.PostgresDSN == "" {
+			err = fmt.Errorf("%w: %s", ErrStorageConfigMissing, StoragePostgres)
+			return
+		}
+		// - db: init and migrate
+		st.db, err = repository.NewPostgresDB(cfg.PostgresDSN)
+		if err != nil {
+			return
+		}
+		st.migrations = repository.PostgresMigrations
+		// - repository
+		st.rpCustomer = repository.NewCustomersPostgres(st.db)
+		st.rpProduct = repository.NewProductsPostgres(st.db)
+		st.rpInvoice = repository.NewInvoicesPostgres(st.db)
+		st.rpSale = repository.NewSalesPostgres(st.db)
+		st.rpAudit = repository.NewAuditPostgres(st.db)
+		st.rpExchangeRate = repository.NewExchangeRatesPostgres(st.db)
+		st.rpTaxRate = repository.NewTaxRatesPostgres(st.db)
+		st.rpPromotion = repository.NewPromotionsPostgres(st.db)
+		st.rpStock = repository.NewStocksPostgres(st.db)
+		st.rpStockAlert = repository.NewStockAlertsPostgres(st.db)
+		st.rpWebhook = repository.NewWebhooksPostgres(st.db)
+		st.rpCategory = repository.NewCategoriesPostgres(st.db)
+	case StorageSQLite:
+		if cfg.SQLitePath == "" {
+			err = fmt.Errorf("%w: %s", ErrStorageConfigMissing, StorageSQLite)
+			return
+		}
+		// - db: init and migrate
+		st.db, err = repository.NewSQLiteDB(cfg.SQLitePath)
+		if err != nil {
+			return
+		}
+		st.migrations = repository.SQLiteMigrations
+		// - repository
+		st.rpCustomer = repository.NewCustomersSQLite(st.db)
+		st.rpProduct = repository.NewProductsSQLite(st.db)
+		st.rpInvoice = repository.NewInvoicesSQLite(st.db)
+		st.rpSale = repository.NewSalesSQLite(st.db)
+		st.rpAudit = repository.NewAuditSQLite(st.db)
+		st.rpExchangeRate = repository.NewExchangeRatesSQLite(st.db)
+		st.rpTaxRate = repository.NewTaxRatesSQLite(st.db)
+		st.rpPromotion = repository.NewPromotionsSQLite(st.db)
+		st.rpStock = repository.NewStocksSQLite(st.db)
+		st.rpStockAlert = repository.NewStockAlertsSQLite(st.db)
+		st.rpWebhook = repository.NewWebhooksSQLite(st.db)
+		st.rpCategory = repository.NewCategoriesSQLite(st.db)
+	case StorageMemory:
+		db := repository.NewMemoryDB()
+		// - repository
+		st.rpCustomer = repository.NewCustomersMemory(db)
+		st.rpProduct = repository.NewProductsMemory(db)
+		st.rpInvoice = repository.NewInvoicesMemory(db)
+		st.rpSale = repository.NewSalesMemory(db)
+		st.rpAudit = repository.NewAuditMemory(db)
+		st.rpExchangeRate = repository.NewExchangeRatesMemory(db)
+		st.rpTaxRate = repository.NewTaxRatesMemory(db)
+		st.rpPromotion = repository.NewPromotionsMemory(db)
+		st.rpStock = repository.NewStocksMemory(db)
+		st.rpStockAlert = repository.NewStockAlertsMemory(db)
+		st.rpWebhook = repository.NewWebhooksMemory(db)
+		st.rpCategory = repository.NewCategoriesMemory(db)
+	default:
+		err = fmt.Errorf("%w: %s", ErrStorageDriverUnknown, cfg.Driver)
+		return
+	}
+
+	// - db: ping
+	if st.db != nil {
+		err = st.db.Ping()
+		if err != nil {
+			st.db.Close()
+			return
+		}
+	}
+
+	return
+}
+
+// Close closes the database connection, if any.
+func (s storage) Close() (err error) {
+	if s.db != nil {
+		err = s.db.Close()
+	}
+	return
+}
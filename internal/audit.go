@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// AuditActionCreate is the action of a created record.
+	AuditActionCreate = "create"
+	// AuditActionUpdate is the action of an updated record.
+	AuditActionUpdate = "update"
+	// AuditActionDelete is the action of a deleted record.
+	AuditActionDelete = "delete"
+
+	// AuditActorSystem is the actor of the changes made without an authenticated caller.
+	AuditActorSystem = "system"
+)
+
+// AuditRecordAttributes is the struct that represents the attributes of an audit record.
+type AuditRecordAttributes struct {
+	// Actor is the subject that made the change.
+	Actor string
+	// Timestamp is the time of the change, in UTC.
+	Timestamp time.Time
+	// Entity is the table of the changed record, e.g. "invoices".
+	Entity string
+	// EntityId is the id of the changed record.
+	EntityId int
+	// Action is one of the AuditAction constants.
+	Action string
+	// Before is the record before the change as JSON, nil for a created record.
+	Before json.RawMessage
+	// After is the record after the change as JSON, nil for a deleted record.
+	After json.RawMessage
+}
+
+// AuditRecord is the struct that represents a change of a record.
+type AuditRecord struct {
+	// Id is the unique identifier of the audit record.
+	Id int
+	// AuditRecordAttributes is the attributes of the audit record.
+	AuditRecordAttributes
+}
+
+// AuditFilter is the struct that represents the criteria to find audit records. Zero fields match every record.
+type AuditFilter struct {
+	// Entity is the table of the changed records.
+	Entity string
+	// EntityId is the id of the changed record.
+	EntityId int
+	// From is the inclusive lower bound of the timestamp.
+	From time.Time
+	// To is the exclusive upper bound of the timestamp.
+	To time.Time
+}
+
+// actorKey is the context key of the actor.
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor, the subject the changes made with it are audited as.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor carried by ctx, AuditActorSystem if there is none.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AuditActorSystem
+}
+
+// RepositoryAudit is the interface that wraps the basic methods that an audit repository should implement.
+// Audit records are written by the other repositories, in the transaction of the change.
+type RepositoryAudit interface {
+	// FindAll returns the audit records matching the filter, oldest first.
+	FindAll(ctx context.Context, f AuditFilter) (a []AuditRecord, err error)
+}
+
+// ServiceAudit is the interface that wraps the basic methods that an audit service should implement.
+type ServiceAudit interface {
+	// FindAll returns the audit records matching the filter, oldest first.
+	FindAll(ctx context.Context, f AuditFilter) (a []AuditRecord, err error)
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	// ErrCategoryNotFound is used when a category does not exist.
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryExists is used when a category of the same name already exists under the same parent.
+	ErrCategoryExists = errors.New("category exists")
+)
+
+// CategoryAttributes is the struct that represents the attributes of a category.
+type CategoryAttributes struct {
+	// Name is the name of the category, unique among the categories of its parent.
+	Name string
+	// ParentId is the parent category, 0 for a root category.
+	ParentId int
+}
+
+// Category is the struct that represents a category of products.
+type Category struct {
+	// Id is the unique identifier of the category.
+	Id int
+	// CategoryAttributes is the attributes of the category.
+	CategoryAttributes
+}
+
+// CategoryRevenue is the struct that represents the sales of the products of a category, not of its subcategories.
+type CategoryRevenue struct {
+	// CategoryId is the category id.
+	CategoryId int
+	// Units is the number of units sold.
+	Units int
+	// Revenue is the amount sold, quantity times unit price less the discounts, before taxes.
+	Revenue Money
+}
+
+// TopCategory is the struct that represents the sales of a category and of its subcategories.
+type TopCategory struct {
+	// Category is the category.
+	Category
+	// Units is the number of units sold.
+	Units int
+	// Revenue is the amount sold, before taxes.
+	Revenue Money
+	// Currency is the reporting currency the revenue is converted to.
+	Currency Currency
+}
+
+// Categories is a list of categories, the tree of the categories of products.
+type Categories []Category
+
+// Top returns the 5 children of the category parentId, the root categories if 0, with the highest revenue.
+// Each counts the sales of its subcategories too, and the categories without sales are left out.
+func (c Categories) Top(parentId int, revenue []CategoryRevenue, currency Currency) (t []TopCategory) {
+	parents := make(map[int]int, len(c))
+	for _, v := range c {
+		parents[v.Id] = v.ParentId
+	}
+
+	// - roll the revenue of every category up to its ancestor among the children of parentId
+	units := make(map[int]int)
+	amounts := make(map[int]Money)
+	for _, v := range revenue {
+		id, ok := c.childOf(parents, v.CategoryId, parentId)
+		if !ok {
+			continue
+		}
+		units[id] += v.Units
+		amounts[id] += v.Revenue
+	}
+
+	t = []TopCategory{}
+	for _, v := range c {
+		if v.ParentId != parentId || units[v.Id] == 0 {
+			continue
+		}
+		t = append(t, TopCategory{Category: v, Units: units[v.Id], Revenue: amounts[v.Id], Currency: currency})
+	}
+
+	// - order by revenue desc, ties by id
+	sort.Slice(t, func(i, j int) bool {
+		if t[i].Revenue != t[j].Revenue {
+			return t[i].Revenue > t[j].Revenue
+		}
+		return t[i].Id < t[j].Id
+	})
+	if len(t) > 5 {
+		t = t[:5]
+	}
+	return
+}
+
+// childOf returns the ancestor of the category id, itself included, whose parent is parentId, by the parents of
+// the categories. It is false if id is not under parentId. The depth is bounded, so a cycle cannot loop forever.
+func (c Categories) childOf(parents map[int]int, id, parentId int) (child int, ok bool) {
+	for depth := 0; depth <= len(parents); depth++ {
+		parent, exists := parents[id]
+		if !exists {
+			return 0, false
+		}
+		if parent == parentId {
+			return id, true
+		}
+		if parent == 0 {
+			return 0, false
+		}
+		id = parent
+	}
+	return 0, false
+}
@@ -0,0 +1,15 @@
+package internal
+
+import "context"
+
+// RepositoryCategory is the interface that wraps the basic methods that a category repository should implement.
+type RepositoryCategory interface {
+	// FindAll returns all categories saved in the database, by id.
+	FindAll(ctx context.Context) (c []Category, err error)
+	// Save saves a category into the database. It fails with ErrCategoryNotFound if its parent does not exist,
+	// and with ErrCategoryExists if its parent already has a category of the same name.
+	Save(ctx context.Context, c *Category) (err error)
+	// GetCategoryRevenue returns the sales of the products of every category with sales, by category id,
+	// converted to currency at the date of their invoices. It fails with ErrExchangeRateNotFound.
+	GetCategoryRevenue(ctx context.Context, currency Currency) (r []CategoryRevenue, err error)
+}
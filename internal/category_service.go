@@ -0,0 +1,16 @@
+package internal
+
+import "context"
+
+// ServiceCategory is the interface that wraps the basic methods that a category service should implement.
+type ServiceCategory interface {
+	// FindAll returns all categories.
+	FindAll(ctx context.Context) (c []Category, err error)
+	// Save saves a category. It fails with ErrCategoryNotFound if its parent does not exist,
+	// and with ErrCategoryExists if its parent already has a category of the same name.
+	Save(ctx context.Context, c *Category) (err error)
+	// GetTopCategories returns the 5 children of the category parentId, the root categories if 0, with the highest
+	// revenue converted to currency, each counting its subcategories.
+	// It fails with ErrCategoryNotFound and ErrExchangeRateNotFound.
+	GetTopCategories(ctx context.Context, currency Currency, parentId int) (t []TopCategory, err error)
+}
@@ -0,0 +1,77 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Categories.Top method
+func TestCategoriesTop(t *testing.T) {
+	// - Food (1) > Fruit (2) > Citrus (3), Food (1) > Bakery (4), Drinks (5)
+	categories := internal.Categories{
+		{Id: 1, CategoryAttributes: internal.CategoryAttributes{Name: "Food"}},
+		{Id: 2, CategoryAttributes: internal.CategoryAttributes{Name: "Fruit", ParentId: 1}},
+		{Id: 3, CategoryAttributes: internal.CategoryAttributes{Name: "Citrus", ParentId: 2}},
+		{Id: 4, CategoryAttributes: internal.CategoryAttributes{Name: "Bakery", ParentId: 1}},
+		{Id: 5, CategoryAttributes: internal.CategoryAttributes{Name: "Drinks"}},
+	}
+	revenue := []internal.CategoryRevenue{
+		{CategoryId: 2, Units: 1, Revenue: internal.MustParseMoney("10")},
+		{CategoryId: 3, Units: 4, Revenue: internal.MustParseMoney("20")},
+		{CategoryId: 5, Units: 2, Revenue: internal.MustParseMoney("30")},
+		{CategoryId: 9, Units: 1, Revenue: internal.MustParseMoney("100")},
+	}
+
+	testCases := []struct {
+		name     string
+		parentId int
+		expected []internal.TopCategory
+	}{
+		{
+			name:     "root categories roll up their subcategories",
+			parentId: 0,
+			expected: []internal.TopCategory{
+				{Category: categories[0], Units: 5, Revenue: internal.MustParseMoney("30"), Currency: "USD"},
+				{Category: categories[4], Units: 2, Revenue: internal.MustParseMoney("30"), Currency: "USD"},
+			},
+		}, {
+			name:     "subcategories without sales are left out",
+			parentId: 1,
+			expected: []internal.TopCategory{
+				{Category: categories[1], Units: 5, Revenue: internal.MustParseMoney("30"), Currency: "USD"},
+			},
+		}, {
+			name:     "leaf category",
+			parentId: 3,
+			expected: []internal.TopCategory{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			top := categories.Top(testCase.parentId, revenue, "USD")
+
+			// assert
+			require.Equal(t, testCase.expected, top)
+		})
+	}
+}
+
+// Tests for Categories.Top method, limited to 5 categories by revenue
+func TestCategoriesTopLimit(t *testing.T) {
+	var categories internal.Categories
+	var revenue []internal.CategoryRevenue
+	for id := 1; id <= 7; id++ {
+		categories = append(categories, internal.Category{Id: id})
+		revenue = append(revenue, internal.CategoryRevenue{CategoryId: id, Units: 1, Revenue: internal.Money(id * 100)})
+	}
+
+	top := categories.Top(0, revenue, "USD")
+
+	require.Len(t, top, 5)
+	require.Equal(t, 7, top[0].Id)
+	require.Equal(t, 3, top[4].Id)
+}
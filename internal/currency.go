@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyInvalid is used when a currency code is not an ISO 4217 code.
+	ErrCurrencyInvalid = errors.New("invalid currency")
+	// ErrCurrencyMismatch is used when a sale line is not in the currency of its invoice.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrRateInvalid is used when a rate cannot be parsed.
+	ErrRateInvalid = errors.New("invalid rate")
+)
+
+// CurrencyDefault is the currency of the products and invoices saved without one, and of the reports.
+const CurrencyDefault Currency = "USD"
+
+// Currency is an ISO 4217 alphabetic code, e.g. "USD".
+type Currency string
+
+// ParseCurrency parses a currency code, in any case, e.g. "eur".
+func ParseCurrency(s string) (c Currency, err error) {
+	c = Currency(strings.ToUpper(s))
+	if !c.IsValid() {
+		err = fmt.Errorf("%w: %q is not a three letter ISO 4217 code", ErrCurrencyInvalid, s)
+	}
+	return
+}
+
+// IsValid reports whether the code is made of three upper-case letters.
+func (c Currency) IsValid() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// OrDefault returns the currency, or CurrencyDefault if it is empty.
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return CurrencyDefault
+	}
+	return c
+}
+
+// rateScale is the number of units of a Rate in 1.
+const rateScale = 1_000_000
+
+// Rate is a factor in millionths, such as an exchange rate or a tax rate, so it multiplies amounts of money exactly.
+// It is stored in DECIMAL(18,6) columns and encoded in json as a number, e.g. 1.0825.
+type Rate int64
+
+// ParseRate parses a decimal rate, e.g. "1.0825", rounding it to six decimals.
+func ParseRate(s string) (r Rate, err error) {
+	v, ok := new(big.Rat).SetString(s)
+	if !ok {
+		err = fmt.Errorf("%w: %q", ErrRateInvalid, s)
+		return
+	}
+	units, ok := roundHalfEven(v, rateScale)
+	if !ok {
+		err = fmt.Errorf("%w: %q out of range", ErrRateInvalid, s)
+		return
+	}
+	return Rate(units), nil
+}
+
+// MustParseRate is like ParseRate but panics if s cannot be parsed. It is meant for constants.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// String returns the rate with the decimals it needs, e.g. "1.0825".
+func (r Rate) String() string {
+	s := strings.TrimRight(big.NewRat(int64(r), rateScale).FloatString(6), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON encodes the rate as a number.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON decodes the rate from a number or a string holding a number.
+func (r *Rate) UnmarshalJSON(data []byte) (err error) {
+	s := string(data)
+	if s == "null" {
+		return
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	*r, err = ParseRate(s)
+	if err != nil {
+		// - a type error lets the decoder report the field
+		return &json.UnmarshalTypeError{Value: string(data), Type: reflect.TypeOf(*r)}
+	}
+	return
+}
+
+// Scan reads the rate from a database value: DECIMAL columns come as text, sqlite ones as numbers.
+func (r *Rate) Scan(src any) (err error) {
+	switch v := src.(type) {
+	case int64:
+		*r = Rate(v * rateScale)
+	case float64:
+		*r, err = ParseRate(strconv.FormatFloat(v, 'f', -1, 64))
+	case []byte:
+		*r, err = ParseRate(string(v))
+	case string:
+		*r, err = ParseRate(v)
+	default:
+		err = fmt.Errorf("%w: cannot scan %T", ErrRateInvalid, src)
+	}
+	return
+}
+
+// Value writes the rate as a decimal string, exact in DECIMAL columns.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Converted is the exact sum of amounts multiplied by rates, e.g. converted at exchange rates.
+// It is rounded to the cent only when read, as a database rounds a SUM of converted DECIMAL amounts.
+// The zero value is an empty sum.
+type Converted struct {
+	// sum is in millionths of a cent.
+	sum big.Int
+}
+
+// Add adds m converted at r to the sum.
+func (c *Converted) Add(m Money, r Rate) {
+	c.sum.Add(&c.sum, new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(r))))
+}
+
+// Money returns the sum rounded to the cent, half to even.
+func (c *Converted) Money() Money {
+	cents, _ := roundHalfEven(new(big.Rat).SetFrac(&c.sum, big.NewInt(rateScale*100)), 100)
+	return Money(cents)
+}
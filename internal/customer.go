@@ -1,5 +1,66 @@
 package internal
 
+import "errors"
+
+var (
+	// ErrCustomerNotFound is used when a customer does not exist.
+	ErrCustomerNotFound = errors.New("customer not found")
+	// ErrCustomerEmailExists is used when another customer has the same email.
+	ErrCustomerEmailExists = errors.New("customer email exists")
+)
+
+// CustomerCondition is the lifecycle status of a customer. Tax rates and promotions apply by condition,
+// and the invoices total report groups by it.
+type CustomerCondition int
+
+const (
+	// CustomerConditionInactive is the condition of a customer that no longer buys, kept for the history.
+	CustomerConditionInactive CustomerCondition = 0
+	// CustomerConditionActive is the condition of a customer that buys, the default of a new customer.
+	CustomerConditionActive CustomerCondition = 1
+)
+
+// Valid reports whether c is one of the CustomerCondition constants.
+func (c CustomerCondition) Valid() bool {
+	return c == CustomerConditionInactive || c == CustomerConditionActive
+}
+
+// String returns the name of the condition, e.g. "active".
+func (c CustomerCondition) String() string {
+	switch c {
+	case CustomerConditionInactive:
+		return "inactive"
+	case CustomerConditionActive:
+		return "active"
+	}
+	return "unknown"
+}
+
+const (
+	// AddressKindBilling is the kind of the address invoices are billed to.
+	AddressKindBilling = "billing"
+	// AddressKindShipping is the kind of the address sales are shipped to.
+	AddressKindShipping = "shipping"
+)
+
+// CustomerAddress is the struct that represents an address of a customer, one of each kind at most.
+type CustomerAddress struct {
+	// Kind is one of the AddressKind constants.
+	Kind string
+	// Line1 is the street and number.
+	Line1 string
+	// Line2 is the apartment, suite or floor, "" if none.
+	Line2 string
+	// City is the city.
+	City string
+	// State is the state or province, "" if none.
+	State string
+	// PostalCode is the postal code, "" if none.
+	PostalCode string
+	// Country is the ISO 3166-1 alpha-2 code of the country, e.g. "AR".
+	Country string
+}
+
 // CustomerAttributes is the struct that represents the attributes of a customer.
 type CustomerAttributes struct {
 	// FirstName is the first name of the customer.
@@ -7,7 +68,17 @@ type CustomerAttributes struct {
 	// LastName is the last name of the customer.
 	LastName string
 	// Condition is the condition of the customer.
-	Condition int
+	Condition CustomerCondition
+	// Email is the email of the customer, unique among the customers, "" if unknown.
+	Email string
+	// Phone is the phone number of the customer, "" if unknown.
+	Phone string
+	// Addresses is the billing and shipping addresses of the customer, in that order.
+	Addresses []CustomerAddress
+	// CreatedAt is the UTC datetime the customer was saved, set by the repository.
+	CreatedAt string
+	// UpdatedAt is the UTC datetime the customer was last saved or updated, set by the repository.
+	UpdatedAt string
 }
 
 // Customer is the struct that represents a customer.
@@ -22,5 +93,10 @@ type TopCustomer struct {
 	Id        int
 	FirstName string
 	LastName  string
-	Amount    float64
+	// Net is the invoiced amount before taxes.
+	Net Money
+	// Amount is the invoiced amount including taxes.
+	Amount Money
+	// Currency is the reporting currency the amount is converted to.
+	Currency Currency
 }
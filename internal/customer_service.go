@@ -1,10 +1,20 @@
 package internal
 
+import "context"
+
 // ServiceCustomer is the interface that wraps the basic methods that a customer service should implement.
 type ServiceCustomer interface {
-	// FindAll returns all customers
-	FindAll() (c []Customer, err error)
-	GetTopCustomers() ([]TopCustomer, error)
-	// Save saves a customer
-	Save(c *Customer) (err error)
+	// FindAll returns all customers, with their addresses
+	FindAll(ctx context.Context) (c []Customer, err error)
+	// GetTopCustomers returns the 5 customers with the highest invoiced amount, converted to currency
+	// at the rate effective at the date of each invoice. It fails with ErrExchangeRateNotFound if a rate is missing.
+	GetTopCustomers(ctx context.Context, currency Currency) ([]TopCustomer, error)
+	// Search returns the page of q of the customers whose first or last name match its text, the best ranked first.
+	Search(ctx context.Context, q SearchQuery) (m CustomerMatches, err error)
+	// Save saves a customer with its addresses, setting its creation and update datetimes.
+	// It fails with ErrCustomerEmailExists if another customer has its email.
+	Save(ctx context.Context, c *Customer) (err error)
+	// Update replaces the attributes and the addresses of the customer c.Id, setting its update datetime
+	// and filling its creation datetime. It fails with ErrCustomerNotFound and ErrCustomerEmailExists.
+	Update(ctx context.Context, c *Customer) (err error)
 }
@@ -0,0 +1,31 @@
+package internal_test
+
+import (
+	"app/internal"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for CustomerCondition type
+func TestCustomerCondition(t *testing.T) {
+	testCases := []struct {
+		name        string
+		condition   internal.CustomerCondition
+		expectValid bool
+		expectName  string
+	}{
+		{name: "inactive", condition: 0, expectValid: true, expectName: "inactive"},
+		{name: "active", condition: 1, expectValid: true, expectName: "active"},
+		{name: "unknown", condition: 2, expectValid: false, expectName: "unknown"},
+		{name: "negative", condition: -1, expectValid: false, expectName: "unknown"},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			require.Equal(t, testCase.expectValid, testCase.condition.Valid())
+			require.Equal(t, testCase.expectName, testCase.condition.String())
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package internal
+
+import "errors"
+
+var (
+	// ErrExchangeRateNotFound is used when an amount cannot be converted because no exchange rate is effective at its date.
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	// ErrExchangeRateExists is used when an exchange rate is already effective from the same date.
+	ErrExchangeRateExists = errors.New("exchange rate already exists")
+)
+
+// ExchangeRateAttributes is the struct that represents the attributes of an exchange rate.
+type ExchangeRateAttributes struct {
+	// From is the currency converted from.
+	From Currency
+	// To is the currency converted to.
+	To Currency
+	// Rate is the amount of To worth one unit of From.
+	Rate Rate
+	// EffectiveDate is the date, YYYY-MM-DD, from which the rate applies, until the next rate of the same currencies.
+	EffectiveDate string
+}
+
+// ExchangeRate is the struct that represents an exchange rate.
+type ExchangeRate struct {
+	// Id is the unique identifier of the exchange rate.
+	Id int
+	// ExchangeRateAttributes is the attributes of the exchange rate.
+	ExchangeRateAttributes
+}
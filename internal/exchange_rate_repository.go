@@ -0,0 +1,12 @@
+package internal
+
+import "context"
+
+// RepositoryExchangeRate is the interface that wraps the basic methods that an exchange rate repository should implement.
+type RepositoryExchangeRate interface {
+	// FindAll returns all exchange rates saved in the database.
+	FindAll(ctx context.Context) (e []ExchangeRate, err error)
+	// Save saves an exchange rate into the database. It fails with ErrExchangeRateExists
+	// if a rate of the same currencies is effective from the same date.
+	Save(ctx context.Context, e *ExchangeRate) (err error)
+}
@@ -0,0 +1,11 @@
+package internal
+
+import "context"
+
+// ServiceExchangeRate is the interface that wraps the basic methods that an exchange rate service should implement.
+type ServiceExchangeRate interface {
+	// FindAll returns all exchange rates.
+	FindAll(ctx context.Context) (e []ExchangeRate, err error)
+	// Save saves an exchange rate.
+	Save(ctx context.Context, e *ExchangeRate) (err error)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/response"
+)
+
+// NewAuditDefault returns a new AuditDefault
+func NewAuditDefault(sv internal.ServiceAudit) *AuditDefault {
+	return &AuditDefault{sv: sv}
+}
+
+// AuditDefault is a struct that returns the audit handlers
+type AuditDefault struct {
+	// sv is the audit's service
+	sv internal.ServiceAudit
+}
+
+// AuditRecordJSON is a struct that represents an audit record in JSON format
+type AuditRecordJSON struct {
+	Id        int             `json:"id"`
+	Actor     string          `json:"actor"`
+	Timestamp time.Time       `json:"timestamp"`
+	Entity    string          `json:"entity"`
+	EntityId  int             `json:"entity_id"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+}
+
+// GetAll returns the audit records matching the query parameters entity, id, from and to.
+// Times are RFC 3339 timestamps or dates, to is exclusive.
+func (h *AuditDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		f, err := auditFilter(r.URL.Query())
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing audit filter", "error", err)
+			var fieldErr *response.FieldError
+			errors.As(err, &fieldErr)
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidParameter, err.Error(), *fieldErr)
+			return
+		}
+
+		// process
+		a, err := h.sv.FindAll(r.Context(), f)
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting audit records", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting audit records")
+			return
+		}
+
+		// response
+		// - serialize
+		arJSON := make([]AuditRecordJSON, len(a))
+		for ix, v := range a {
+			arJSON[ix] = AuditRecordJSON{
+				Id:        v.Id,
+				Actor:     v.Actor,
+				Timestamp: v.Timestamp,
+				Entity:    v.Entity,
+				EntityId:  v.EntityId,
+				Action:    v.Action,
+				Before:    v.Before,
+				After:     v.After,
+			}
+		}
+		response.OK(w, "audit records found", arJSON)
+	}
+}
+
+// errAuditFilter is used when a query parameter of the audit filter is invalid.
+var errAuditFilter = errors.New("invalid audit filter")
+
+// auditFilter parses the audit filter from the query parameters. Errors wrap the *response.FieldError of the parameter.
+func auditFilter(q url.Values) (f internal.AuditFilter, err error) {
+	f.Entity = q.Get("entity")
+
+	if v := q.Get("id"); v != "" {
+		f.EntityId, err = strconv.Atoi(v)
+		if err != nil || f.EntityId <= 0 {
+			return f, fmt.Errorf("%w: %w", errAuditFilter, &response.FieldError{Field: "id", Message: "must be a positive integer"})
+		}
+	}
+	if f.From, err = parseAuditTime(q.Get("from")); err != nil {
+		return f, fmt.Errorf("%w: %w", errAuditFilter, &response.FieldError{Field: "from", Message: err.Error()})
+	}
+	if f.To, err = parseAuditTime(q.Get("to")); err != nil {
+		return f, fmt.Errorf("%w: %w", errAuditFilter, &response.FieldError{Field: "to", Message: err.Error()})
+	}
+	return
+}
+
+// parseAuditTime parses an RFC 3339 timestamp or a date. An empty string is the zero time.
+func parseAuditTime(s string) (t time.Time, err error) {
+	if s == "" {
+		return
+	}
+	if t, err = time.Parse(time.RFC3339, s); err == nil {
+		return
+	}
+	t, err = time.Parse(time.DateOnly, s)
+	if err != nil {
+		err = fmt.Errorf("%q is not an RFC 3339 timestamp or a date", s)
+	}
+	return
+}
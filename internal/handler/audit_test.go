@@ -0,0 +1,61 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAudit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectCode    int
+		expectRecords int
+		expectBody    string
+	}{
+		{name: "all records", query: "", expectCode: http.StatusOK, expectRecords: 3},
+		{name: "by entity and id", query: "?entity=customers&id=2", expectCode: http.StatusOK, expectRecords: 1},
+		{name: "by time range", query: "?from=2000-01-01&to=2000-01-02T00:00:00Z", expectCode: http.StatusOK, expectRecords: 0},
+		{name: "invalid id", query: "?id=abc", expectCode: http.StatusBadRequest, expectBody: `{"type":"urn:app:problem:invalid_parameter","title":"Bad Request","status":400,"code":"invalid_parameter","detail":"invalid audit filter: id: must be a positive integer","errors":[{"field":"id","message":"must be a positive integer"}]}`},
+		{name: "invalid from", query: "?from=yesterday", expectCode: http.StatusBadRequest, expectBody: `{"type":"urn:app:problem:invalid_parameter","title":"Bad Request","status":400,"code":"invalid_parameter","detail":"invalid audit filter: from: \"yesterday\" is not an RFC 3339 timestamp or a date","errors":[{"field":"from","message":"\"yesterday\" is not an RFC 3339 timestamp or a date"}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			db := repository.NewMemoryDB()
+			rpCustomer := repository.NewCustomersMemory(db)
+			for ix := 0; ix < 2; ix++ {
+				require.NoError(t, rpCustomer.Save(context.Background(), &internal.Customer{}))
+			}
+			require.NoError(t, repository.NewProductsMemory(db).Save(context.Background(), &internal.Product{}))
+			hd := handler.NewAuditDefault(service.NewAuditDefault(repository.NewAuditMemory(db)))
+			req := httptest.NewRequest(http.MethodGet, "/audit"+tc.query, nil)
+			res := httptest.NewRecorder()
+
+			// act
+			hd.GetAll()(res, req)
+
+			// assert
+			require.Equal(t, tc.expectCode, res.Code)
+			if tc.expectBody != "" {
+				require.JSONEq(t, tc.expectBody, res.Body.String())
+				return
+			}
+			var body struct {
+				Data []handler.AuditRecordJSON `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+			require.Len(t, body.Data, tc.expectRecords)
+		})
+	}
+}
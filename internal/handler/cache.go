@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"app/platform/cache"
+	"app/platform/web/response"
+)
+
+// NewCacheDefault returns a new CacheDefault
+func NewCacheDefault(c cache.Cache) *CacheDefault {
+	return &CacheDefault{c: c}
+}
+
+// CacheDefault is a struct that returns the cache handlers
+type CacheDefault struct {
+	// c is the cache
+	c cache.Cache
+}
+
+// CacheStatsJSON is a struct that represents the cache stats in JSON format
+type CacheStatsJSON struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// GetStats returns the usage counters of the cache
+func (h *CacheDefault) GetStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		st := h.c.Stats()
+
+		response.OK(w, "cache stats found", CacheStatsJSON{
+			Hits:      st.Hits,
+			Misses:    st.Misses,
+			Evictions: st.Evictions,
+			Entries:   st.Entries,
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/request"
+	"app/platform/web/response"
+)
+
+// categoryNameMaxLen is the maximum length of the name of a category, the one of its column.
+const categoryNameMaxLen = 45
+
+// NewCategoriesDefault returns a new CategoriesDefault
+func NewCategoriesDefault(sv internal.ServiceCategory) *CategoriesDefault {
+	return &CategoriesDefault{sv: sv}
+}
+
+// CategoriesDefault is a struct that returns the category handlers
+type CategoriesDefault struct {
+	// sv is the category's service
+	sv internal.ServiceCategory
+}
+
+// CategoryJSON is a struct that represents a category in JSON format
+type CategoryJSON struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentId *int   `json:"parent_id"`
+}
+
+// categoryJSON serializes the category c, with a null parent if it is a root category.
+func categoryJSON(c internal.Category) CategoryJSON {
+	ct := CategoryJSON{Id: c.Id, Name: c.Name}
+	if c.ParentId != 0 {
+		parentId := c.ParentId
+		ct.ParentId = &parentId
+	}
+	return ct
+}
+
+// GetAll returns all categories
+func (h *CategoriesDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		c, err := h.sv.FindAll(r.Context())
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting categories", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting categories")
+			return
+		}
+
+		// response
+		// - serialize
+		cJSON := make([]CategoryJSON, len(c))
+		for ix, v := range c {
+			cJSON[ix] = categoryJSON(v)
+		}
+		response.OK(w, "categories found", cJSON)
+	}
+}
+
+// RequestBodyCategory is a struct that represents the request body for a category
+type RequestBodyCategory struct {
+	Name     string `json:"name"`
+	ParentId *int   `json:"parent_id"`
+}
+
+// Create creates a new category
+func (h *CategoriesDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - body
+		var reqBody RequestBodyCategory
+		err := request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		// - validate
+		c, fields := category(reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid category", fields...)
+			return
+		}
+		// - save
+		err = h.sv.Save(r.Context(), &c)
+		if errors.Is(err, internal.ErrCategoryExists) {
+			response.ErrorCode(w, http.StatusConflict, response.CodeConflict, err.Error())
+			return
+		}
+		if errors.Is(err, internal.ErrCategoryNotFound) {
+			logger.FromContext(r.Context()).Debug("error saving category", "error", err)
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid category",
+				response.FieldError{Field: "parent_id", Message: "must be an existing category"})
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving category", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error saving category")
+			return
+		}
+
+		// response
+		// - serialize
+		response.Created(w, "category created", categoryJSON(c))
+	}
+}
+
+// category validates the request body of a category, returning the errors of its invalid fields.
+func category(reqBody RequestBodyCategory) (c internal.Category, fields []response.FieldError) {
+	c.Name = strings.TrimSpace(reqBody.Name)
+	if c.Name == "" || utf8.RuneCountInString(c.Name) > categoryNameMaxLen {
+		fields = append(fields, response.FieldError{Field: "name", Message: "must be between 1 and 45 characters"})
+	}
+
+	if reqBody.ParentId != nil {
+		c.ParentId = *reqBody.ParentId
+		if c.ParentId <= 0 {
+			fields = append(fields, response.FieldError{Field: "parent_id", Message: "must be a positive integer"})
+		}
+	}
+	return
+}
+
+// TopCategoryJSON is a top category in JSON format.
+type TopCategoryJSON struct {
+	CategoryJSON
+	Units    int               `json:"units"`
+	Revenue  internal.Money    `json:"revenue"`
+	Currency internal.Currency `json:"currency"`
+}
+
+// GetTopCategories returns the top categories, children of the query parameter parent_id or the root categories,
+// with the revenue converted to the query parameter currency, USD by default
+func (h *CategoriesDefault) GetTopCategories() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currency, err := reportCurrency(r.URL.Query())
+		if err != nil {
+			writeReportCurrencyError(w, r, err)
+			return
+		}
+		var parentId int
+		if v := r.URL.Query().Get("parent_id"); v != "" {
+			parentId, err = strconv.Atoi(v)
+			if err != nil || parentId <= 0 {
+				response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidParameter, "invalid parent category id",
+					response.FieldError{Field: "parent_id", Message: "must be a positive integer"})
+				return
+			}
+		}
+
+		topCategories, err := h.sv.GetTopCategories(r.Context(), currency, parentId)
+		if errors.Is(err, internal.ErrCategoryNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			writeReportError(w, r, err)
+			return
+		}
+
+		data := make([]TopCategoryJSON, 0, len(topCategories))
+		for _, v := range topCategories {
+			data = append(data, TopCategoryJSON{
+				CategoryJSON: categoryJSON(v.Category),
+				Units:        v.Units,
+				Revenue:      v.Revenue,
+				Currency:     v.Currency,
+			})
+		}
+
+		response.OK(w, "top categories found", data)
+	}
+}
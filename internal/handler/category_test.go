@@ -0,0 +1,146 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategories(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success get categories",
+			method:     "GET",
+			path:       "/categories",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "categories found", "data": [
+				{"id": 1, "name": "Food", "parent_id": null},
+				{"id": 2, "name": "Fruit", "parent_id": 1},
+				{"id": 3, "name": "Drinks", "parent_id": null}
+			]}`,
+		}, {
+			name:       "success create category",
+			method:     "POST",
+			path:       "/categories",
+			body:       `{"name": "Vegetables", "parent_id": 1}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "category created", "data": {"id": 4, "name": "Vegetables", "parent_id": 1}}`,
+		}, {
+			name:       "category already exists",
+			method:     "POST",
+			path:       "/categories",
+			body:       `{"name": "Fruit", "parent_id": 1}`,
+			expectCode: http.StatusConflict,
+			expectBody: `{"type": "urn:app:problem:conflict", "title": "Conflict", "status": 409, "code": "conflict", "detail": "category exists: Fruit under 1"}`,
+		}, {
+			name:       "parent category not found",
+			method:     "POST",
+			path:       "/categories",
+			body:       `{"name": "Vegetables", "parent_id": 9}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid category", "errors": [{"field": "parent_id", "message": "must be an existing category"}]}`,
+		}, {
+			name:       "invalid fields",
+			method:     "POST",
+			path:       "/categories",
+			body:       `{"name": " ", "parent_id": 0}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid category", "errors": [
+				{"field": "name", "message": "must be between 1 and 45 characters"},
+				{"field": "parent_id", "message": "must be a positive integer"}
+			]}`,
+		}, {
+			name:       "success get top root categories",
+			method:     "GET",
+			path:       "/categories/top",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "top categories found", "data": [
+				{"id": 1, "name": "Food", "parent_id": null, "units": 2, "revenue": 20.00, "currency": "USD"},
+				{"id": 3, "name": "Drinks", "parent_id": null, "units": 3, "revenue": 15.00, "currency": "USD"}
+			]}`,
+		}, {
+			name:       "success get top subcategories",
+			method:     "GET",
+			path:       "/categories/top?parent_id=1",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "top categories found", "data": [
+				{"id": 2, "name": "Fruit", "parent_id": 1, "units": 2, "revenue": 20.00, "currency": "USD"}
+			]}`,
+		}, {
+			name:       "parent category of top categories not found",
+			method:     "GET",
+			path:       "/categories/top?parent_id=9",
+			expectCode: http.StatusNotFound,
+			expectBody: `{"type": "urn:app:problem:not_found", "title": "Not Found", "status": 404, "code": "not_found", "detail": "category not found: 9"}`,
+		}, {
+			name:       "invalid parent category id",
+			method:     "GET",
+			path:       "/categories/top?parent_id=abc",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type": "urn:app:problem:invalid_parameter", "title": "Bad Request", "status": 400, "code": "invalid_parameter", "detail": "invalid parent category id", "errors": [{"field": "parent_id", "message": "must be a positive integer"}]}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			ctx := context.Background()
+			cr := repository.NewCategoriesMemory(db)
+			for _, c := range []internal.CategoryAttributes{{Name: "Food"}, {Name: "Fruit", ParentId: 1}, {Name: "Drinks"}} {
+				err := cr.Save(ctx, &internal.Category{CategoryAttributes: c})
+				require.NoError(t, err)
+			}
+			// - 2 units of fruit for 20 and 3 units of drinks for 15
+			cu := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe", Condition: 1}}
+			err := repository.NewCustomersMemory(db).Save(ctx, &cu)
+			require.NoError(t, err)
+			i := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Datetime: "2021-01-01 00:00:00", CustomerId: cu.Id}}
+			err = repository.NewInvoicesMemory(db).Save(ctx, &i)
+			require.NoError(t, err)
+			pr := repository.NewProductsMemory(db)
+			sr := repository.NewSalesMemory(db)
+			for ix, p := range []internal.ProductAttributes{
+				{Description: "Apple", Price: internal.MustParseMoney("10"), CategoryId: 2},
+				{Description: "Water", Price: internal.MustParseMoney("5"), CategoryId: 3},
+			} {
+				pd := internal.Product{ProductAttributes: p}
+				err = pr.Save(internal.WithStockUntracked(ctx), &pd)
+				require.NoError(t, err)
+				err = sr.Save(ctx, &internal.Sale{SaleAttributes: internal.SaleAttributes{Quantity: ix + 2, ProductId: pd.Id, InvoiceId: i.Id}})
+				require.NoError(t, err)
+			}
+
+			h := handler.NewCategoriesDefault(service.NewCategoriesDefault(cr))
+			rt := chi.NewRouter()
+			rt.Get("/categories", h.GetAll())
+			rt.Post("/categories", h.Create())
+			rt.Get("/categories/top", h.GetTopCategories())
+
+			request := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			rt.ServeHTTP(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			require.JSONEq(t, testCase.expectBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/response"
+)
+
+// Problem codes of the currency errors.
+const (
+	CodeCurrencyMismatch     = "currency_mismatch"
+	CodeExchangeRateNotFound = "exchange_rate_not_found"
+)
+
+// errReportCurrency is used when the reporting currency is invalid.
+var errReportCurrency = errors.New("invalid reporting currency")
+
+// reportCurrency parses the reporting currency from the query parameter currency, internal.CurrencyDefault if missing.
+// Errors wrap the *response.FieldError of the parameter.
+func reportCurrency(q url.Values) (c internal.Currency, err error) {
+	v := q.Get("currency")
+	if v == "" {
+		return internal.CurrencyDefault, nil
+	}
+
+	c, err = internal.ParseCurrency(v)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", errReportCurrency, &response.FieldError{Field: errCurrencyField.Field, Message: errCurrencyField.Message})
+	}
+	return
+}
+
+// writeReportCurrencyError writes the error response of an invalid reporting currency.
+func writeReportCurrencyError(w http.ResponseWriter, r *http.Request, err error) {
+	logger.FromContext(r.Context()).Debug("error parsing reporting currency", "error", err)
+	var fieldErr *response.FieldError
+	errors.As(err, &fieldErr)
+	response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidParameter, err.Error(), *fieldErr)
+}
+
+// writeReportError writes the error response of a report: 422 if an amount cannot be converted, 500 otherwise.
+func writeReportError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, internal.ErrExchangeRateNotFound) {
+		logger.FromContext(r.Context()).Debug("error converting report", "error", err)
+		response.ErrorCode(w, http.StatusUnprocessableEntity, CodeExchangeRateNotFound, err.Error())
+		return
+	}
+
+	logger.FromContext(r.Context()).Error("internal server error", "error", err)
+	response.Error(w, http.StatusInternalServerError, "internal server error")
+}
+
+// errCurrencyField is the field error of an invalid currency in a request body.
+var errCurrencyField = response.FieldError{Field: "currency", Message: "must be a three letter ISO 4217 code"}
+
+// entityCurrency parses the currency of a product or an invoice, internal.CurrencyDefault if empty.
+func entityCurrency(s string) (c internal.Currency, err error) {
+	if s == "" {
+		return internal.CurrencyDefault, nil
+	}
+	return internal.ParseCurrency(s)
+}
@@ -1,14 +1,31 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"net/mail"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"app/internal"
+	"app/platform/logger"
 	"app/platform/web/request"
 	"app/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Maximum lengths of the attributes of a customer and its addresses, the ones of their columns.
+const (
+	customerNameMaxLen  = 45
+	customerEmailMaxLen = 100
+	customerPhoneMinLen = 7
+	customerPhoneMaxLen = 20
+	addressLineMaxLen   = 100
+	addressPlaceMaxLen  = 45
+	postalCodeMaxLen    = 20
 )
 
 // NewCustomersDefault returns a new CustomersDefault
@@ -22,12 +39,59 @@ type CustomersDefault struct {
 	sv internal.ServiceCustomer
 }
 
-// CustomerJSON is a struct that represents a customer in JSON format
+// CustomerJSON is a struct that represents a customer in JSON format.
+// Condition is 1 for an active customer and 0 for an inactive one.
 type CustomerJSON struct {
-	Id        int    `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Condition int    `json:"condition"`
+	Id        int                   `json:"id"`
+	FirstName string                `json:"first_name"`
+	LastName  string                `json:"last_name"`
+	Condition int                   `json:"condition"`
+	Email     *string               `json:"email"`
+	Phone     string                `json:"phone"`
+	Addresses []CustomerAddressJSON `json:"addresses"`
+	CreatedAt string                `json:"created_at"`
+	UpdatedAt string                `json:"updated_at"`
+}
+
+// CustomerAddressJSON is a struct that represents an address of a customer in JSON format
+type CustomerAddressJSON struct {
+	Kind       string `json:"kind"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// customerJSON serializes the customer c, with a null email if it has none.
+func customerJSON(c internal.Customer) CustomerJSON {
+	cs := CustomerJSON{
+		Id:        c.Id,
+		FirstName: c.FirstName,
+		LastName:  c.LastName,
+		Condition: int(c.Condition),
+		Phone:     c.Phone,
+		Addresses: make([]CustomerAddressJSON, len(c.Addresses)),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if c.Email != "" {
+		email := c.Email
+		cs.Email = &email
+	}
+	for ix, a := range c.Addresses {
+		cs.Addresses[ix] = CustomerAddressJSON{
+			Kind:       a.Kind,
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			State:      a.State,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		}
+	}
+	return cs
 }
 
 // GetAll returns all customers
@@ -37,9 +101,9 @@ func (h *CustomersDefault) GetAll() http.HandlerFunc {
 		// ...
 
 		// process
-		c, err := h.sv.FindAll()
+		c, err := h.sv.FindAll(r.Context())
 		if err != nil {
-			log.Println(err)
+			logger.FromContext(r.Context()).Error("error getting customers", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error getting customers")
 			return
 		}
@@ -48,25 +112,21 @@ func (h *CustomersDefault) GetAll() http.HandlerFunc {
 		// - serialize
 		csJSON := make([]CustomerJSON, len(c))
 		for ix, v := range c {
-			csJSON[ix] = CustomerJSON{
-				Id:        v.Id,
-				FirstName: v.FirstName,
-				LastName:  v.LastName,
-				Condition: v.Condition,
-			}
+			csJSON[ix] = customerJSON(v)
 		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "customers found",
-			"data":    csJSON,
-		})
+		response.OK(w, "customers found", csJSON)
 	}
 }
 
-// RequestBodyCustomer is a struct that represents the request body for a customer
+// RequestBodyCustomer is a struct that represents the request body for a customer.
+// The condition is active, 1, if omitted.
 type RequestBodyCustomer struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Condition int    `json:"condition"`
+	FirstName string                `json:"first_name"`
+	LastName  string                `json:"last_name"`
+	Condition *int                  `json:"condition"`
+	Email     string                `json:"email"`
+	Phone     string                `json:"phone"`
+	Addresses []CustomerAddressJSON `json:"addresses"`
 }
 
 // Create creates a new customer
@@ -77,70 +137,261 @@ func (h *CustomersDefault) Create() http.HandlerFunc {
 		var reqBody RequestBodyCustomer
 		err := request.JSON(r, &reqBody)
 		if err != nil {
-			response.Error(w, http.StatusBadRequest, "error deserializing request body")
+			logger.FromContext(r.Context()).Debug("error deserializing request body", "error", err)
+			response.RequestError(w, err)
 			return
 		}
 
 		// process
-		// - deserialize
-		c := internal.Customer{
-			CustomerAttributes: internal.CustomerAttributes{
-				FirstName: reqBody.FirstName,
-				LastName:  reqBody.LastName,
-				Condition: reqBody.Condition,
-			},
+		// - validate
+		c, fields := customer(reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid customer", fields...)
+			return
 		}
 		// - save
-		err = h.sv.Save(&c)
+		err = h.sv.Save(r.Context(), &c)
+		if errors.Is(err, internal.ErrCustomerEmailExists) {
+			response.ErrorCode(w, http.StatusConflict, response.CodeConflict, err.Error())
+			return
+		}
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving customer", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error saving customer")
 			return
 		}
 
 		// response
 		// - serialize
-		cs := CustomerJSON{
-			Id:        c.Id,
-			FirstName: c.FirstName,
-			LastName:  c.LastName,
-			Condition: c.Condition,
+		response.Created(w, "customer created", customerJSON(c))
+	}
+}
+
+// Update replaces the attributes and the addresses of a customer
+func (h *CustomersDefault) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || id <= 0 {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidParameter, "invalid customer id",
+				response.FieldError{Field: "id", Message: "must be a positive integer"})
+			return
+		}
+		// - body
+		var reqBody RequestBodyCustomer
+		err = request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error deserializing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		// - validate
+		c, fields := customer(reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid customer", fields...)
+			return
+		}
+		c.Id = id
+		// - update
+		err = h.sv.Update(r.Context(), &c)
+		if errors.Is(err, internal.ErrCustomerNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, internal.ErrCustomerEmailExists) {
+			response.ErrorCode(w, http.StatusConflict, response.CodeConflict, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error updating customer", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error updating customer")
+			return
+		}
+
+		// response
+		// - serialize
+		response.OK(w, "customer updated", customerJSON(c))
+	}
+}
+
+// customer validates the request body of a customer, returning the errors of its invalid fields.
+// The email is lowercased and the country codes uppercased.
+func customer(reqBody RequestBodyCustomer) (c internal.Customer, fields []response.FieldError) {
+	c.FirstName = strings.TrimSpace(reqBody.FirstName)
+	if c.FirstName == "" || utf8.RuneCountInString(c.FirstName) > customerNameMaxLen {
+		fields = append(fields, response.FieldError{Field: "first_name", Message: "must be between 1 and 45 characters"})
+	}
+	c.LastName = strings.TrimSpace(reqBody.LastName)
+	if c.LastName == "" || utf8.RuneCountInString(c.LastName) > customerNameMaxLen {
+		fields = append(fields, response.FieldError{Field: "last_name", Message: "must be between 1 and 45 characters"})
+	}
+
+	c.Condition = internal.CustomerConditionActive
+	if reqBody.Condition != nil {
+		c.Condition = internal.CustomerCondition(*reqBody.Condition)
+		if !c.Condition.Valid() {
+			fields = append(fields, response.FieldError{Field: "condition", Message: "must be 1, active, or 0, inactive"})
+		}
+	}
+
+	c.Email = strings.ToLower(strings.TrimSpace(reqBody.Email))
+	if c.Email != "" {
+		addr, err := mail.ParseAddress(c.Email)
+		if err != nil || addr.Address != c.Email || len(c.Email) > customerEmailMaxLen {
+			fields = append(fields, response.FieldError{Field: "email", Message: "must be an email address of at most 100 characters"})
+		}
+	}
+
+	c.Phone = strings.TrimSpace(reqBody.Phone)
+	if c.Phone != "" && !validPhone(c.Phone) {
+		fields = append(fields, response.FieldError{Field: "phone", Message: "must be 7 to 20 digits, spaces and + - ( )"})
+	}
+
+	kinds := make(map[string]bool)
+	for ix, v := range reqBody.Addresses {
+		a, addressFields := customerAddress(v, fmt.Sprintf("addresses[%d].", ix))
+		fields = append(fields, addressFields...)
+		if kinds[a.Kind] {
+			fields = append(fields, response.FieldError{Field: fmt.Sprintf("addresses[%d].kind", ix), Message: "must not repeat the kind of another address"})
+		}
+		kinds[a.Kind] = true
+		c.Addresses = append(c.Addresses, a)
+	}
+	return
+}
+
+// customerAddress validates an address of the request body of a customer, returning the errors of its invalid fields,
+// named after prefix.
+func customerAddress(v CustomerAddressJSON, prefix string) (a internal.CustomerAddress, fields []response.FieldError) {
+	a = internal.CustomerAddress{
+		Kind:       v.Kind,
+		Line1:      strings.TrimSpace(v.Line1),
+		Line2:      strings.TrimSpace(v.Line2),
+		City:       strings.TrimSpace(v.City),
+		State:      strings.TrimSpace(v.State),
+		PostalCode: strings.TrimSpace(v.PostalCode),
+		Country:    strings.ToUpper(v.Country),
+	}
+	if a.Kind != internal.AddressKindBilling && a.Kind != internal.AddressKindShipping {
+		fields = append(fields, response.FieldError{Field: prefix + "kind", Message: "must be billing or shipping"})
+	}
+	if a.Line1 == "" || utf8.RuneCountInString(a.Line1) > addressLineMaxLen {
+		fields = append(fields, response.FieldError{Field: prefix + "line1", Message: "must be between 1 and 100 characters"})
+	}
+	if utf8.RuneCountInString(a.Line2) > addressLineMaxLen {
+		fields = append(fields, response.FieldError{Field: prefix + "line2", Message: "must be at most 100 characters"})
+	}
+	if a.City == "" || utf8.RuneCountInString(a.City) > addressPlaceMaxLen {
+		fields = append(fields, response.FieldError{Field: prefix + "city", Message: "must be between 1 and 45 characters"})
+	}
+	if utf8.RuneCountInString(a.State) > addressPlaceMaxLen {
+		fields = append(fields, response.FieldError{Field: prefix + "state", Message: "must be at most 45 characters"})
+	}
+	if utf8.RuneCountInString(a.PostalCode) > postalCodeMaxLen {
+		fields = append(fields, response.FieldError{Field: prefix + "postal_code", Message: "must be at most 20 characters"})
+	}
+	if len(a.Country) != 2 || strings.Trim(a.Country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		fields = append(fields, response.FieldError{Field: prefix + "country", Message: "must be a two letter ISO 3166-1 code"})
+	}
+	return
+}
+
+// validPhone reports whether s is a phone number: 7 to 20 digits, spaces and + - ( ), with a digit at least.
+func validPhone(s string) bool {
+	if len(s) < customerPhoneMinLen || len(s) > customerPhoneMaxLen {
+		return false
+	}
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune(" +-()", r):
+		default:
+			return false
 		}
-		response.JSON(w, http.StatusCreated, map[string]any{
-			"message": "customer created",
-			"data":    cs,
-		})
 	}
+	return digits > 0
 }
 
+// TopCustomerJSON is a top customer in JSON format.
+// Amount is the gross figure, kept for the clients that predate Net and Gross.
 type TopCustomerJSON struct {
-	Id        int     `json:"id"`
-	FirstName string  `json:"first_name"`
-	LastName  string  `json:"last_name"`
-	Amount    float64 `json:"amount"`
+	Id        int               `json:"id"`
+	FirstName string            `json:"first_name"`
+	LastName  string            `json:"last_name"`
+	Net       internal.Money    `json:"net"`
+	Gross     internal.Money    `json:"gross"`
+	Amount    internal.Money    `json:"amount"`
+	Currency  internal.Currency `json:"currency"`
 }
 
+// GetTopCustomers returns the top customers, with the amounts converted to the query parameter currency, USD by default
 func (h *CustomersDefault) GetTopCustomers() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		topCustomers, err := h.sv.GetTopCustomers()
+		currency, err := reportCurrency(r.URL.Query())
 		if err != nil {
-			response.Error(w, http.StatusInternalServerError, "internal server error")
+			writeReportCurrencyError(w, r, err)
+			return
+		}
+
+		topCustomers, err := h.sv.GetTopCustomers(r.Context(), currency)
+		if err != nil {
+			writeReportError(w, r, err)
 			return
 		}
 
 		data := make([]TopCustomerJSON, 0, len(topCustomers))
 		for _, v := range topCustomers {
-			totalRounded, _ := strconv.ParseFloat(fmt.Sprintf("%.2f", v.Amount), 64)
-
 			data = append(data, TopCustomerJSON{
 				Id:        v.Id,
 				FirstName: v.FirstName,
 				LastName:  v.LastName,
-				Amount:    totalRounded,
+				Net:       v.Net,
+				Gross:     v.Amount,
+				Amount:    v.Amount,
+				Currency:  v.Currency,
 			})
 		}
 
-		response.JSON(w, http.StatusOK, map[string]any{
-			"data": data,
-		})
+		response.OK(w, "top customers found", data)
+	}
+}
+
+// CustomerMatchJSON is a struct that represents a customer found by a search in JSON format
+type CustomerMatchJSON struct {
+	CustomerJSON
+	Score float64 `json:"score"`
+}
+
+// Search returns the page of the query parameters limit and offset of the customers whose first or last name
+// match the query parameter q, the best ranked first
+func (h *CustomersDefault) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := searchQuery(r.URL.Query())
+		if err != nil {
+			writeSearchQueryError(w, r, err)
+			return
+		}
+
+		// process
+		m, err := h.sv.Search(r.Context(), q)
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error searching customers", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error searching customers")
+			return
+		}
+
+		// response
+		// - serialize
+		data := SearchPageJSON[CustomerMatchJSON]{Total: m.Total, Limit: q.Limit, Offset: q.Offset, Results: make([]CustomerMatchJSON, len(m.Matches))}
+		for ix, v := range m.Matches {
+			data.Results[ix] = CustomerMatchJSON{CustomerJSON: customerJSON(v.Customer), Score: v.Score}
+		}
+		response.OK(w, "customers found", data)
 	}
 }
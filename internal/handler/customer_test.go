@@ -5,34 +5,23 @@ import (
 	"app/internal/handler"
 	"app/internal/repository"
 	"app/internal/service"
-	"database/sql"
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/DATA-DOG/go-txdb"
-	"github.com/go-sql-driver/mysql"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
 )
 
-func init() {
-	cfg := mysql.Config{
-		User:   "root",
-		Passwd: "123",
-		Net:    "tcp",
-		Addr:   "localhost:3306",
-		DBName: "fantasy_products_test",
-	}
-
-	txdb.Register("txdb", "mysql", cfg.FormatDSN())
-}
-
 func TestGetTopCustomers(t *testing.T) {
 	testCases := []struct {
 		name       string
 		customers  []internal.CustomerAttributes
 		invoices   []internal.InvoiceAttributes
+		query      string
 		expectCode int
 		expectBody string
 	}{
@@ -50,28 +39,35 @@ func TestGetTopCustomers(t *testing.T) {
 			invoices: []internal.InvoiceAttributes{
 				{
 					Datetime:   "2022-05-15 00:00:00",
-					Total:      32.00,
+					Total:      internal.MustParseMoney("32.00"),
 					CustomerId: 1,
 				}, {
 					Datetime:   "2022-05-15 00:00:00",
-					Total:      10.00,
+					Total:      internal.MustParseMoney("10.00"),
 					CustomerId: 2,
 				},
 			},
 			expectCode: http.StatusOK,
 			expectBody: `{
+				"message": "top customers found",
 				"data": [
 					{
 						"id": 1,
 						"first_name": "John",
 						"last_name": "Doe",
-						"amount": 32.00
+						"net": 32.00,
+						"gross": 32.00,
+						"amount": 32.00,
+						"currency": "USD"
 					},
 					{
 						"id": 2,
 						"first_name": "Jane",
 						"last_name": "Doe",
-						"amount": 10.00
+						"net": 10.00,
+						"gross": 10.00,
+						"amount": 10.00,
+						"currency": "USD"
 					}
 				]
 			}`,
@@ -80,72 +76,46 @@ func TestGetTopCustomers(t *testing.T) {
 			expectCode: http.StatusOK,
 			customers:  []internal.CustomerAttributes{},
 			invoices:   []internal.InvoiceAttributes{},
-			expectBody: `{"data": []}`,
+			expectBody: `{"message": "top customers found", "data": []}`,
+		}, {
+			name:      "exchange rate not found",
+			customers: []internal.CustomerAttributes{{FirstName: "John", LastName: "Doe"}},
+			invoices: []internal.InvoiceAttributes{
+				{Datetime: "2022-05-15 00:00:00", Total: internal.MustParseMoney("32.00"), CustomerId: 1},
+			},
+			query:      "?currency=eur",
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:exchange_rate_not_found", "title": "Unprocessable Entity", "status": 422, "code": "exchange_rate_not_found", "detail": "exchange rate not found: invoice 1 from USD to EUR"}`,
+		}, {
+			name:       "invalid currency",
+			query:      "?currency=euro",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type": "urn:app:problem:invalid_parameter", "title": "Bad Request", "status": 400, "code": "invalid_parameter", "detail": "invalid reporting currency: currency: must be a three letter ISO 4217 code", "errors": [{"field": "currency", "message": "must be a three letter ISO 4217 code"}]}`,
 		},
 	}
 
 	for idx, testCase := range testCases {
 		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
-			db, err := sql.Open("txdb", "fantasy_products_test")
-			require.NoError(t, err)
-			defer db.Close()
-
-			defer func(db *sql.DB) {
-				// delete records
-				_, err := db.Exec("DELETE FROM invoices")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM customers")
-				if err != nil {
-					panic(err)
-				}
-				// reset auto increment
-				_, err = db.Exec("ALTER TABLE invoices AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("ALTER TABLE customers AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-			}(db)
-
-			err = func(db *sql.DB) error {
-				for _, customerAttr := range testCase.customers {
-					_, err := db.Exec(
-						"INSERT INTO customers (`first_name`, `last_name`, `condition`) VALUES (?, ?, ?)",
-						customerAttr.FirstName, customerAttr.LastName, customerAttr.Condition,
-					)
-
-					if err != nil {
-						return err
-					}
-				}
+			db := repository.NewMemoryDB()
+			cr := repository.NewCustomersMemory(db)
+			ir := repository.NewInvoicesMemory(db)
 
-				return nil
-			}(db)
-			require.NoError(t, err)
+			for _, customerAttr := range testCase.customers {
+				c := internal.Customer{CustomerAttributes: customerAttr}
+				err := cr.Save(context.Background(), &c)
+				require.NoError(t, err)
+			}
 
-			err = func(db *sql.DB) error {
-				for _, invoiceAttr := range testCase.invoices {
-					_, err := db.Exec(
-						"INSERT INTO invoices (`customer_id`, `datetime`, `total`) VALUES (?, ?, ?)",
-						invoiceAttr.CustomerId, invoiceAttr.Datetime, invoiceAttr.Total,
-					)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}(db)
-			require.NoError(t, err)
+			for _, invoiceAttr := range testCase.invoices {
+				i := internal.Invoice{InvoiceAttributes: invoiceAttr}
+				err := ir.Save(context.Background(), &i)
+				require.NoError(t, err)
+			}
 
-			cr := repository.NewCustomersMySQL(db)
 			cs := service.NewCustomersDefault(cr)
 			h := handler.NewCustomersDefault(cs)
 
-			request := httptest.NewRequest("GET", "/customers/top", nil)
+			request := httptest.NewRequest("GET", "/customers/top"+testCase.query, nil)
 			response := httptest.NewRecorder()
 
 			h.GetTopCustomers()(response, request)
@@ -155,3 +125,249 @@ func TestGetTopCustomers(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateCustomer(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		expectCode  int
+		expectBody  string
+	}{
+		{
+			name:        "success with charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"first_name": "John", "last_name": "Doe", "condition": 1}`,
+			expectCode:  http.StatusCreated,
+			expectBody:  `{"message": "customer created", "data": {"id": 1, "first_name": "John", "last_name": "Doe", "condition": 1, "email": null, "phone": "", "addresses": [], "created_at": "{datetime}", "updated_at": "{datetime}"}}`,
+		}, {
+			name:        "success with contact details and addresses",
+			contentType: "application/json",
+			body: `{"first_name": "John", "last_name": "Doe", "email": " John@Example.com", "phone": "+1 (555) 0100", "addresses": [
+				{"kind": "billing", "line1": "1 Main St", "city": "Springfield", "state": "IL", "postal_code": "62701", "country": "us"}
+			]}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "customer created", "data": {"id": 1, "first_name": "John", "last_name": "Doe", "condition": 1, "email": "john@example.com", "phone": "+1 (555) 0100", "addresses": [
+				{"kind": "billing", "line1": "1 Main St", "line2": "", "city": "Springfield", "state": "IL", "postal_code": "62701", "country": "US"}
+			], "created_at": "{datetime}", "updated_at": "{datetime}"}}`,
+		}, {
+			name:        "invalid customer",
+			contentType: "application/json",
+			body: `{"first_name": " ", "last_name": "Doe", "condition": 2, "email": "john", "phone": "555-CALL-NOW", "addresses": [
+				{"kind": "billing", "line1": "1 Main St", "city": "Springfield", "country": "USA"},
+				{"kind": "billing", "line1": "", "city": "Springfield", "country": "US"},
+				{"kind": "home", "line1": "3 Main St", "city": "", "country": "US"}
+			]}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid customer", "errors": [
+				{"field": "first_name", "message": "must be between 1 and 45 characters"},
+				{"field": "condition", "message": "must be 1, active, or 0, inactive"},
+				{"field": "email", "message": "must be an email address of at most 100 characters"},
+				{"field": "phone", "message": "must be 7 to 20 digits, spaces and + - ( )"},
+				{"field": "addresses[0].country", "message": "must be a two letter ISO 3166-1 code"},
+				{"field": "addresses[1].line1", "message": "must be between 1 and 100 characters"},
+				{"field": "addresses[1].kind", "message": "must not repeat the kind of another address"},
+				{"field": "addresses[2].kind", "message": "must be billing or shipping"},
+				{"field": "addresses[2].city", "message": "must be between 1 and 45 characters"}
+			]}`,
+		}, {
+			name:        "email exists",
+			contentType: "application/json",
+			body:        `{"first_name": "Jane", "last_name": "Doe", "email": "jane@example.com"}`,
+			expectCode:  http.StatusConflict,
+			expectBody:  `{"type": "urn:app:problem:conflict", "title": "Conflict", "status": 409, "code": "conflict", "detail": "customer email exists: jane@example.com"}`,
+		}, {
+			name:        "unknown field",
+			contentType: "application/json",
+			body:        `{"first_name": "John", "customerid": 1}`,
+			expectCode:  http.StatusBadRequest,
+			expectBody:  `{"type": "urn:app:problem:invalid_json", "title": "Bad Request", "status": 400, "code": "invalid_json", "detail": "request json invalid. field \"customerid\": unknown field (offset 39)", "errors": [{"field": "customerid", "message": "unknown field"}]}`,
+		}, {
+			name:        "trailing data",
+			contentType: "application/json",
+			body:        `{"first_name": "John"} x`,
+			expectCode:  http.StatusBadRequest,
+			expectBody:  `{"type": "urn:app:problem:invalid_json", "title": "Bad Request", "status": 400, "code": "invalid_json", "detail": "request json invalid. unexpected data after the json value (offset 22)"}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			rp := repository.NewCustomersMemory(db)
+			cs := service.NewCustomersDefault(rp)
+			h := handler.NewCustomersDefault(cs)
+			if testCase.expectCode == http.StatusConflict {
+				c := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com"}}
+				require.NoError(t, rp.Save(context.Background(), &c))
+			}
+
+			request := httptest.NewRequest("POST", "/customers", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", testCase.contentType)
+			response := httptest.NewRecorder()
+
+			h.Create()(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			// - the customer is created at the time of the test
+			c, err := rp.FindAll(context.Background())
+			require.NoError(t, err)
+			datetime := ""
+			if len(c) > 0 {
+				datetime = c[0].CreatedAt
+			}
+			require.JSONEq(t, strings.ReplaceAll(testCase.expectBody, "{datetime}", datetime), response.Body.String())
+		})
+	}
+}
+
+func TestUpdateCustomer(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success",
+			path:       "/customers/1",
+			body:       `{"first_name": "John", "last_name": "Smith", "condition": 0, "addresses": [{"kind": "shipping", "line1": "2 Side St", "city": "Springfield", "country": "US"}]}`,
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "customer updated", "data": {"id": 1, "first_name": "John", "last_name": "Smith", "condition": 0, "email": null, "phone": "", "addresses": [
+				{"kind": "shipping", "line1": "2 Side St", "line2": "", "city": "Springfield", "state": "", "postal_code": "", "country": "US"}
+			], "created_at": "{created_at}", "updated_at": "{updated_at}"}}`,
+		}, {
+			name:       "email exists",
+			path:       "/customers/1",
+			body:       `{"first_name": "John", "last_name": "Doe", "email": "jane@example.com"}`,
+			expectCode: http.StatusConflict,
+			expectBody: `{"type": "urn:app:problem:conflict", "title": "Conflict", "status": 409, "code": "conflict", "detail": "customer email exists: jane@example.com"}`,
+		}, {
+			name:       "invalid customer",
+			path:       "/customers/1",
+			body:       `{"first_name": "John"}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid customer", "errors": [{"field": "last_name", "message": "must be between 1 and 45 characters"}]}`,
+		}, {
+			name:       "customer not found",
+			path:       "/customers/3",
+			body:       `{"first_name": "John", "last_name": "Doe"}`,
+			expectCode: http.StatusNotFound,
+			expectBody: `{"type": "urn:app:problem:not_found", "title": "Not Found", "status": 404, "code": "not_found", "detail": "customer not found: 3"}`,
+		}, {
+			name:       "invalid customer id",
+			path:       "/customers/abc",
+			body:       `{"first_name": "John", "last_name": "Doe"}`,
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type": "urn:app:problem:invalid_parameter", "title": "Bad Request", "status": 400, "code": "invalid_parameter", "detail": "invalid customer id", "errors": [{"field": "id", "message": "must be a positive integer"}]}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			rp := repository.NewCustomersMemory(db)
+			for _, c := range []internal.CustomerAttributes{
+				{FirstName: "John", LastName: "Doe", Condition: 1, Addresses: []internal.CustomerAddress{{Kind: "billing", Line1: "1 Main St", City: "Springfield", Country: "US"}}},
+				{FirstName: "Jane", LastName: "Smith", Condition: 1, Email: "jane@example.com"},
+			} {
+				err := rp.Save(context.Background(), &internal.Customer{CustomerAttributes: c})
+				require.NoError(t, err)
+			}
+			h := handler.NewCustomersDefault(service.NewCustomersDefault(rp))
+			rt := chi.NewRouter()
+			rt.Put("/customers/{id}", h.Update())
+
+			request := httptest.NewRequest("PUT", testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			rt.ServeHTTP(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			// - the customer is updated at the time of the test
+			c, err := rp.FindAll(context.Background())
+			require.NoError(t, err)
+			expectBody := strings.NewReplacer("{created_at}", c[0].CreatedAt, "{updated_at}", c[0].UpdatedAt).Replace(testCase.expectBody)
+			require.JSONEq(t, expectBody, response.Body.String())
+		})
+	}
+}
+
+func TestSearchCustomers(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success search",
+			query:      "?q=smith",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "customers found", "data": {"total": 2, "limit": 20, "offset": 0, "results": [
+				{"id": 2, "first_name": "Jane", "last_name": "Smith", "condition": 1, "email": null, "phone": "", "addresses": [], "created_at": "{datetime_2}", "updated_at": "{datetime_2}", "score": 1},
+				{"id": 3, "first_name": "Joan", "last_name": "Smyth", "condition": 0, "email": null, "phone": "", "addresses": [], "created_at": "{datetime_3}", "updated_at": "{datetime_3}", "score": 0.5}
+			]}}`,
+		}, {
+			name:       "success search page",
+			query:      "?q=j&limit=1&offset=2",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "customers found", "data": {"total": 3, "limit": 1, "offset": 2, "results": [
+				{"id": 3, "first_name": "Joan", "last_name": "Smyth", "condition": 0, "email": null, "phone": "", "addresses": [], "created_at": "{datetime_3}", "updated_at": "{datetime_3}", "score": 0.75}
+			]}}`,
+		}, {
+			name:       "success search without results",
+			query:      "?q=zzz",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "customers found", "data": {"total": 0, "limit": 20, "offset": 0, "results": []}}`,
+		}, {
+			name:       "missing text",
+			query:      "?q=%20*",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type": "urn:app:problem:invalid_parameter", "title": "Bad Request", "status": 400, "code": "invalid_parameter", "detail": "invalid search query", "errors": [{"field": "q", "message": "must have a letter or a digit"}]}`,
+		}, {
+			name:       "invalid limit",
+			query:      "?q=smith&limit=101",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type": "urn:app:problem:invalid_parameter", "title": "Bad Request", "status": 400, "code": "invalid_parameter", "detail": "invalid search query", "errors": [{"field": "limit", "message": "must be between 1 and 100"}]}`,
+		}, {
+			name:       "invalid offset",
+			query:      "?q=smith&offset=-1",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type": "urn:app:problem:invalid_parameter", "title": "Bad Request", "status": 400, "code": "invalid_parameter", "detail": "invalid search query", "errors": [{"field": "offset", "message": "must be a non-negative integer"}]}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			rp := repository.NewCustomersMemory(db)
+			for _, c := range []internal.CustomerAttributes{
+				{FirstName: "John", LastName: "Doe", Condition: 1},
+				{FirstName: "Jane", LastName: "Smith", Condition: 1},
+				{FirstName: "Joan", LastName: "Smyth", Condition: 0},
+			} {
+				err := rp.Save(context.Background(), &internal.Customer{CustomerAttributes: c})
+				require.NoError(t, err)
+			}
+			h := handler.NewCustomersDefault(service.NewCustomersDefault(rp))
+
+			request := httptest.NewRequest("GET", "/customers/search"+testCase.query, nil)
+			response := httptest.NewRecorder()
+
+			h.Search()(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			// - the customers are created at the time of the test
+			c, err := rp.FindAll(context.Background())
+			require.NoError(t, err)
+			expectBody := testCase.expectBody
+			for _, v := range c {
+				expectBody = strings.ReplaceAll(expectBody, fmt.Sprintf("{datetime_%d}", v.Id), v.CreatedAt)
+			}
+			require.JSONEq(t, expectBody, response.Body.String())
+		})
+	}
+}
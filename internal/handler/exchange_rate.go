@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/request"
+	"app/platform/web/response"
+)
+
+// NewExchangeRatesDefault returns a new ExchangeRatesDefault
+func NewExchangeRatesDefault(sv internal.ServiceExchangeRate) *ExchangeRatesDefault {
+	return &ExchangeRatesDefault{sv: sv}
+}
+
+// ExchangeRatesDefault is a struct that returns the exchange rate handlers
+type ExchangeRatesDefault struct {
+	// sv is the exchange rate's service
+	sv internal.ServiceExchangeRate
+}
+
+// ExchangeRateJSON is a struct that represents an exchange rate in JSON format
+type ExchangeRateJSON struct {
+	Id            int               `json:"id"`
+	From          internal.Currency `json:"from"`
+	To            internal.Currency `json:"to"`
+	Rate          internal.Rate     `json:"rate"`
+	EffectiveDate string            `json:"effective_date"`
+}
+
+// GetAll returns all exchange rates
+func (h *ExchangeRatesDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		e, err := h.sv.FindAll(r.Context())
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting exchange rates", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting exchange rates")
+			return
+		}
+
+		// response
+		// - serialize
+		erJSON := make([]ExchangeRateJSON, len(e))
+		for ix, v := range e {
+			erJSON[ix] = ExchangeRateJSON{
+				Id:            v.Id,
+				From:          v.From,
+				To:            v.To,
+				Rate:          v.Rate,
+				EffectiveDate: v.EffectiveDate,
+			}
+		}
+		response.OK(w, "exchange rates found", erJSON)
+	}
+}
+
+// RequestBodyExchangeRate is a struct that represents the request body for an exchange rate
+type RequestBodyExchangeRate struct {
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	Rate          internal.Rate `json:"rate"`
+	EffectiveDate string        `json:"effective_date"`
+}
+
+// Create creates a new exchange rate
+func (h *ExchangeRatesDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - body
+		var reqBody RequestBodyExchangeRate
+		err := request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		// - validate
+		e, fields := exchangeRate(reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid exchange rate", fields...)
+			return
+		}
+		// - save
+		err = h.sv.Save(r.Context(), &e)
+		if errors.Is(err, internal.ErrExchangeRateExists) {
+			response.ErrorCode(w, http.StatusConflict, response.CodeConflict, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving exchange rate", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error saving exchange rate")
+			return
+		}
+
+		// response
+		// - serialize
+		er := ExchangeRateJSON{
+			Id:            e.Id,
+			From:          e.From,
+			To:            e.To,
+			Rate:          e.Rate,
+			EffectiveDate: e.EffectiveDate,
+		}
+		response.Created(w, "exchange rate created", er)
+	}
+}
+
+// exchangeRate validates the request body of an exchange rate, returning the errors of its invalid fields.
+func exchangeRate(reqBody RequestBodyExchangeRate) (e internal.ExchangeRate, fields []response.FieldError) {
+	var err error
+	if e.From, err = internal.ParseCurrency(reqBody.From); err != nil {
+		fields = append(fields, response.FieldError{Field: "from", Message: errCurrencyField.Message})
+	}
+	if e.To, err = internal.ParseCurrency(reqBody.To); err != nil {
+		fields = append(fields, response.FieldError{Field: "to", Message: errCurrencyField.Message})
+	} else if e.To == e.From {
+		fields = append(fields, response.FieldError{Field: "to", Message: "must differ from from"})
+	}
+
+	e.Rate = reqBody.Rate
+	if e.Rate <= 0 {
+		fields = append(fields, response.FieldError{Field: "rate", Message: "must be greater than 0"})
+	}
+
+	e.EffectiveDate = reqBody.EffectiveDate
+	if _, err = time.Parse(time.DateOnly, e.EffectiveDate); err != nil {
+		fields = append(fields, response.FieldError{Field: "effective_date", Message: "must be a date, YYYY-MM-DD"})
+	}
+	return
+}
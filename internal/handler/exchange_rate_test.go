@@ -0,0 +1,72 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateExchangeRate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		rates      []internal.ExchangeRateAttributes
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success with lower case currencies",
+			body:       `{"from": "eur", "to": "usd", "rate": 1.0825, "effective_date": "2024-01-31"}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "exchange rate created", "data": {"id": 1, "from": "EUR", "to": "USD", "rate": 1.0825, "effective_date": "2024-01-31"}}`,
+		}, {
+			name:       "invalid fields",
+			body:       `{"from": "EUR", "to": "EUR", "rate": 0, "effective_date": "31/01/2024"}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid exchange rate", "errors": [
+				{"field": "to", "message": "must differ from from"},
+				{"field": "rate", "message": "must be greater than 0"},
+				{"field": "effective_date", "message": "must be a date, YYYY-MM-DD"}
+			]}`,
+		}, {
+			name:       "already exists",
+			rates:      []internal.ExchangeRateAttributes{{From: "EUR", To: "USD", Rate: internal.MustParseRate("1.08"), EffectiveDate: "2024-01-31"}},
+			body:       `{"from": "EUR", "to": "USD", "rate": 1.0825, "effective_date": "2024-01-31"}`,
+			expectCode: http.StatusConflict,
+			expectBody: `{"type": "urn:app:problem:conflict", "title": "Conflict", "status": 409, "code": "conflict", "detail": "exchange rate already exists: EUR to USD from 2024-01-31"}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			rp := repository.NewExchangeRatesMemory(db)
+
+			for _, rateAttr := range testCase.rates {
+				e := internal.ExchangeRate{ExchangeRateAttributes: rateAttr}
+				err := rp.Save(context.Background(), &e)
+				require.NoError(t, err)
+			}
+
+			h := handler.NewExchangeRatesDefault(service.NewExchangeRatesDefault(rp))
+
+			request := httptest.NewRequest("POST", "/exchange_rates", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			h.Create()(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			require.JSONEq(t, testCase.expectBody, response.Body.String())
+		})
+	}
+}
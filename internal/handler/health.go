@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"app/platform/logger"
+	"app/platform/web/response"
+)
+
+const (
+	// HealthStatusOK is the status of a healthy check or service.
+	HealthStatusOK = "ok"
+	// HealthStatusDegraded is the status of a service with at least one failing check.
+	HealthStatusDegraded = "degraded"
+	// HealthStatusDraining is the status of a service that is shutting down.
+	HealthStatusDraining = "draining"
+	// HealthStatusError is the status of a failing check.
+	HealthStatusError = "error"
+)
+
+// HealthCheck is a named check of a dependency of the service.
+type HealthCheck struct {
+	// Name is the name of the check in the readiness report.
+	Name string
+	// Check returns an error if the dependency is not usable.
+	Check func(ctx context.Context) error
+}
+
+// NewHealthDefault returns a new HealthDefault
+func NewHealthDefault(draining func() bool, timeout time.Duration, checks ...HealthCheck) *HealthDefault {
+	return &HealthDefault{draining: draining, timeout: timeout, checks: checks}
+}
+
+// HealthDefault is a struct that returns the liveness and readiness handlers
+type HealthDefault struct {
+	// draining reports whether the graceful shutdown has started
+	draining func() bool
+	// timeout is the time limit of the readiness checks
+	timeout time.Duration
+	// checks are the readiness checks
+	checks []HealthCheck
+}
+
+// HealthCheckJSON is a struct that represents the result of a check in JSON format
+type HealthCheckJSON struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessJSON is a struct that represents the readiness report in JSON format
+type ReadinessJSON struct {
+	Status string                     `json:"status"`
+	Checks map[string]HealthCheckJSON `json:"checks"`
+}
+
+// Healthz reports that the process is alive
+func (h *HealthDefault) Healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.JSON(w, http.StatusOK, map[string]any{
+			"status": HealthStatusOK,
+		})
+	}
+}
+
+// Readyz reports whether the service can handle requests, running every check within the timeout
+func (h *HealthDefault) Readyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+		defer cancel()
+
+		rd := ReadinessJSON{Status: HealthStatusOK, Checks: make(map[string]HealthCheckJSON, len(h.checks))}
+		for _, c := range h.checks {
+			if err := c.Check(ctx); err != nil {
+				logger.FromContext(r.Context()).Warn("readiness check failed", "check", c.Name, "error", err)
+				rd.Status = HealthStatusDegraded
+				rd.Checks[c.Name] = HealthCheckJSON{Status: HealthStatusError, Error: err.Error()}
+				continue
+			}
+			rd.Checks[c.Name] = HealthCheckJSON{Status: HealthStatusOK}
+		}
+		// - a draining service is never ready, so no new traffic is routed to it
+		if h.draining != nil && h.draining() {
+			rd.Status = HealthStatusDraining
+		}
+
+		// response
+		code := http.StatusOK
+		if rd.Status != HealthStatusOK {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		response.JSON(w, code, rd)
+	}
+}
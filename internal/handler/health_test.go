@@ -0,0 +1,73 @@
+package handler_test
+
+import (
+	"app/internal/handler"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyz(t *testing.T) {
+	ok := handler.HealthCheck{Name: "database", Check: func(ctx context.Context) error { return nil }}
+	failing := handler.HealthCheck{Name: "schema", Check: func(ctx context.Context) error { return errors.New("schema version mismatch") }}
+	slow := handler.HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	testCases := []struct {
+		name       string
+		draining   bool
+		checks     []handler.HealthCheck
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "ready",
+			checks:     []handler.HealthCheck{ok},
+			expectCode: http.StatusOK,
+			expectBody: `{"status": "ok", "checks": {"database": {"status": "ok"}}}`,
+		},
+		{
+			name:       "degraded",
+			checks:     []handler.HealthCheck{ok, failing},
+			expectCode: http.StatusServiceUnavailable,
+			expectBody: `{"status": "degraded", "checks": {"database": {"status": "ok"}, "schema": {"status": "error", "error": "schema version mismatch"}}}`,
+		},
+		{
+			name:       "check timed out",
+			checks:     []handler.HealthCheck{slow},
+			expectCode: http.StatusServiceUnavailable,
+			expectBody: `{"status": "degraded", "checks": {"database": {"status": "error", "error": "context deadline exceeded"}}}`,
+		},
+		{
+			name:       "draining",
+			draining:   true,
+			checks:     []handler.HealthCheck{ok},
+			expectCode: http.StatusServiceUnavailable,
+			expectBody: `{"status": "draining", "checks": {"database": {"status": "ok"}}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			draining := tc.draining
+			hd := handler.NewHealthDefault(func() bool { return draining }, 10*time.Millisecond, tc.checks...)
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			res := httptest.NewRecorder()
+
+			// act
+			hd.Readyz()(res, req)
+
+			// assert
+			require.Equal(t, tc.expectCode, res.Code)
+			require.JSONEq(t, tc.expectBody, res.Body.String())
+		})
+	}
+}
@@ -1,11 +1,10 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
-	"strconv"
 
 	"app/internal"
+	"app/platform/logger"
 	"app/platform/web/request"
 	"app/platform/web/response"
 )
@@ -23,10 +22,14 @@ type InvoicesDefault struct {
 
 // InvoiceJSON is a struct that represents a invoice in JSON format
 type InvoiceJSON struct {
-	Id         int     `json:"id"`
-	Datetime   string  `json:"datetime"`
-	Total      float64 `json:"total"`
-	CustomerId int     `json:"customer_id"`
+	Id         int               `json:"id"`
+	Datetime   string            `json:"datetime"`
+	Discount   internal.Money    `json:"discount"`
+	Subtotal   internal.Money    `json:"subtotal"`
+	Tax        internal.Money    `json:"tax"`
+	Total      internal.Money    `json:"total"`
+	CustomerId int               `json:"customer_id"`
+	Currency   internal.Currency `json:"currency"`
 }
 
 // GetAll returns all invoices
@@ -36,8 +39,9 @@ func (h *InvoicesDefault) GetAll() http.HandlerFunc {
 		// ...
 
 		// process
-		i, err := h.sv.FindAll()
+		i, err := h.sv.FindAll(r.Context())
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting invoices", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error getting invoices")
 			return
 		}
@@ -49,22 +53,27 @@ func (h *InvoicesDefault) GetAll() http.HandlerFunc {
 			ivJSON[ix] = InvoiceJSON{
 				Id:         v.Id,
 				Datetime:   v.Datetime,
+				Discount:   v.Discount,
+				Subtotal:   v.Subtotal,
+				Tax:        v.Tax,
 				Total:      v.Total,
 				CustomerId: v.CustomerId,
+				Currency:   v.Currency,
 			}
 		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "invoices found",
-			"data":    ivJSON,
-		})
+		response.OK(w, "invoices found", ivJSON)
 	}
 }
 
-// RequestBodyInvoice is a struct that represents the request body for a invoice
+// RequestBodyInvoice is a struct that represents the request body for a invoice.
+// Subtotal and Tax are optional: without them the invoice is untaxed, its subtotal is its total.
 type RequestBodyInvoice struct {
-	Datetime   string  `json:"datetime"`
-	Total      float64 `json:"total"`
-	CustomerId int     `json:"customer_id"`
+	Datetime   string          `json:"datetime"`
+	Subtotal   *internal.Money `json:"subtotal"`
+	Tax        *internal.Money `json:"tax"`
+	Total      internal.Money  `json:"total"`
+	CustomerId int             `json:"customer_id"`
+	Currency   string          `json:"currency"`
 }
 
 // Create creates a new invoice
@@ -75,22 +84,39 @@ func (h *InvoicesDefault) Create() http.HandlerFunc {
 		var reqBody RequestBodyInvoice
 		err := request.JSON(r, &reqBody)
 		if err != nil {
-			response.Error(w, http.StatusBadRequest, "error parsing request body")
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
 			return
 		}
 
 		// process
+		// - validate
+		currency, err := entityCurrency(reqBody.Currency)
+		if err != nil {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, err.Error(), errCurrencyField)
+			return
+		}
+		subtotal, tax, ok := invoiceAmounts(reqBody)
+		if !ok {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid invoice amounts",
+				response.FieldError{Field: "total", Message: "must be subtotal plus tax"})
+			return
+		}
 		// - deserialize
 		i := internal.Invoice{
 			InvoiceAttributes: internal.InvoiceAttributes{
 				Datetime:   reqBody.Datetime,
+				Subtotal:   subtotal,
+				Tax:        tax,
 				Total:      reqBody.Total,
 				CustomerId: reqBody.CustomerId,
+				Currency:   currency,
 			},
 		}
 		// - save
-		err = h.sv.Save(&i)
+		err = h.sv.Save(r.Context(), &i)
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving invoice", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error saving invoice")
 			return
 		}
@@ -100,54 +126,82 @@ func (h *InvoicesDefault) Create() http.HandlerFunc {
 		iv := InvoiceJSON{
 			Id:         i.Id,
 			Datetime:   i.Datetime,
+			Discount:   i.Discount,
+			Subtotal:   i.Subtotal,
+			Tax:        i.Tax,
 			Total:      i.Total,
 			CustomerId: i.CustomerId,
+			Currency:   i.Currency,
 		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "invoice created",
-			"data":    iv,
-		})
+		response.Created(w, "invoice created", iv)
+	}
+}
+
+// invoiceAmounts returns the subtotal and tax of the request body of an invoice, the total and 0 when both are missing.
+// It reports false when the total is not the subtotal plus the tax.
+func invoiceAmounts(reqBody RequestBodyInvoice) (subtotal, tax internal.Money, ok bool) {
+	if reqBody.Subtotal == nil && reqBody.Tax == nil {
+		return reqBody.Total, 0, true
 	}
+
+	if reqBody.Subtotal != nil {
+		subtotal = *reqBody.Subtotal
+	}
+	if reqBody.Tax != nil {
+		tax = *reqBody.Tax
+	}
+	return subtotal, tax, subtotal+tax == reqBody.Total
 }
 
 func (h *InvoicesDefault) UpdateInvoicesTotal() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := h.sv.UpdateInvoicesTotal()
+		err := h.sv.UpdateInvoicesTotal(r.Context())
 		if err != nil {
+			logger.FromContext(r.Context()).Error("internal server error", "error", err)
 			response.Error(w, http.StatusInternalServerError, "internal server error")
 			return
 		}
 
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "invoices total updated",
-		})
+		response.OK(w, "invoices total updated", nil)
 	}
 }
 
+// InvoiceTotalByCustomerConditionJSON is the invoiced total of a customer condition in JSON format.
+// Total is the gross figure, kept for the clients that predate Net and Gross.
 type InvoiceTotalByCustomerConditionJSON struct {
-	Condition int     `json:"condition"`
-	Total     float64 `json:"total"`
+	Condition int               `json:"condition"`
+	Net       internal.Money    `json:"net"`
+	Gross     internal.Money    `json:"gross"`
+	Total     internal.Money    `json:"total"`
+	Currency  internal.Currency `json:"currency"`
 }
 
+// InvoicesTotalByCondition returns the invoices total by customer condition, converted to the query parameter currency, USD by default
 func (h *InvoicesDefault) InvoicesTotalByCondition() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		invoiceTotalByCustomerCondition, err := h.sv.GetInvoicesTotalByCustomerCondition()
+		currency, err := reportCurrency(r.URL.Query())
 		if err != nil {
-			response.Error(w, http.StatusInternalServerError, "internal server error")
+			writeReportCurrencyError(w, r, err)
+			return
+		}
+
+		invoiceTotalByCustomerCondition, err := h.sv.GetInvoicesTotalByCustomerCondition(r.Context(), currency)
+		if err != nil {
+			writeReportError(w, r, err)
 			return
 		}
 
 		data := make([]InvoiceTotalByCustomerConditionJSON, 0, len(invoiceTotalByCustomerCondition))
 		for _, invoceTotal := range invoiceTotalByCustomerCondition {
-			totalRounded, _ := strconv.ParseFloat(fmt.Sprintf("%.2f", invoceTotal.Total), 64)
 			data = append(data, InvoiceTotalByCustomerConditionJSON{
 				Condition: invoceTotal.Condition,
-				Total:     totalRounded,
+				Net:       invoceTotal.Net,
+				Gross:     invoceTotal.Total,
+				Total:     invoceTotal.Total,
+				Currency:  invoceTotal.Currency,
 			})
 		}
 
-		response.JSON(w, http.StatusOK, map[string]any{
-			"data": data,
-		})
+		response.OK(w, "invoices total by customer condition found", data)
 	}
 }
@@ -5,30 +5,15 @@ import (
 	"app/internal/handler"
 	"app/internal/repository"
 	"app/internal/service"
-	"database/sql"
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"strconv"
 	"testing"
 
-	"github.com/DATA-DOG/go-txdb"
-	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
 )
 
-func init() {
-	cfg := mysql.Config{
-		User:   "root",
-		Passwd: "123",
-		Net:    "tcp",
-		Addr:   "localhost:3306",
-		DBName: "fantasy_products_test",
-	}
-
-	txdb.Register("txdb", "mysql", cfg.FormatDSN())
-}
-
 func TestInvoicesTotalByCondition(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -66,23 +51,24 @@ func TestInvoicesTotalByCondition(t *testing.T) {
 			invoices: []internal.InvoiceAttributes{
 				{
 					Datetime:   "2022-05-15 00:00:00",
-					Total:      32.00,
+					Total:      internal.MustParseMoney("32.00"),
 					CustomerId: 1,
 				}, {
 					Datetime:   "2022-05-15 00:00:00",
-					Total:      10.00,
+					Total:      internal.MustParseMoney("10.00"),
 					CustomerId: 2,
 				}, {
 					Datetime:   "2022-05-15 00:00:00",
-					Total:      5.00,
+					Total:      internal.MustParseMoney("5.00"),
 					CustomerId: 3,
 				},
 			},
 			expectCode: http.StatusOK,
 			expectBody: `{
+				"message": "invoices total by customer condition found",
 				"data": [
-					{"condition": 1, "total": 42.00},
-					{"condition": 0, "total": 5.00}
+					{"condition": 1, "net": 42.00, "gross": 42.00, "total": 42.00, "currency": "USD"},
+					{"condition": 0, "net": 5.00, "gross": 5.00, "total": 5.00, "currency": "USD"}
 				]
 			}`,
 		}, {
@@ -90,68 +76,27 @@ func TestInvoicesTotalByCondition(t *testing.T) {
 			expectCode: http.StatusOK,
 			customers:  []internal.Customer{},
 			invoices:   []internal.InvoiceAttributes{},
-			expectBody: `{"data": []}`,
+			expectBody: `{"message": "invoices total by customer condition found", "data": []}`,
 		},
 	}
 
 	for idx, testCase := range testCases {
 		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
-			db, err := sql.Open("txdb", "fantasy_products_test")
-			require.NoError(t, err)
-			defer db.Close()
-
-			defer func(db *sql.DB) {
-				// delete records
-				_, err := db.Exec("DELETE FROM invoices")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM customers")
-				if err != nil {
-					panic(err)
-				}
-				// reset auto increment
-				_, err = db.Exec("ALTER TABLE invoices AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("ALTER TABLE customers AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-			}(db)
-
-			err = func(db *sql.DB) error {
-				for _, customerAttr := range testCase.customers {
-					_, err := db.Exec(
-						"INSERT INTO customers (`id`, `first_name`, `last_name`, `condition`) VALUES (?, ?, ?, ?)",
-						customerAttr.Id, customerAttr.FirstName, customerAttr.LastName, customerAttr.Condition,
-					)
+			db := repository.NewMemoryDB()
+			cr := repository.NewCustomersMemory(db)
+			ir := repository.NewInvoicesMemory(db)
 
-					if err != nil {
-						return err
-					}
-				}
-
-				return nil
-			}(db)
-			require.NoError(t, err)
+			for _, customer := range testCase.customers {
+				err := cr.Save(context.Background(), &customer)
+				require.NoError(t, err)
+			}
 
-			err = func(db *sql.DB) error {
-				for _, invoiceAttr := range testCase.invoices {
-					_, err := db.Exec(
-						"INSERT INTO invoices (`customer_id`, `datetime`, `total`) VALUES (?, ?, ?)",
-						invoiceAttr.CustomerId, invoiceAttr.Datetime, invoiceAttr.Total,
-					)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}(db)
-			require.NoError(t, err)
+			for _, invoiceAttr := range testCase.invoices {
+				i := internal.Invoice{InvoiceAttributes: invoiceAttr}
+				err := ir.Save(context.Background(), &i)
+				require.NoError(t, err)
+			}
 
-			ir := repository.NewInvoicesMySQL(db)
 			is := service.NewInvoicesDefault(ir)
 			h := handler.NewInvoicesDefault(is)
 
@@ -173,7 +118,7 @@ func TestUpdateInvoicesTotal(t *testing.T) {
 		invoices     []internal.Invoice
 		sales        []internal.Sale
 		expectCode   int
-		expectValues map[int]float64
+		expectValues map[int]internal.Money
 	}{
 		{
 			name: "success retrieve invoices total by condition",
@@ -182,14 +127,14 @@ func TestUpdateInvoicesTotal(t *testing.T) {
 					Id: 1,
 					ProductAttributes: internal.ProductAttributes{
 						Description: "Product 1",
-						Price:       10.00,
+						Price:       internal.MustParseMoney("10.00"),
 					},
 				},
 				{
 					Id: 2,
 					ProductAttributes: internal.ProductAttributes{
 						Description: "Product 2",
-						Price:       5.00,
+						Price:       internal.MustParseMoney("5.00"),
 					},
 				},
 			},
@@ -198,14 +143,14 @@ func TestUpdateInvoicesTotal(t *testing.T) {
 					Id: 1,
 					InvoiceAttributes: internal.InvoiceAttributes{
 						Datetime:   "2022-05-15 00:00:00",
-						Total:      0.00,
+						Total:      internal.MustParseMoney("0.00"),
 						CustomerId: 1,
 					},
 				}, {
 					Id: 2,
 					InvoiceAttributes: internal.InvoiceAttributes{
 						Datetime:   "2022-05-15 00:00:00",
-						Total:      0.00,
+						Total:      internal.MustParseMoney("0.00"),
 						CustomerId: 1,
 					},
 				},
@@ -237,112 +182,41 @@ func TestUpdateInvoicesTotal(t *testing.T) {
 				},
 			},
 			expectCode: http.StatusOK,
-			expectValues: map[int]float64{
-				1: 150.00,
-				2: 200.00,
+			expectValues: map[int]internal.Money{
+				1: internal.MustParseMoney("150.00"),
+				2: internal.MustParseMoney("200.00"),
 			},
 		},
 	}
 
 	for idx, testCase := range testCases {
 		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
-			db, err := sql.Open("txdb", "fantasy_products_test")
-			require.NoError(t, err)
-			defer db.Close()
-
-			defer func(db *sql.DB) {
-				// delete records
-				_, err := db.Exec("DELETE FROM sales")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM products")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM invoices")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM customers")
-				if err != nil {
-					panic(err)
-				}
-				// reset auto increment
-				_, err = db.Exec("ALTER TABLE sales AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("ALTER TABLE products AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("ALTER TABLE invoices AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("ALTER TABLE customers AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-			}(db)
+			db := repository.NewMemoryDB()
+			ir := repository.NewInvoicesMemory(db)
 
-			err = func(db *sql.DB) error {
-				_, err := db.Exec(
-					"INSERT INTO customers (`id`, `first_name`, `last_name`, `condition`) VALUES (?, ?, ?, ?)",
-					1, "John", "Doe", 1,
-				)
-				if err != nil {
-					return err
-				}
-
-				return nil
-			}(db)
+			c := internal.Customer{
+				CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe", Condition: 1},
+			}
+			err := repository.NewCustomersMemory(db).Save(context.Background(), &c)
 			require.NoError(t, err)
 
-			err = func(db *sql.DB) error {
-				for _, productAttr := range testCase.products {
-					_, err := db.Exec(
-						"INSERT INTO products (`id`, `description`, `price`) VALUES (?, ?, ?)",
-						productAttr.Id, productAttr.Description, productAttr.Price,
-					)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}(db)
-			require.NoError(t, err)
+			pr := repository.NewProductsMemory(db)
+			for _, product := range testCase.products {
+				err := pr.Save(internal.WithStockUntracked(context.Background()), &product)
+				require.NoError(t, err)
+			}
 
-			err = func(db *sql.DB) error {
-				for _, invoiceAttr := range testCase.invoices {
-					_, err := db.Exec(
-						"INSERT INTO invoices (`customer_id`, `datetime`, `total`) VALUES (?, ?, ?)",
-						invoiceAttr.CustomerId, invoiceAttr.Datetime, invoiceAttr.Total,
-					)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}(db)
-			require.NoError(t, err)
+			for _, invoice := range testCase.invoices {
+				err := ir.Save(context.Background(), &invoice)
+				require.NoError(t, err)
+			}
 
-			err = func(db *sql.DB) error {
-				for _, saleAttr := range testCase.sales {
-					_, err := db.Exec(
-						"INSERT INTO sales (`quantity`, `product_id`, `invoice_id`) VALUES (?, ?, ?)",
-						saleAttr.Quantity, saleAttr.ProductId, saleAttr.InvoiceId,
-					)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}(db)
-			require.NoError(t, err)
+			sr := repository.NewSalesMemory(db)
+			for _, sale := range testCase.sales {
+				err := sr.Save(context.Background(), &sale)
+				require.NoError(t, err)
+			}
 
-			ir := repository.NewInvoicesMySQL(db)
 			is := service.NewInvoicesDefault(ir)
 			h := handler.NewInvoicesDefault(is)
 
@@ -353,16 +227,11 @@ func TestUpdateInvoicesTotal(t *testing.T) {
 
 			require.Equal(t, testCase.expectCode, response.Code)
 
-			rows, err := db.Query("SELECT id, total FROM invoices")
+			invoices, err := ir.FindAll(context.Background())
 			require.NoError(t, err)
 
-			var id int
-			var total float64
-			for rows.Next() {
-				err = rows.Scan(&id, &total)
-				require.NoError(t, err)
-				total, _ = strconv.ParseFloat(fmt.Sprintf("%.2f", total), 64)
-				require.Equal(t, testCase.expectValues[id], total)
+			for _, invoice := range invoices {
+				require.Equal(t, testCase.expectValues[invoice.Id], invoice.Total)
 			}
 		})
 
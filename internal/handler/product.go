@@ -1,11 +1,28 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"app/internal"
+	"app/platform/logger"
 	"app/platform/web/request"
 	"app/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CodeProductArchived is the problem code of a sale of an archived product.
+const CodeProductArchived = "product_archived"
+
+// Maximum lengths of the catalog attributes of a product, the ones of their columns.
+const (
+	skuMaxLen  = 45
+	unitMaxLen = 20
 )
 
 // NewProductsDefault returns a new ProductsDefault
@@ -21,9 +38,37 @@ type ProductsDefault struct {
 
 // ProductJSON is a struct that represents a product in JSON format
 type ProductJSON struct {
-	Id          int     `json:"id"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Id          int               `json:"id"`
+	Description string            `json:"description"`
+	Price       internal.Money    `json:"price"`
+	Currency    internal.Currency `json:"currency"`
+	TaxCategory string            `json:"tax_category"`
+	Sku         *string           `json:"sku"`
+	CategoryId  *int              `json:"category_id"`
+	Unit        string            `json:"unit"`
+	Archived    bool              `json:"archived"`
+}
+
+// productJSON serializes the product p, with a null SKU and category if it has none.
+func productJSON(p internal.Product) ProductJSON {
+	pr := ProductJSON{
+		Id:          p.Id,
+		Description: p.Description,
+		Price:       p.Price,
+		Currency:    p.Currency,
+		TaxCategory: p.TaxCategory,
+		Unit:        p.Unit,
+		Archived:    p.Archived,
+	}
+	if p.Sku != "" {
+		sku := p.Sku
+		pr.Sku = &sku
+	}
+	if p.CategoryId != 0 {
+		categoryId := p.CategoryId
+		pr.CategoryId = &categoryId
+	}
+	return pr
 }
 
 // GetAll returns all products
@@ -33,8 +78,9 @@ func (h *ProductsDefault) GetAll() http.HandlerFunc {
 		// ...
 
 		// process
-		p, err := h.sv.FindAll()
+		p, err := h.sv.FindAll(r.Context())
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting products", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error getting products")
 			return
 		}
@@ -43,23 +89,21 @@ func (h *ProductsDefault) GetAll() http.HandlerFunc {
 		// - serialize
 		pJSON := make([]ProductJSON, len(p))
 		for ix, v := range p {
-			pJSON[ix] = ProductJSON{
-				Id:          v.Id,
-				Description: v.Description,
-				Price:       v.Price,
-			}
+			pJSON[ix] = productJSON(v)
 		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "products found",
-			"data":    pJSON,
-		})
+		response.OK(w, "products found", pJSON)
 	}
 }
 
 // RequestBodyProduct is a struct that represents the request body for a product
 type RequestBodyProduct struct {
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Description string         `json:"description"`
+	Price       internal.Money `json:"price"`
+	Currency    string         `json:"currency"`
+	TaxCategory string         `json:"tax_category"`
+	Sku         string         `json:"sku"`
+	CategoryId  *int           `json:"category_id"`
+	Unit        string         `json:"unit"`
 }
 
 // Create creates a new product
@@ -70,39 +114,234 @@ func (h *ProductsDefault) Create() http.HandlerFunc {
 		var reqBody RequestBodyProduct
 		err := request.JSON(r, &reqBody)
 		if err != nil {
-			response.Error(w, http.StatusBadRequest, "error parsing request body")
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
 			return
 		}
 
 		// process
-		// - deserialize
-		p := internal.Product{
-			ProductAttributes: internal.ProductAttributes{
-				Description: reqBody.Description,
-				Price:       reqBody.Price,
-			},
+		// - validate
+		p, fields := product(reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid product", fields...)
+			return
 		}
 		// - save
-		err = h.sv.Save(&p)
+		err = h.sv.Save(r.Context(), &p)
+		if errors.Is(err, internal.ErrProductSkuExists) {
+			response.ErrorCode(w, http.StatusConflict, response.CodeConflict, err.Error())
+			return
+		}
+		if errors.Is(err, internal.ErrCategoryNotFound) {
+			logger.FromContext(r.Context()).Debug("error creating product", "error", err)
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid product", errCategoryIdField)
+			return
+		}
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error creating product", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error creating product")
 			return
 		}
 
 		// response
 		// - serialize
-		pr := ProductJSON{
-			Id:          p.Id,
-			Description: p.Description,
-			Price:       p.Price,
+		response.Created(w, "product created", productJSON(p))
+	}
+}
+
+// errCategoryIdField is the field error of a category that does not exist.
+var errCategoryIdField = response.FieldError{Field: "category_id", Message: "must be an existing category"}
+
+// product validates the request body of a product, returning the errors of its invalid fields.
+func product(reqBody RequestBodyProduct) (p internal.Product, fields []response.FieldError) {
+	p.Description = reqBody.Description
+	p.Price = reqBody.Price
+
+	currency, err := entityCurrency(reqBody.Currency)
+	if err != nil {
+		fields = append(fields, errCurrencyField)
+	}
+	p.Currency = currency
+
+	p.TaxCategory = reqBody.TaxCategory
+	if utf8.RuneCountInString(p.TaxCategory) > taxCategoryMaxLen {
+		fields = append(fields, errTaxCategoryField)
+	}
+
+	p.Sku = reqBody.Sku
+	if utf8.RuneCountInString(p.Sku) > skuMaxLen || strings.ContainsFunc(p.Sku, unicode.IsSpace) {
+		fields = append(fields, response.FieldError{Field: "sku", Message: "must be at most 45 characters without spaces"})
+	}
+
+	if reqBody.CategoryId != nil {
+		p.CategoryId = *reqBody.CategoryId
+		if p.CategoryId <= 0 {
+			fields = append(fields, response.FieldError{Field: "category_id", Message: "must be a positive integer"})
+		}
+	}
+
+	p.Unit = reqBody.Unit
+	if utf8.RuneCountInString(p.Unit) > unitMaxLen {
+		fields = append(fields, response.FieldError{Field: "unit", Message: "must be at most 20 characters"})
+	}
+	return
+}
+
+// ProductPriceJSON is a struct that represents a price of a product in JSON format
+type ProductPriceJSON struct {
+	Id        int               `json:"id"`
+	ProductId int               `json:"product_id"`
+	Price     internal.Money    `json:"price"`
+	Currency  internal.Currency `json:"currency"`
+	ValidFrom string            `json:"valid_from"`
+	ValidTo   *string           `json:"valid_to"`
+}
+
+// GetPrices returns the price history of a product
+func (h *ProductsDefault) GetPrices() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path
+		id, ok := productId(w, r)
+		if !ok {
+			return
+		}
+
+		// process
+		pp, err := h.sv.FindPrices(r.Context(), id)
+		if errors.Is(err, internal.ErrProductNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting product prices", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting product prices")
+			return
+		}
+
+		// response
+		// - serialize
+		ppJSON := make([]ProductPriceJSON, len(pp))
+		for ix, v := range pp {
+			ppJSON[ix] = ProductPriceJSON{
+				Id:        v.Id,
+				ProductId: v.ProductId,
+				Price:     v.Price,
+				Currency:  v.Currency,
+				ValidFrom: v.ValidFrom,
+			}
+			// - the current price is valid until further notice
+			if v.ValidTo != "" {
+				validTo := v.ValidTo
+				ppJSON[ix].ValidTo = &validTo
+			}
 		}
-		response.JSON(w, http.StatusCreated, map[string]any{
-			"message": "product created",
-			"data":    pr,
-		})
+		response.OK(w, "product prices found", ppJSON)
 	}
 }
 
+// RequestBodyProductPrice is a struct that represents the request body for the price of a product
+type RequestBodyProductPrice struct {
+	Price internal.Money `json:"price"`
+}
+
+// UpdatePrice changes the price of a product
+func (h *ProductsDefault) UpdatePrice() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path
+		id, ok := productId(w, r)
+		if !ok {
+			return
+		}
+		// - body
+		var reqBody RequestBodyProductPrice
+		err := request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		p := internal.Product{Id: id, ProductAttributes: internal.ProductAttributes{Price: reqBody.Price}}
+		err = h.sv.UpdatePrice(r.Context(), &p)
+		if errors.Is(err, internal.ErrProductNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error updating product price", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error updating product price")
+			return
+		}
+
+		// response
+		// - serialize
+		response.OK(w, "product price updated", productJSON(p))
+	}
+}
+
+// RequestBodyProductArchived is a struct that represents the request body for the archived flag of a product
+type RequestBodyProductArchived struct {
+	Archived *bool `json:"archived"`
+}
+
+// UpdateArchived archives a product, so it can no longer be sold, or restores it
+func (h *ProductsDefault) UpdateArchived() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path
+		id, ok := productId(w, r)
+		if !ok {
+			return
+		}
+		// - body
+		var reqBody RequestBodyProductArchived
+		err := request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		// - validate
+		if reqBody.Archived == nil {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid archived flag",
+				response.FieldError{Field: "archived", Message: "is required"})
+			return
+		}
+		// - save
+		p := internal.Product{Id: id, ProductAttributes: internal.ProductAttributes{Archived: *reqBody.Archived}}
+		err = h.sv.UpdateArchived(r.Context(), &p)
+		if errors.Is(err, internal.ErrProductNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error updating product archived flag", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error updating product archived flag")
+			return
+		}
+
+		// response
+		// - serialize
+		response.OK(w, "product archived flag updated", productJSON(p))
+	}
+}
+
+// productId parses the product id of the path, writing the error response if it is invalid.
+func productId(w http.ResponseWriter, r *http.Request) (id int, ok bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidParameter, "invalid product id",
+			response.FieldError{Field: "id", Message: "must be a positive integer"})
+		return 0, false
+	}
+	return id, true
+}
+
 type TopProductJSON struct {
 	Id          int    `json:"id"`
 	Description string `json:"description"`
@@ -112,8 +351,9 @@ type TopProductJSON struct {
 // Create creates a new product
 func (h *ProductsDefault) GetTopProducts() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		topProducts, err := h.sv.GetTopProducts()
+		topProducts, err := h.sv.GetTopProducts(r.Context())
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting top products", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error getting top products")
 			return
 		}
@@ -127,8 +367,41 @@ func (h *ProductsDefault) GetTopProducts() http.HandlerFunc {
 			})
 		}
 
-		response.JSON(w, http.StatusOK, map[string]any{
-			"data": data,
-		})
+		response.OK(w, "top products found", data)
+	}
+}
+
+// ProductMatchJSON is a struct that represents a product found by a search in JSON format
+type ProductMatchJSON struct {
+	ProductJSON
+	Score float64 `json:"score"`
+}
+
+// Search returns the page of the query parameters limit and offset of the products whose description
+// matches the query parameter q, the best ranked first
+func (h *ProductsDefault) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := searchQuery(r.URL.Query())
+		if err != nil {
+			writeSearchQueryError(w, r, err)
+			return
+		}
+
+		// process
+		m, err := h.sv.Search(r.Context(), q)
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error searching products", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error searching products")
+			return
+		}
+
+		// response
+		// - serialize
+		data := SearchPageJSON[ProductMatchJSON]{Total: m.Total, Limit: q.Limit, Offset: q.Offset, Results: make([]ProductMatchJSON, len(m.Matches))}
+		for ix, v := range m.Matches {
+			data.Results[ix] = ProductMatchJSON{ProductJSON: productJSON(v.Product), Score: v.Score}
+		}
+		response.OK(w, "products found", data)
 	}
 }
@@ -5,28 +5,17 @@ import (
 	"app/internal/handler"
 	"app/internal/repository"
 	"app/internal/service"
-	"database/sql"
+	"context"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/DATA-DOG/go-txdb"
-	"github.com/go-sql-driver/mysql"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
 )
 
-func init() {
-	cfg := mysql.Config{
-		User:   "root",
-		Passwd: "123",
-		Net:    "tcp",
-		Addr:   "localhost:3306",
-		DBName: "fantasy_products_test",
-	}
-
-	txdb.Register("txdb", "mysql", cfg.FormatDSN())
-}
-
 func TestGetTopProducts(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -67,6 +56,7 @@ func TestGetTopProducts(t *testing.T) {
 			},
 			expectCode: 200,
 			expectBody: `{
+				"message": "top products found",
 				"data": [
 					{"id": 1, "description": "Product 1", "total": 20},
 					{"id": 2, "description": "Product 2", "total": 5}
@@ -77,67 +67,27 @@ func TestGetTopProducts(t *testing.T) {
 			sales:      []internal.SaleAttributes{},
 			products:   []internal.Product{},
 			expectCode: 200,
-			expectBody: `{"data": []}`,
+			expectBody: `{"message": "top products found", "data": []}`,
 		},
 	}
 
 	for idx, testCase := range testCases {
 		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
-			db, err := sql.Open("txdb", "fantasy_products_test")
-			require.NoError(t, err)
-			defer db.Close()
+			db := repository.NewMemoryDB()
+			pr := repository.NewProductsMemory(db)
 
-			defer func(db *sql.DB) {
-				// delete records
-				_, err := db.Exec("DELETE FROM sales")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM products")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM invoices")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("DELETE FROM customers")
-				if err != nil {
-					panic(err)
-				}
-				// reset auto increment
-				_, err = db.Exec("ALTER TABLE sales AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("ALTER TABLE products AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-				_, err = db.Exec("ALTER TABLE invoices AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
+			err := func(db *repository.MemoryDB) error {
+				c := internal.Customer{
+					CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe", Condition: 1},
 				}
-				_, err = db.Exec("ALTER TABLE customers AUTO_INCREMENT = 0")
-				if err != nil {
-					panic(err)
-				}
-			}(db)
-
-			err = func(db *sql.DB) error {
-				_, err := db.Exec(
-					"INSERT INTO customers (`id`, `first_name`, `last_name`, `condition`) VALUES (?, ?, ?, ?)",
-					1, "John", "Doe", 1,
-				)
-				if err != nil {
+				if err := repository.NewCustomersMemory(db).Save(context.Background(), &c); err != nil {
 					return err
 				}
 
-				_, err = db.Exec(
-					"INSERT INTO invoices (`id`, `customer_id`, `datetime`, `total`) VALUES (?, ?, ?, ?)",
-					1, 1, "2021-01-01 00:00:00", 42.00,
-				)
-				if err != nil {
+				i := internal.Invoice{
+					InvoiceAttributes: internal.InvoiceAttributes{Datetime: "2021-01-01 00:00:00", Total: internal.MustParseMoney("42.00"), CustomerId: c.Id},
+				}
+				if err := repository.NewInvoicesMemory(db).Save(context.Background(), &i); err != nil {
 					return err
 				}
 
@@ -145,35 +95,18 @@ func TestGetTopProducts(t *testing.T) {
 			}(db)
 			require.NoError(t, err)
 
-			err = func(db *sql.DB) error {
-				for _, productAttr := range testCase.products {
-					_, err := db.Exec(
-						"INSERT INTO products (`id`, `description`) VALUES (?, ?)",
-						productAttr.Id, productAttr.Description,
-					)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}(db)
-			require.NoError(t, err)
+			for _, product := range testCase.products {
+				err := pr.Save(internal.WithStockUntracked(context.Background()), &product)
+				require.NoError(t, err)
+			}
 
-			err = func(db *sql.DB) error {
-				for _, saleAttr := range testCase.sales {
-					_, err := db.Exec(
-						"INSERT INTO sales (`quantity`, `product_id`, `invoice_id`) VALUES (?, ?, ?)",
-						saleAttr.Quantity, saleAttr.ProductId, saleAttr.InvoiceId,
-					)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}(db)
-			require.NoError(t, err)
+			sr := repository.NewSalesMemory(db)
+			for _, saleAttr := range testCase.sales {
+				s := internal.Sale{SaleAttributes: saleAttr}
+				err := sr.Save(context.Background(), &s)
+				require.NoError(t, err)
+			}
 
-			pr := repository.NewProductsMySQL(db)
 			ps := service.NewProductsDefault(pr)
 			h := handler.NewProductsDefault(ps)
 
@@ -188,3 +121,178 @@ func TestGetTopProducts(t *testing.T) {
 		})
 	}
 }
+
+func TestProductPrices(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success update price",
+			method:     "PUT",
+			path:       "/products/1/price",
+			body:       `{"price": 12.5}`,
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "product price updated", "data": {"id": 1, "description": "Product 1", "price": 12.50, "currency": "USD", "tax_category": "standard", "sku": null, "category_id": null, "unit": "unit", "archived": false}}`,
+		}, {
+			name:       "success get prices",
+			method:     "GET",
+			path:       "/products/1/prices",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "product prices found", "data": [{"id": 1, "product_id": 1, "price": 10.00, "currency": "USD", "valid_from": "{valid_from}", "valid_to": null}]}`,
+		}, {
+			name:       "product not found",
+			method:     "PUT",
+			path:       "/products/2/price",
+			body:       `{"price": 12.5}`,
+			expectCode: http.StatusNotFound,
+			expectBody: `{"type": "urn:app:problem:not_found", "title": "Not Found", "status": 404, "code": "not_found", "detail": "product not found: 2"}`,
+		}, {
+			name:       "invalid product id",
+			method:     "GET",
+			path:       "/products/abc/prices",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type": "urn:app:problem:invalid_parameter", "title": "Bad Request", "status": 400, "code": "invalid_parameter", "detail": "invalid product id", "errors": [{"field": "id", "message": "must be a positive integer"}]}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			pr := repository.NewProductsMemory(db)
+			p := internal.Product{ProductAttributes: internal.ProductAttributes{Description: "Product 1", Price: internal.MustParseMoney("10")}}
+			err := pr.Save(context.Background(), &p)
+			require.NoError(t, err)
+
+			h := handler.NewProductsDefault(service.NewProductsDefault(pr))
+			rt := chi.NewRouter()
+			rt.Get("/products/{id}/prices", h.GetPrices())
+			rt.Put("/products/{id}/price", h.UpdatePrice())
+
+			request := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			rt.ServeHTTP(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			// - the price is valid from the time of the test
+			pp, err := pr.FindPrices(context.Background(), p.Id)
+			require.NoError(t, err)
+			require.JSONEq(t, strings.ReplaceAll(testCase.expectBody, "{valid_from}", pp[0].ValidFrom), response.Body.String())
+		})
+	}
+}
+
+func TestProductCatalog(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success create product with sku, category and unit",
+			method:     "POST",
+			path:       "/products",
+			body:       `{"description": "Product 2", "price": 5, "sku": "SKU-2", "category_id": 1, "unit": "kg"}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "product created", "data": {"id": 2, "description": "Product 2", "price": 5.00, "currency": "USD", "tax_category": "standard", "sku": "SKU-2", "category_id": 1, "unit": "kg", "archived": false}}`,
+		}, {
+			name:       "sku already exists",
+			method:     "POST",
+			path:       "/products",
+			body:       `{"description": "Product 2", "price": 5, "sku": "SKU-1"}`,
+			expectCode: http.StatusConflict,
+			expectBody: `{"type": "urn:app:problem:conflict", "title": "Conflict", "status": 409, "code": "conflict", "detail": "product sku exists: SKU-1"}`,
+		}, {
+			name:       "category not found",
+			method:     "POST",
+			path:       "/products",
+			body:       `{"description": "Product 2", "price": 5, "category_id": 9}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid product", "errors": [{"field": "category_id", "message": "must be an existing category"}]}`,
+		}, {
+			name:       "invalid sku",
+			method:     "POST",
+			path:       "/products",
+			body:       `{"description": "Product 2", "price": 5, "sku": "SKU 2"}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid product", "errors": [{"field": "sku", "message": "must be at most 45 characters without spaces"}]}`,
+		}, {
+			name:       "success archive product",
+			method:     "PUT",
+			path:       "/products/1/archived",
+			body:       `{"archived": true}`,
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "product archived flag updated", "data": {"id": 1, "description": "Product 1", "price": 10.00, "currency": "USD", "tax_category": "standard", "sku": "SKU-1", "category_id": 1, "unit": "unit", "archived": true}}`,
+		}, {
+			name:       "archived flag required",
+			method:     "PUT",
+			path:       "/products/1/archived",
+			body:       `{}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid archived flag", "errors": [{"field": "archived", "message": "is required"}]}`,
+		}, {
+			name:       "archive product not found",
+			method:     "PUT",
+			path:       "/products/2/archived",
+			body:       `{"archived": true}`,
+			expectCode: http.StatusNotFound,
+			expectBody: `{"type": "urn:app:problem:not_found", "title": "Not Found", "status": 404, "code": "not_found", "detail": "product not found: 2"}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			c := internal.Category{CategoryAttributes: internal.CategoryAttributes{Name: "Food"}}
+			err := repository.NewCategoriesMemory(db).Save(context.Background(), &c)
+			require.NoError(t, err)
+			pr := repository.NewProductsMemory(db)
+			p := internal.Product{ProductAttributes: internal.ProductAttributes{Description: "Product 1", Price: internal.MustParseMoney("10"), Sku: "SKU-1", CategoryId: c.Id}}
+			err = pr.Save(context.Background(), &p)
+			require.NoError(t, err)
+
+			h := handler.NewProductsDefault(service.NewProductsDefault(pr))
+			rt := chi.NewRouter()
+			rt.Post("/products", h.Create())
+			rt.Put("/products/{id}/archived", h.UpdateArchived())
+
+			request := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			rt.ServeHTTP(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			require.JSONEq(t, testCase.expectBody, response.Body.String())
+		})
+	}
+}
+
+func TestSearchProducts(t *testing.T) {
+	db := repository.NewMemoryDB()
+	pr := repository.NewProductsMemory(db)
+	for _, d := range []string{"Coffee beans", "Coffee mug", "Green tea"} {
+		err := pr.Save(context.Background(), &internal.Product{ProductAttributes: internal.ProductAttributes{Description: d, Price: internal.MustParseMoney("10")}})
+		require.NoError(t, err)
+	}
+	h := handler.NewProductsDefault(service.NewProductsDefault(pr))
+
+	request := httptest.NewRequest("GET", "/products/search?q=cofee+mu", nil)
+	response := httptest.NewRecorder()
+
+	h.Search()(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.JSONEq(t, `{"message": "products found", "data": {"total": 1, "limit": 20, "offset": 0, "results": [
+		{"id": 2, "description": "Coffee mug", "price": 10.00, "currency": "USD", "tax_category": "standard", "sku": null, "category_id": null, "unit": "unit", "archived": false, "score": 1.25}
+	]}}`, response.Body.String())
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/request"
+	"app/platform/web/response"
+)
+
+// NewPromotionsDefault returns a new PromotionsDefault
+func NewPromotionsDefault(sv internal.ServicePromotion) *PromotionsDefault {
+	return &PromotionsDefault{sv: sv}
+}
+
+// PromotionsDefault is a struct that returns the promotion handlers
+type PromotionsDefault struct {
+	// sv is the promotion's service
+	sv internal.ServicePromotion
+}
+
+// PromotionJSON is a struct that represents a promotion in JSON format
+type PromotionJSON struct {
+	Id        int           `json:"id"`
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	ProductId *int          `json:"product_id"`
+	Condition *int          `json:"condition"`
+	Percent   internal.Rate `json:"percent"`
+	Buy       int           `json:"buy"`
+	Get       int           `json:"get"`
+	ValidFrom string        `json:"valid_from"`
+	ValidTo   *string       `json:"valid_to"`
+	Stackable bool          `json:"stackable"`
+}
+
+// promotionJSON serializes the promotion p.
+func promotionJSON(p internal.Promotion) PromotionJSON {
+	pr := PromotionJSON{
+		Id:        p.Id,
+		Name:      p.Name,
+		Type:      p.Kind,
+		ProductId: p.ProductId,
+		Condition: p.Condition,
+		Percent:   p.Percent,
+		Buy:       p.Buy,
+		Get:       p.Get,
+		ValidFrom: p.ValidFrom,
+		Stackable: p.Stackable,
+	}
+	if p.ValidTo != "" {
+		validTo := p.ValidTo
+		pr.ValidTo = &validTo
+	}
+	return pr
+}
+
+// GetAll returns all promotions
+func (h *PromotionsDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		p, err := h.sv.FindAll(r.Context())
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting promotions", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting promotions")
+			return
+		}
+
+		// response
+		// - serialize
+		prJSON := make([]PromotionJSON, len(p))
+		for ix, v := range p {
+			prJSON[ix] = promotionJSON(v)
+		}
+		response.OK(w, "promotions found", prJSON)
+	}
+}
+
+// RequestBodyPromotion is a struct that represents the request body for a promotion
+type RequestBodyPromotion struct {
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	ProductId *int          `json:"product_id"`
+	Condition *int          `json:"condition"`
+	Percent   internal.Rate `json:"percent"`
+	Buy       int           `json:"buy"`
+	Get       int           `json:"get"`
+	ValidFrom string        `json:"valid_from"`
+	ValidTo   *string       `json:"valid_to"`
+	Stackable bool          `json:"stackable"`
+}
+
+// Create creates a new promotion
+func (h *PromotionsDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - body
+		var reqBody RequestBodyPromotion
+		err := request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		// - validate
+		p, fields := promotion(reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid promotion", fields...)
+			return
+		}
+		// - save
+		err = h.sv.Save(r.Context(), &p)
+		if errors.Is(err, internal.ErrProductNotFound) {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, err.Error(),
+				response.FieldError{Field: "product_id", Message: "must be an existing product"})
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving promotion", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error saving promotion")
+			return
+		}
+
+		// response
+		// - serialize
+		response.Created(w, "promotion created", promotionJSON(p))
+	}
+}
+
+// promotion validates the request body of a promotion, returning the errors of its invalid fields.
+// The attributes that do not apply to its type are left out.
+func promotion(reqBody RequestBodyPromotion) (p internal.Promotion, fields []response.FieldError) {
+	p.Name = reqBody.Name
+	if p.Name == "" || utf8.RuneCountInString(p.Name) > 100 {
+		fields = append(fields, response.FieldError{Field: "name", Message: "must be between 1 and 100 characters"})
+	}
+
+	p.Kind = reqBody.Type
+	switch p.Kind {
+	case internal.PromotionPercentage:
+		p.Percent = reqBody.Percent
+		if p.Percent <= 0 || p.Percent > internal.MustParseRate("1") {
+			fields = append(fields, response.FieldError{Field: "percent", Message: "must be greater than 0 and at most 1"})
+		}
+	case internal.PromotionBuyXGetY:
+		p.Buy, p.Get = reqBody.Buy, reqBody.Get
+		if p.Buy <= 0 {
+			fields = append(fields, response.FieldError{Field: "buy", Message: "must be greater than 0"})
+		}
+		if p.Get <= 0 {
+			fields = append(fields, response.FieldError{Field: "get", Message: "must be greater than 0"})
+		}
+	default:
+		fields = append(fields, response.FieldError{Field: "type", Message: "must be percentage or buy_x_get_y"})
+	}
+
+	p.ProductId = reqBody.ProductId
+	if p.ProductId != nil && *p.ProductId <= 0 {
+		fields = append(fields, response.FieldError{Field: "product_id", Message: "must be a positive integer"})
+	}
+
+	p.Condition = reqBody.Condition
+	if p.Condition != nil && *p.Condition < 0 {
+		fields = append(fields, response.FieldError{Field: "condition", Message: "must be greater than or equal to 0"})
+	}
+
+	p.ValidFrom = reqBody.ValidFrom
+	_, errFrom := time.Parse(time.DateOnly, p.ValidFrom)
+	if errFrom != nil {
+		fields = append(fields, response.FieldError{Field: "valid_from", Message: "must be a date, YYYY-MM-DD"})
+	}
+	if reqBody.ValidTo != nil {
+		p.ValidTo = *reqBody.ValidTo
+		if _, err := time.Parse(time.DateOnly, p.ValidTo); err != nil {
+			fields = append(fields, response.FieldError{Field: "valid_to", Message: "must be a date, YYYY-MM-DD"})
+		} else if errFrom == nil && p.ValidTo < p.ValidFrom {
+			fields = append(fields, response.FieldError{Field: "valid_to", Message: "must not be before valid_from"})
+		}
+	}
+
+	p.Stackable = reqBody.Stackable
+	return
+}
+
+// SaleDiscountJSON is a struct that represents a discount of a promotion applied to a sale in JSON format
+type SaleDiscountJSON struct {
+	PromotionId int            `json:"promotion_id"`
+	Amount      internal.Money `json:"amount"`
+}
+
+// saleDiscountsJSON serializes the discounts d of a sale, an empty list if there is none.
+func saleDiscountsJSON(d []internal.SaleDiscount) []SaleDiscountJSON {
+	dJSON := make([]SaleDiscountJSON, len(d))
+	for ix, v := range d {
+		dJSON[ix] = SaleDiscountJSON{PromotionId: v.PromotionId, Amount: v.Amount}
+	}
+	return dJSON
+}
@@ -0,0 +1,88 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	testCases := []struct {
+		name       string
+		products   []internal.ProductAttributes
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success percentage for a condition",
+			body:       `{"name": "preferred", "type": "percentage", "condition": 1, "percent": 0.1, "buy": 2, "valid_from": "2024-01-01", "stackable": true}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "promotion created", "data": {"id": 1, "name": "preferred", "type": "percentage", "product_id": null, "condition": 1, "percent": 0.1, "buy": 0, "get": 0, "valid_from": "2024-01-01", "valid_to": null, "stackable": true}}`,
+		}, {
+			name:       "success buy x get y for a product",
+			products:   []internal.ProductAttributes{{Description: "Product 1", Price: internal.MustParseMoney("10")}},
+			body:       `{"name": "3x2", "type": "buy_x_get_y", "product_id": 1, "buy": 2, "get": 1, "valid_from": "2024-01-01", "valid_to": "2024-01-31"}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "promotion created", "data": {"id": 1, "name": "3x2", "type": "buy_x_get_y", "product_id": 1, "condition": null, "percent": 0, "buy": 2, "get": 1, "valid_from": "2024-01-01", "valid_to": "2024-01-31", "stackable": false}}`,
+		}, {
+			name:       "invalid fields",
+			body:       `{"name": "", "type": "buy_x_get_y", "product_id": 0, "buy": 0, "get": 1, "valid_from": "2024-02-01", "valid_to": "2024-01-31"}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid promotion", "errors": [
+				{"field": "name", "message": "must be between 1 and 100 characters"},
+				{"field": "buy", "message": "must be greater than 0"},
+				{"field": "product_id", "message": "must be a positive integer"},
+				{"field": "valid_to", "message": "must not be before valid_from"}
+			]}`,
+		}, {
+			name:       "invalid type",
+			body:       `{"name": "free", "type": "free", "valid_from": "2024-01-01"}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid promotion", "errors": [
+				{"field": "type", "message": "must be percentage or buy_x_get_y"}
+			]}`,
+		}, {
+			name:       "unknown product",
+			body:       `{"name": "3x2", "type": "buy_x_get_y", "product_id": 1, "buy": 2, "get": 1, "valid_from": "2024-01-01"}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "product not found: 1", "errors": [
+				{"field": "product_id", "message": "must be an existing product"}
+			]}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			pr := repository.NewProductsMemory(db)
+			rp := repository.NewPromotionsMemory(db)
+
+			for _, productAttr := range testCase.products {
+				p := internal.Product{ProductAttributes: productAttr}
+				err := pr.Save(context.Background(), &p)
+				require.NoError(t, err)
+			}
+
+			h := handler.NewPromotionsDefault(service.NewPromotionsDefault(rp))
+
+			request := httptest.NewRequest("POST", "/promotions", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			h.Create()(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			require.JSONEq(t, testCase.expectBody, response.Body.String())
+		})
+	}
+}
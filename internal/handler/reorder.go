@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/response"
+)
+
+// NewReorderDefault returns a new ReorderDefault
+func NewReorderDefault(sv internal.ServiceReorder) *ReorderDefault {
+	return &ReorderDefault{sv: sv}
+}
+
+// ReorderDefault is a struct that returns the reorder suggestion and stock alert handlers
+type ReorderDefault struct {
+	// sv is the reorder's service
+	sv internal.ServiceReorder
+}
+
+// ReorderSuggestionJSON is a struct that represents a reorder suggestion in JSON format
+type ReorderSuggestionJSON struct {
+	ProductId   int     `json:"product_id"`
+	Description string  `json:"description"`
+	Stock       int     `json:"stock"`
+	Sold        int     `json:"sold"`
+	DailySales  float64 `json:"daily_sales"`
+	DaysOfCover float64 `json:"days_of_cover"`
+	Quantity    int     `json:"quantity"`
+}
+
+// reorderSuggestionJSON serializes the reorder suggestion r.
+func reorderSuggestionJSON(r internal.ReorderSuggestion) ReorderSuggestionJSON {
+	return ReorderSuggestionJSON{
+		ProductId:   r.ProductId,
+		Description: r.Description,
+		Stock:       r.Stock,
+		Sold:        r.Sold,
+		DailySales:  r.DailySales,
+		DaysOfCover: r.DaysOfCover,
+		Quantity:    r.Quantity,
+	}
+}
+
+// GetSuggestions returns the reorder suggestions of the products whose stock runs low
+func (h *ReorderDefault) GetSuggestions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		s, err := h.sv.Suggestions(r.Context())
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting reorder suggestions", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting reorder suggestions")
+			return
+		}
+
+		// response
+		// - serialize
+		sJSON := make([]ReorderSuggestionJSON, len(s))
+		for ix, v := range s {
+			sJSON[ix] = reorderSuggestionJSON(v)
+		}
+		response.OK(w, "reorder suggestions found", sJSON)
+	}
+}
+
+// StockAlertJSON is a struct that represents a stock alert in JSON format
+type StockAlertJSON struct {
+	Id   int    `json:"id"`
+	Date string `json:"date"`
+	ReorderSuggestionJSON
+}
+
+// GetAlerts returns all stock alerts
+func (h *ReorderDefault) GetAlerts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		a, err := h.sv.Alerts(r.Context())
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting stock alerts", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting stock alerts")
+			return
+		}
+
+		// response
+		// - serialize
+		aJSON := make([]StockAlertJSON, len(a))
+		for ix, v := range a {
+			aJSON[ix] = StockAlertJSON{Id: v.Id, Date: v.Date, ReorderSuggestionJSON: reorderSuggestionJSON(v.ReorderSuggestion)}
+		}
+		response.OK(w, "stock alerts found", aJSON)
+	}
+}
@@ -0,0 +1,85 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorder(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		raiseAlerts bool
+		expectCode  int
+		expectBody  string
+	}{
+		{
+			name:       "success get suggestions",
+			path:       "/products/reorder",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "reorder suggestions found", "data": [{"product_id": 1, "description": "Product 1", "stock": 2, "sold": 30, "daily_sales": 1, "days_of_cover": 2, "quantity": 28}]}`,
+		}, {
+			name:       "success get alerts, none raised",
+			path:       "/stock_alerts",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "stock alerts found", "data": []}`,
+		}, {
+			name:        "success get alerts",
+			path:        "/stock_alerts",
+			raiseAlerts: true,
+			expectCode:  http.StatusOK,
+			expectBody:  `{"message": "stock alerts found", "data": [{"id": 1, "date": "{date}", "product_id": 1, "description": "Product 1", "stock": 2, "sold": 30, "daily_sales": 1, "days_of_cover": 2, "quantity": 28}]}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			// - a product selling 30 units over the window with 2 left, and another with plenty
+			db := repository.NewMemoryDB()
+			cs := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe"}}
+			require.NoError(t, repository.NewCustomersMemory(db).Save(context.Background(), &cs))
+			rpStock := repository.NewStocksMemory(db)
+			iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Datetime: time.Now().UTC().Format(time.DateTime), CustomerId: cs.Id}}
+			require.NoError(t, repository.NewInvoicesMemory(db).Save(context.Background(), &iv))
+			for ix, units := range []int{32, 100} {
+				p := internal.Product{ProductAttributes: internal.ProductAttributes{Description: fmt.Sprintf("Product %d", ix+1), Price: internal.MustParseMoney("10")}}
+				require.NoError(t, repository.NewProductsMemory(db).Save(context.Background(), &p))
+				m := internal.StockMovement{StockMovementAttributes: internal.StockMovementAttributes{ProductId: p.Id, Kind: internal.StockMovementReceipt, Quantity: units}}
+				require.NoError(t, rpStock.SaveMovement(context.Background(), &m))
+				sa := internal.Sale{SaleAttributes: internal.SaleAttributes{Quantity: 30, ProductId: p.Id, InvoiceId: iv.Id}}
+				require.NoError(t, repository.NewSalesMemory(db).Save(context.Background(), &sa))
+			}
+			sv := service.NewReorderDefault(rpStock, repository.NewStockAlertsMemory(db), internal.ReorderPolicyDefault, false)
+			if testCase.raiseAlerts {
+				_, err := sv.RaiseAlerts(context.Background())
+				require.NoError(t, err)
+			}
+
+			h := handler.NewReorderDefault(sv)
+			rt := chi.NewRouter()
+			rt.Get("/products/reorder", h.GetSuggestions())
+			rt.Get("/stock_alerts", h.GetAlerts())
+
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			response := httptest.NewRecorder()
+
+			rt.ServeHTTP(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			// - the alerts are dated at the time of the test
+			require.JSONEq(t, strings.ReplaceAll(testCase.expectBody, "{date}", time.Now().UTC().Format(time.DateOnly)), response.Body.String())
+		})
+	}
+}
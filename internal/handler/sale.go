@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"unicode/utf8"
 
 	"app/internal"
+	"app/platform/logger"
 	"app/platform/web/request"
 	"app/platform/web/response"
 )
@@ -25,6 +28,11 @@ type SaleJSON struct {
 	Quantity int `json:"quantity"`
 	ProductId int `json:"product_id"`
 	InvoiceId int `json:"invoice_id"`
+	Warehouse string `json:"warehouse"`
+	UnitPrice internal.Money `json:"unit_price"`
+	TaxRate internal.Rate `json:"tax_rate"`
+	Discount internal.Money `json:"discount"`
+	Discounts []SaleDiscountJSON `json:"discounts"`
 }
 
 // GetAll returns all sales
@@ -34,8 +42,9 @@ func (h *SalesDefault) GetAll() http.HandlerFunc {
 		// ...
 
 		// process
-		s, err := h.sv.FindAll()
+		s, err := h.sv.FindAll(r.Context())
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting sales", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error getting sales")
 			return
 		}
@@ -49,12 +58,14 @@ func (h *SalesDefault) GetAll() http.HandlerFunc {
 				Quantity: v.Quantity,
 				ProductId:  v.ProductId,
 				InvoiceId: v.InvoiceId,
+				Warehouse: v.Warehouse,
+				UnitPrice: v.UnitPrice,
+				TaxRate: v.TaxRate,
+				Discount: v.Discount,
+				Discounts: saleDiscountsJSON(v.Discounts),
 			}
 		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "sales found",
-			"data":    sJSON,
-		})
+		response.OK(w, "sales found", sJSON)
 	}
 }
 
@@ -63,6 +74,7 @@ type RequestBodySale struct {
 	Quantity int `json:"quantity"`
 	ProductId int `json:"product_id"`
 	InvoiceId int `json:"invoice_id"`
+	Warehouse string `json:"warehouse"`
 }
 // Create creates a new sale
 func (h *SalesDefault) Create() http.HandlerFunc {
@@ -72,22 +84,52 @@ func (h *SalesDefault) Create() http.HandlerFunc {
 		var reqBody RequestBodySale
 		err := request.JSON(r, &reqBody)
 		if err != nil {
-			response.Error(w, http.StatusBadRequest, "error parsing request body")
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
 			return
 		}
 
 		// process
+		// - validate
+		var fields []response.FieldError
+		if reqBody.Quantity <= 0 {
+			fields = append(fields, response.FieldError{Field: "quantity", Message: "must be greater than 0"})
+		}
+		if utf8.RuneCountInString(reqBody.Warehouse) > warehouseMaxLen {
+			fields = append(fields, errWarehouseField)
+		}
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid sale", fields...)
+			return
+		}
 		// - deserialize
 		s := internal.Sale{
 			SaleAttributes: internal.SaleAttributes{
 				Quantity: reqBody.Quantity,
 				ProductId: reqBody.ProductId,
 				InvoiceId: reqBody.InvoiceId,
+				Warehouse: reqBody.Warehouse,
 			},
 		}
 		// - save
-		err = h.sv.Save(&s)
+		err = h.sv.Save(r.Context(), &s)
+		if errors.Is(err, internal.ErrCurrencyMismatch) {
+			logger.FromContext(r.Context()).Debug("error saving sale", "error", err)
+			response.ErrorCode(w, http.StatusUnprocessableEntity, CodeCurrencyMismatch, err.Error())
+			return
+		}
+		if errors.Is(err, internal.ErrStockInsufficient) {
+			logger.FromContext(r.Context()).Debug("error saving sale", "error", err)
+			response.ErrorCode(w, http.StatusConflict, CodeStockInsufficient, err.Error())
+			return
+		}
+		if errors.Is(err, internal.ErrProductArchived) {
+			logger.FromContext(r.Context()).Debug("error saving sale", "error", err)
+			response.ErrorCode(w, http.StatusConflict, CodeProductArchived, err.Error())
+			return
+		}
 		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving sale", "error", err)
 			response.Error(w, http.StatusInternalServerError, "error saving sale")
 			return
 		}
@@ -99,10 +141,12 @@ func (h *SalesDefault) Create() http.HandlerFunc {
 			Quantity: s.Quantity,
 			ProductId:  s.ProductId,
 			InvoiceId: s.InvoiceId,
+			Warehouse: s.Warehouse,
+			UnitPrice: s.UnitPrice,
+			TaxRate: s.TaxRate,
+			Discount: s.Discount,
+			Discounts: saleDiscountsJSON(s.Discounts),
 		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "sale created",
-			"data":    sa,
-		})
+		response.Created(w, "sale created", sa)
 	}
 }
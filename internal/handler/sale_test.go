@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaleCreate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		body        string
+		expectCode  int
+		expectBody  string
+		expectStock int
+	}{
+		{
+			name:        "success",
+			body:        `{"quantity": 2, "product_id": 1, "invoice_id": 1}`,
+			expectCode:  http.StatusCreated,
+			expectBody:  `{"message": "sale created", "data": {"id": 1, "quantity": 2, "product_id": 1, "invoice_id": 1, "warehouse": "main", "unit_price": 10.00, "tax_rate": 0, "discount": 0.00, "discounts": []}}`,
+			expectStock: 0,
+		}, {
+			name:       "negative quantity",
+			body:       `{"quantity": -100, "product_id": 1, "invoice_id": 1}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid sale", "errors": [
+				{"field": "quantity", "message": "must be greater than 0"}
+			]}`,
+			expectStock: 2,
+		}, {
+			name:       "invalid fields",
+			body:       `{"quantity": 0, "product_id": 1, "invoice_id": 1, "warehouse": "` + strings.Repeat("w", 46) + `"}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid sale", "errors": [
+				{"field": "quantity", "message": "must be greater than 0"},
+				{"field": "warehouse", "message": "must be at most 45 characters"}
+			]}`,
+			expectStock: 2,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			// - a product with 2 units in stock
+			db := repository.NewMemoryDB()
+			cs := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe"}}
+			require.NoError(t, repository.NewCustomersMemory(db).Save(context.Background(), &cs))
+			iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Datetime: "2024-01-01 00:00:00", CustomerId: cs.Id}}
+			require.NoError(t, repository.NewInvoicesMemory(db).Save(context.Background(), &iv))
+			p := internal.Product{ProductAttributes: internal.ProductAttributes{Description: "Product 1", Price: internal.MustParseMoney("10")}}
+			require.NoError(t, repository.NewProductsMemory(db).Save(context.Background(), &p))
+			rpStock := repository.NewStocksMemory(db)
+			m := internal.StockMovement{StockMovementAttributes: internal.StockMovementAttributes{ProductId: p.Id, Kind: internal.StockMovementReceipt, Quantity: 2}}
+			require.NoError(t, rpStock.SaveMovement(context.Background(), &m))
+
+			h := handler.NewSalesDefault(service.NewSalesDefault(repository.NewSalesMemory(db)))
+			rt := chi.NewRouter()
+			rt.Post("/sales", h.Create())
+
+			request := httptest.NewRequest(http.MethodPost, "/sales", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			rt.ServeHTTP(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			require.JSONEq(t, testCase.expectBody, response.Body.String())
+			s, err := rpStock.FindByProduct(context.Background(), p.Id)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectStock, s.Quantity)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/response"
+)
+
+// SearchPageJSON is a struct that represents a page of search results in JSON format
+type SearchPageJSON[T any] struct {
+	Total   int `json:"total"`
+	Limit   int `json:"limit"`
+	Offset  int `json:"offset"`
+	Results []T `json:"results"`
+}
+
+// errSearchQuery is used when a query parameter of a search is invalid.
+var errSearchQuery = errors.New("invalid search query")
+
+// searchQuery parses the search from the query parameters q, limit and offset.
+// Errors wrap the *response.FieldError of the parameter.
+func searchQuery(q url.Values) (s internal.SearchQuery, err error) {
+	s = internal.SearchQuery{Text: q.Get("q"), Limit: internal.SearchLimitDefault}
+	if len(s.Terms()) == 0 {
+		return s, fmt.Errorf("%w: %w", errSearchQuery, &response.FieldError{Field: "q", Message: "must have a letter or a digit"})
+	}
+
+	if v := q.Get("limit"); v != "" {
+		s.Limit, err = strconv.Atoi(v)
+		if err != nil || s.Limit <= 0 || s.Limit > internal.SearchLimitMax {
+			return s, fmt.Errorf("%w: %w", errSearchQuery, &response.FieldError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", internal.SearchLimitMax)})
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		s.Offset, err = strconv.Atoi(v)
+		if err != nil || s.Offset < 0 {
+			return s, fmt.Errorf("%w: %w", errSearchQuery, &response.FieldError{Field: "offset", Message: "must be a non-negative integer"})
+		}
+	}
+	return s, nil
+}
+
+// writeSearchQueryError writes the error response of an invalid search query.
+func writeSearchQueryError(w http.ResponseWriter, r *http.Request, err error) {
+	logger.FromContext(r.Context()).Debug("error parsing search query", "error", err)
+	var fieldErr *response.FieldError
+	errors.As(err, &fieldErr)
+	response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidParameter, errSearchQuery.Error(), *fieldErr)
+}
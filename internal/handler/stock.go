@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/request"
+	"app/platform/web/response"
+)
+
+// CodeStockInsufficient is the problem code of a sale or an adjustment taking more units than its warehouse holds.
+const CodeStockInsufficient = "stock_insufficient"
+
+// warehouseMaxLen is the maximum length of a warehouse, the one of its column.
+const warehouseMaxLen = 45
+
+// errWarehouseField is the error of an invalid warehouse.
+var errWarehouseField = response.FieldError{Field: "warehouse", Message: "must be at most 45 characters"}
+
+// NewStocksDefault returns a new StocksDefault
+func NewStocksDefault(sv internal.ServiceStock) *StocksDefault {
+	return &StocksDefault{sv: sv}
+}
+
+// StocksDefault is a struct that returns the stock handlers
+type StocksDefault struct {
+	// sv is the stock's service
+	sv internal.ServiceStock
+}
+
+// WarehouseStockJSON is a struct that represents the units of a product held in a warehouse in JSON format
+type WarehouseStockJSON struct {
+	Warehouse string `json:"warehouse"`
+	Quantity  int    `json:"quantity"`
+}
+
+// StockJSON is a struct that represents the stock of a product in JSON format
+type StockJSON struct {
+	ProductId  int                  `json:"product_id"`
+	Tracked    bool                 `json:"tracked"`
+	Quantity   int                  `json:"quantity"`
+	Warehouses []WarehouseStockJSON `json:"warehouses"`
+}
+
+// Get returns the stock of a product
+func (h *StocksDefault) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path
+		id, ok := productId(w, r)
+		if !ok {
+			return
+		}
+
+		// process
+		s, err := h.sv.FindByProduct(r.Context(), id)
+		if errors.Is(err, internal.ErrProductNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting product stock", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting product stock")
+			return
+		}
+
+		// response
+		// - serialize
+		sJSON := StockJSON{
+			ProductId:  s.ProductId,
+			Tracked:    s.Tracked,
+			Quantity:   s.Quantity,
+			Warehouses: make([]WarehouseStockJSON, len(s.Warehouses)),
+		}
+		for ix, v := range s.Warehouses {
+			sJSON.Warehouses[ix] = WarehouseStockJSON{Warehouse: v.Warehouse, Quantity: v.Quantity}
+		}
+		response.OK(w, "product stock found", sJSON)
+	}
+}
+
+// StockMovementJSON is a struct that represents a stock movement in JSON format
+type StockMovementJSON struct {
+	Id        int    `json:"id"`
+	ProductId int    `json:"product_id"`
+	Warehouse string `json:"warehouse"`
+	Type      string `json:"type"`
+	Quantity  int    `json:"quantity"`
+	SaleId    *int   `json:"sale_id"`
+	Datetime  string `json:"datetime"`
+}
+
+// stockMovementJSON serializes the stock movement m.
+func stockMovementJSON(m internal.StockMovement) StockMovementJSON {
+	mv := StockMovementJSON{
+		Id:        m.Id,
+		ProductId: m.ProductId,
+		Warehouse: m.Warehouse,
+		Type:      m.Kind,
+		Quantity:  m.Quantity,
+		Datetime:  m.Datetime,
+	}
+	if m.SaleId != 0 {
+		saleId := m.SaleId
+		mv.SaleId = &saleId
+	}
+	return mv
+}
+
+// GetMovements returns the stock movements of a product
+func (h *StocksDefault) GetMovements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path
+		id, ok := productId(w, r)
+		if !ok {
+			return
+		}
+
+		// process
+		m, err := h.sv.FindMovements(r.Context(), id)
+		if errors.Is(err, internal.ErrProductNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting stock movements", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting stock movements")
+			return
+		}
+
+		// response
+		// - serialize
+		mJSON := make([]StockMovementJSON, len(m))
+		for ix, v := range m {
+			mJSON[ix] = stockMovementJSON(v)
+		}
+		response.OK(w, "stock movements found", mJSON)
+	}
+}
+
+// RequestBodyStockMovement is a struct that represents the request body for a stock movement
+type RequestBodyStockMovement struct {
+	Type      string `json:"type"`
+	Warehouse string `json:"warehouse"`
+	Quantity  int    `json:"quantity"`
+}
+
+// CreateMovement records a receipt or an adjustment of the stock of a product
+func (h *StocksDefault) CreateMovement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - path
+		id, ok := productId(w, r)
+		if !ok {
+			return
+		}
+		// - body
+		var reqBody RequestBodyStockMovement
+		err := request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		// - validate
+		m, fields := stockMovement(id, reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid stock movement", fields...)
+			return
+		}
+		// - save
+		err = h.sv.SaveMovement(r.Context(), &m)
+		if errors.Is(err, internal.ErrProductNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, internal.ErrStockInsufficient) {
+			logger.FromContext(r.Context()).Debug("error saving stock movement", "error", err)
+			response.ErrorCode(w, http.StatusConflict, CodeStockInsufficient, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving stock movement", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error saving stock movement")
+			return
+		}
+
+		// response
+		// - serialize
+		response.Created(w, "stock movement created", stockMovementJSON(m))
+	}
+}
+
+// stockMovement validates the request body of a stock movement of the product id, returning the errors of its
+// invalid fields. Only receipts and adjustments are recorded: the sales take their units themselves.
+func stockMovement(id int, reqBody RequestBodyStockMovement) (m internal.StockMovement, fields []response.FieldError) {
+	m.ProductId = id
+
+	m.Kind = reqBody.Type
+	m.Quantity = reqBody.Quantity
+	switch m.Kind {
+	case internal.StockMovementReceipt:
+		if m.Quantity <= 0 {
+			fields = append(fields, response.FieldError{Field: "quantity", Message: "must be greater than 0"})
+		}
+	case internal.StockMovementAdjustment:
+		if m.Quantity == 0 {
+			fields = append(fields, response.FieldError{Field: "quantity", Message: "must not be 0"})
+		}
+	default:
+		fields = append(fields, response.FieldError{Field: "type", Message: "must be receipt or adjustment"})
+	}
+
+	m.Warehouse = reqBody.Warehouse
+	if utf8.RuneCountInString(m.Warehouse) > warehouseMaxLen {
+		fields = append(fields, errWarehouseField)
+	}
+	return
+}
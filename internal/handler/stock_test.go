@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductStock(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success get stock",
+			method:     "GET",
+			path:       "/products/1/stock",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "product stock found", "data": {"product_id": 1, "tracked": true, "quantity": 10, "warehouses": [{"warehouse": "main", "quantity": 10}]}}`,
+		}, {
+			name:       "success get movements",
+			method:     "GET",
+			path:       "/products/1/stock/movements",
+			expectCode: http.StatusOK,
+			expectBody: `{"message": "stock movements found", "data": [{"id": 1, "product_id": 1, "warehouse": "main", "type": "receipt", "quantity": 10, "sale_id": null, "datetime": "{datetime}"}]}`,
+		}, {
+			name:       "success receipt in another warehouse",
+			method:     "POST",
+			path:       "/products/1/stock/movements",
+			body:       `{"type": "receipt", "warehouse": "north", "quantity": 5}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "stock movement created", "data": {"id": 2, "product_id": 1, "warehouse": "north", "type": "receipt", "quantity": 5, "sale_id": null, "datetime": "{datetime}"}}`,
+		}, {
+			name:       "adjustment below 0",
+			method:     "POST",
+			path:       "/products/1/stock/movements",
+			body:       `{"type": "adjustment", "quantity": -11}`,
+			expectCode: http.StatusConflict,
+			expectBody: `{"type": "urn:app:problem:stock_insufficient", "title": "Conflict", "status": 409, "code": "stock_insufficient", "detail": "insufficient stock: product 1 in warehouse main cannot move -11 units"}`,
+		}, {
+			name:       "invalid fields",
+			method:     "POST",
+			path:       "/products/1/stock/movements",
+			body:       `{"type": "receipt", "quantity": 0}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid stock movement", "errors": [
+				{"field": "quantity", "message": "must be greater than 0"}
+			]}`,
+		}, {
+			name:       "sales are not recorded by hand",
+			method:     "POST",
+			path:       "/products/1/stock/movements",
+			body:       `{"type": "sale", "quantity": -1}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid stock movement", "errors": [
+				{"field": "type", "message": "must be receipt or adjustment"}
+			]}`,
+		}, {
+			name:       "product not found",
+			method:     "GET",
+			path:       "/products/2/stock",
+			expectCode: http.StatusNotFound,
+			expectBody: `{"type": "urn:app:problem:not_found", "title": "Not Found", "status": 404, "code": "not_found", "detail": "product not found: 2"}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			pr := repository.NewProductsMemory(db)
+			p := internal.Product{ProductAttributes: internal.ProductAttributes{Description: "Product 1", Price: internal.MustParseMoney("10")}}
+			err := pr.Save(context.Background(), &p)
+			require.NoError(t, err)
+			rp := repository.NewStocksMemory(db)
+			m := internal.StockMovement{StockMovementAttributes: internal.StockMovementAttributes{ProductId: p.Id, Kind: internal.StockMovementReceipt, Quantity: 10}}
+			err = rp.SaveMovement(context.Background(), &m)
+			require.NoError(t, err)
+
+			h := handler.NewStocksDefault(service.NewStocksDefault(rp))
+			rt := chi.NewRouter()
+			rt.Get("/products/{id}/stock", h.Get())
+			rt.Get("/products/{id}/stock/movements", h.GetMovements())
+			rt.Post("/products/{id}/stock/movements", h.CreateMovement())
+
+			request := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			rt.ServeHTTP(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			// - the movements are dated at the time of the test
+			mm, err := rp.FindMovements(context.Background(), p.Id)
+			require.NoError(t, err)
+			require.JSONEq(t, strings.ReplaceAll(testCase.expectBody, "{datetime}", mm[len(mm)-1].Datetime), response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/web/request"
+	"app/platform/web/response"
+)
+
+// taxCategoryMaxLen is the maximum length of a tax category, the one of its columns.
+const taxCategoryMaxLen = 45
+
+// errTaxCategoryField is the field error of an invalid tax category in a request body.
+var errTaxCategoryField = response.FieldError{Field: "tax_category", Message: "must be at most 45 characters"}
+
+// NewTaxRatesDefault returns a new TaxRatesDefault
+func NewTaxRatesDefault(sv internal.ServiceTaxRate) *TaxRatesDefault {
+	return &TaxRatesDefault{sv: sv}
+}
+
+// TaxRatesDefault is a struct that returns the tax rate handlers
+type TaxRatesDefault struct {
+	// sv is the tax rate's service
+	sv internal.ServiceTaxRate
+}
+
+// TaxRateJSON is a struct that represents a tax rate in JSON format
+type TaxRateJSON struct {
+	Id        int           `json:"id"`
+	Category  string        `json:"category"`
+	Condition *int          `json:"condition"`
+	Rate      internal.Rate `json:"rate"`
+}
+
+// GetAll returns all tax rates
+func (h *TaxRatesDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		t, err := h.sv.FindAll(r.Context())
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error getting tax rates", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error getting tax rates")
+			return
+		}
+
+		// response
+		// - serialize
+		trJSON := make([]TaxRateJSON, len(t))
+		for ix, v := range t {
+			trJSON[ix] = TaxRateJSON{
+				Id:        v.Id,
+				Category:  v.Category,
+				Condition: v.Condition,
+				Rate:      v.Rate,
+			}
+		}
+		response.OK(w, "tax rates found", trJSON)
+	}
+}
+
+// RequestBodyTaxRate is a struct that represents the request body for a tax rate
+type RequestBodyTaxRate struct {
+	Category  string        `json:"category"`
+	Condition *int          `json:"condition"`
+	Rate      internal.Rate `json:"rate"`
+}
+
+// Create creates a new tax rate
+func (h *TaxRatesDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - body
+		var reqBody RequestBodyTaxRate
+		err := request.JSON(r, &reqBody)
+		if err != nil {
+			logger.FromContext(r.Context()).Debug("error parsing request body", "error", err)
+			response.RequestError(w, err)
+			return
+		}
+
+		// process
+		// - validate
+		t, fields := taxRate(reqBody)
+		if len(fields) > 0 {
+			response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeUnprocessable, "invalid tax rate", fields...)
+			return
+		}
+		// - save
+		err = h.sv.Save(r.Context(), &t)
+		if errors.Is(err, internal.ErrTaxRateExists) {
+			response.ErrorCode(w, http.StatusConflict, response.CodeConflict, err.Error())
+			return
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Error("error saving tax rate", "error", err)
+			response.Error(w, http.StatusInternalServerError, "error saving tax rate")
+			return
+		}
+
+		// response
+		// - serialize
+		tr := TaxRateJSON{
+			Id:        t.Id,
+			Category:  t.Category,
+			Condition: t.Condition,
+			Rate:      t.Rate,
+		}
+		response.Created(w, "tax rate created", tr)
+	}
+}
+
+// taxRate validates the request body of a tax rate, returning the errors of its invalid fields.
+func taxRate(reqBody RequestBodyTaxRate) (t internal.TaxRate, fields []response.FieldError) {
+	t.Category = reqBody.Category
+	if utf8.RuneCountInString(t.Category) > taxCategoryMaxLen {
+		fields = append(fields, response.FieldError{Field: "category", Message: errTaxCategoryField.Message})
+	}
+
+	t.Condition = reqBody.Condition
+	if t.Condition != nil && *t.Condition < 0 {
+		fields = append(fields, response.FieldError{Field: "condition", Message: "must be greater than or equal to 0"})
+	}
+
+	t.Rate = reqBody.Rate
+	if t.Rate < 0 || t.Rate > internal.MustParseRate("1") {
+		fields = append(fields, response.FieldError{Field: "rate", Message: "must be between 0 and 1"})
+	}
+	return
+}
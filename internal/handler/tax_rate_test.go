@@ -0,0 +1,77 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTaxRate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		rates      []internal.TaxRateAttributes
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "success for a category",
+			body:       `{"category": "food", "rate": 0.1}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "tax rate created", "data": {"id": 1, "category": "food", "condition": null, "rate": 0.1}}`,
+		}, {
+			name:       "success for an exempt condition",
+			body:       `{"condition": 0, "rate": 0}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"message": "tax rate created", "data": {"id": 1, "category": "", "condition": 0, "rate": 0}}`,
+		}, {
+			name:       "invalid fields",
+			body:       `{"category": "` + strings.Repeat("c", 46) + `", "condition": -1, "rate": 1.5}`,
+			expectCode: http.StatusUnprocessableEntity,
+			expectBody: `{"type": "urn:app:problem:unprocessable", "title": "Unprocessable Entity", "status": 422, "code": "unprocessable", "detail": "invalid tax rate", "errors": [
+				{"field": "category", "message": "must be at most 45 characters"},
+				{"field": "condition", "message": "must be greater than or equal to 0"},
+				{"field": "rate", "message": "must be between 0 and 1"}
+			]}`,
+		}, {
+			name:       "already exists",
+			rates:      []internal.TaxRateAttributes{{Category: "food", Rate: internal.MustParseRate("0.1")}},
+			body:       `{"category": "food", "rate": 0.12}`,
+			expectCode: http.StatusConflict,
+			expectBody: `{"type": "urn:app:problem:conflict", "title": "Conflict", "status": 409, "code": "conflict", "detail": "tax rate already exists: category food and every condition"}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			db := repository.NewMemoryDB()
+			rp := repository.NewTaxRatesMemory(db)
+
+			for _, rateAttr := range testCase.rates {
+				tr := internal.TaxRate{TaxRateAttributes: rateAttr}
+				err := rp.Save(context.Background(), &tr)
+				require.NoError(t, err)
+			}
+
+			h := handler.NewTaxRatesDefault(service.NewTaxRatesDefault(rp))
+
+			request := httptest.NewRequest("POST", "/tax_rates", strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+
+			h.Create()(response, request)
+
+			require.Equal(t, testCase.expectCode, response.Code)
+			require.JSONEq(t, testCase.expectBody, response.Body.String())
+		})
+	}
+}
@@ -4,10 +4,18 @@ package internal
 type InvoiceAttributes struct {
 	// Datetime is the datetime of the invoice.
 	Datetime string
-	// Total is the total of the invoice.
-	Total float64
+	// Discount is the amount the promotions take off the sales of the invoice.
+	Discount Money
+	// Subtotal is the total of the invoice before taxes, net of the discount.
+	Subtotal Money
+	// Tax is the tax amount of the invoice.
+	Tax Money
+	// Total is the grand total of the invoice, Subtotal plus Tax.
+	Total Money
 	// CustomerId is the customer id of the invoice.
 	CustomerId int
+	// Currency is the currency of the total and of the products of every sale of the invoice.
+	Currency Currency
 }
 
 // Invoice is the struct that represents an invoice.
@@ -20,5 +28,10 @@ type Invoice struct {
 
 type InvoiceTotalByCustomerCondition struct {
 	Condition int
-	Total     float64
+	// Net is the total before taxes.
+	Net Money
+	// Total is the total including taxes.
+	Total Money
+	// Currency is the reporting currency the total is converted to.
+	Currency Currency
 }
@@ -1,11 +1,16 @@
 package internal
 
+import "context"
+
 // ServiceInvoice is the interface that wraps the basic methods that an invoice service should implement.
 type ServiceInvoice interface {
 	// FindAll returns all invoices
-	FindAll() (i []Invoice, err error)
-	GetInvoicesTotalByCustomerCondition() ([]InvoiceTotalByCustomerCondition, error)
+	FindAll(ctx context.Context) (i []Invoice, err error)
+	// GetInvoicesTotalByCustomerCondition returns the invoiced total grouped by customer condition, converted to currency
+	// at the rate effective at the date of each invoice. It fails with ErrExchangeRateNotFound if a rate is missing.
+	GetInvoicesTotalByCustomerCondition(ctx context.Context, currency Currency) ([]InvoiceTotalByCustomerCondition, error)
 	// Save saves an invoice
-	Save(i *Invoice) (err error)
-	UpdateInvoicesTotal() (err error)
+	Save(ctx context.Context, i *Invoice) (err error)
+	// UpdateInvoicesTotal recalculates the total of every invoice from the quantity and unit price of its sales.
+	UpdateInvoicesTotal(ctx context.Context) (err error)
 }
@@ -1,5 +1,7 @@
 package internal
 
+import "context"
+
 type LoaderDefault interface {
-	LoadAndSave() error
+	LoadAndSave(ctx context.Context) error
 }
@@ -2,8 +2,8 @@ package loader
 
 import (
 	"app/internal"
-	"encoding/json"
-	"os"
+	"app/platform/trace"
+	"context"
 )
 
 type CustomerLoader struct {
@@ -30,7 +30,7 @@ func JSONToCustomer(customerJSON CustomerJSON) internal.Customer {
 	attr := internal.CustomerAttributes{
 		LastName:  customerJSON.LastName,
 		FirstName: customerJSON.FirstName,
-		Condition: customerJSON.Condition,
+		Condition: internal.CustomerCondition(customerJSON.Condition),
 	}
 
 	return internal.Customer{
@@ -39,25 +39,26 @@ func JSONToCustomer(customerJSON CustomerJSON) internal.Customer {
 	}
 }
 
-func (c *CustomerLoader) LoadAndSave() error {
-	file, err := os.Open(c.CustomerJSONPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+func (c *CustomerLoader) LoadAndSave(ctx context.Context) (err error) {
+	ctx, sp := trace.Start(ctx, "loader.customers")
+	defer func() { sp.End(err) }()
 
 	var customers []CustomerJSON
-	err = json.NewDecoder(file).Decode(&customers)
+	err = readJSON(ctx, "customers", c.CustomerJSONPath, &customers)
 	if err != nil {
 		return err
 	}
 
 	var internalCustomer internal.Customer
+	pg := newProgress(ctx, "customers", c.CustomerJSONPath, len(customers))
+	defer func() { pg.Done(err) }()
+
 	for _, customer := range customers {
 		internalCustomer = JSONToCustomer(customer)
-		if err := c.cr.Save(&internalCustomer); err != nil {
+		if err := c.cr.Save(pg.ctx, &internalCustomer); err != nil {
 			return err
 		}
+		pg.Saved()
 	}
 
 	return nil
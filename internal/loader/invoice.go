@@ -2,8 +2,8 @@ package loader
 
 import (
 	"app/internal"
-	"encoding/json"
-	"os"
+	"app/platform/trace"
+	"context"
 )
 
 type InvoiceLoader struct {
@@ -20,10 +20,10 @@ func NewInvoiceLoader(InvoiceJSONPath string, ir internal.RepositoryInvoice) *In
 
 // {"id":1,"datetime":"2022-05-15","customer_id":19,"total":0.0},
 type InvoiceJSON struct {
-	ID         int     `json:"id"`
-	Datetime   string  `json:"datetime"`
-	CustomerID int     `json:"customer_id"`
-	Total      float64 `json:"total"`
+	ID         int            `json:"id"`
+	Datetime   string         `json:"datetime"`
+	CustomerID int            `json:"customer_id"`
+	Total      internal.Money `json:"total"`
 }
 
 func JSONToInvoice(invoiceJSON InvoiceJSON) internal.Invoice {
@@ -38,25 +38,26 @@ func JSONToInvoice(invoiceJSON InvoiceJSON) internal.Invoice {
 	}
 }
 
-func (c *InvoiceLoader) LoadAndSave() error {
-	file, err := os.Open(c.InvoiceJSONPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+func (c *InvoiceLoader) LoadAndSave(ctx context.Context) (err error) {
+	ctx, sp := trace.Start(ctx, "loader.invoices")
+	defer func() { sp.End(err) }()
 
 	var invoices []InvoiceJSON
-	err = json.NewDecoder(file).Decode(&invoices)
+	err = readJSON(ctx, "invoices", c.InvoiceJSONPath, &invoices)
 	if err != nil {
 		return err
 	}
 
 	var internalInvoice internal.Invoice
+	pg := newProgress(ctx, "invoices", c.InvoiceJSONPath, len(invoices))
+	defer func() { pg.Done(err) }()
+
 	for _, invoice := range invoices {
 		internalInvoice = JSONToInvoice(invoice)
-		if err := c.ir.Save(&internalInvoice); err != nil {
+		if err := c.ir.Save(pg.ctx, &internalInvoice); err != nil {
 			return err
 		}
+		pg.Saved()
 	}
 
 	return nil
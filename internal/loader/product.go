@@ -2,8 +2,8 @@ package loader
 
 import (
 	"app/internal"
-	"encoding/json"
-	"os"
+	"app/platform/trace"
+	"context"
 )
 
 type ProductLoader struct {
@@ -20,9 +20,9 @@ func NewProductLoader(ProductJSONPath string, pr internal.RepositoryProduct) *Pr
 
 // {"id":1,"description":"French Pastry - Mini Chocolate","price":97.01},
 type ProductJSON struct {
-	ID          int     `json:"id"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	ID          int            `json:"id"`
+	Description string         `json:"description"`
+	Price       internal.Money `json:"price"`
 }
 
 func JSONToProduct(productJSON ProductJSON) internal.Product {
@@ -36,25 +36,26 @@ func JSONToProduct(productJSON ProductJSON) internal.Product {
 	}
 }
 
-func (p *ProductLoader) LoadAndSave() error {
-	file, err := os.Open(p.ProductJSONPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+func (p *ProductLoader) LoadAndSave(ctx context.Context) (err error) {
+	ctx, sp := trace.Start(ctx, "loader.products")
+	defer func() { sp.End(err) }()
 
 	var products []ProductJSON
-	err = json.NewDecoder(file).Decode(&products)
+	err = readJSON(ctx, "products", p.ProductJSONPath, &products)
 	if err != nil {
 		return err
 	}
 
 	var internalProduct internal.Product
+	pg := newProgress(ctx, "products", p.ProductJSONPath, len(products))
+	defer func() { pg.Done(err) }()
+
 	for _, product := range products {
 		internalProduct = JSONToProduct(product)
-		if err := p.pr.Save(&internalProduct); err != nil {
+		if err := p.pr.Save(pg.ctx, &internalProduct); err != nil {
 			return err
 		}
+		pg.Saved()
 	}
 
 	return nil
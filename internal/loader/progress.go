@@ -0,0 +1,104 @@
+package loader
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"time"
+
+	"app/platform/logger"
+	"app/platform/metrics"
+	"app/platform/trace"
+)
+
+// progressEvery is the number of records between two progress logs.
+const progressEvery = 100
+
+var (
+	// loaderRecords is the number of records saved by the loaders, by entity.
+	loaderRecords = metrics.Default.Counter("loader_records_total",
+		"Number of records saved by the loaders.", "entity")
+	// loaderRuns is the number of loader runs, by entity and status.
+	loaderRuns = metrics.Default.Counter("loader_runs_total",
+		"Number of loader runs.", "entity", "status")
+	// loaderDuration is the duration of the loader runs, by entity.
+	loaderDuration = metrics.Default.Histogram("loader_duration_seconds",
+		"Duration of the loader runs in seconds.", []float64{.1, .5, 1, 5, 10, 30, 60, 300}, "entity")
+)
+
+// readJSON decodes the JSON file at path into v, traced as the read stage of the entity loader.
+func readJSON(ctx context.Context, entity, path string, v any) (err error) {
+	_, sp := trace.Start(ctx, "loader."+entity+".read", trace.String("path", path))
+	defer func() { sp.End(err) }()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(v)
+	return
+}
+
+// progress logs the progress of a loader with the logger carried by the context.
+// It is also the save stage of the loader: ctx carries its span, so saves are traced as its children.
+type progress struct {
+	ctx    context.Context
+	span   *trace.Span
+	entity string
+	total  int
+	saved  int
+	start  time.Time
+}
+
+// newProgress logs the start of the load of total records of entity from path.
+func newProgress(ctx context.Context, entity, path string, total int) *progress {
+	logger.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "loader started",
+		slog.String("entity", entity),
+		slog.String("path", path),
+		slog.Int("total", total),
+	)
+
+	ctx, sp := trace.Start(ctx, "loader."+entity+".save", trace.Int("total", total))
+	return &progress{ctx: ctx, span: sp, entity: entity, total: total, start: time.Now()}
+}
+
+// Saved records that a record was saved, logging every progressEvery records.
+func (p *progress) Saved() {
+	p.saved++
+	loaderRecords.With(p.entity).Inc()
+	if p.saved%progressEvery == 0 && p.saved != p.total {
+		logger.FromContext(p.ctx).LogAttrs(p.ctx, slog.LevelDebug, "loader progress",
+			slog.String("entity", p.entity),
+			slog.Int("saved", p.saved),
+			slog.Int("total", p.total),
+		)
+	}
+}
+
+// Done logs the end of the load, failed if err is not nil.
+func (p *progress) Done(err error) {
+	p.span.SetAttributes(trace.Int("saved", p.saved))
+	p.span.End(err)
+
+	elapsed := time.Since(p.start)
+	loaderDuration.With(p.entity).Observe(elapsed.Seconds())
+
+	attrs := []slog.Attr{
+		slog.String("entity", p.entity),
+		slog.Int("saved", p.saved),
+		slog.Int("total", p.total),
+		slog.Duration("duration", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		loaderRuns.With(p.entity, "error").Inc()
+		logger.FromContext(p.ctx).LogAttrs(p.ctx, slog.LevelError, "loader failed", attrs...)
+		return
+	}
+
+	loaderRuns.With(p.entity, "ok").Inc()
+	logger.FromContext(p.ctx).LogAttrs(p.ctx, slog.LevelInfo, "loader finished", attrs...)
+}
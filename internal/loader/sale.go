@@ -2,8 +2,8 @@ package loader
 
 import (
 	"app/internal"
-	"encoding/json"
-	"os"
+	"app/platform/trace"
+	"context"
 )
 
 type SaleLoader struct {
@@ -40,25 +40,26 @@ func JSONToSale(saleJSON SaleJSON) internal.Sale {
 
 }
 
-func (p *SaleLoader) LoadAndSave() error {
-	file, err := os.Open(p.SaleJSONPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+func (p *SaleLoader) LoadAndSave(ctx context.Context) (err error) {
+	ctx, sp := trace.Start(ctx, "loader.sales")
+	defer func() { sp.End(err) }()
 
 	var sales []SaleJSON
-	err = json.NewDecoder(file).Decode(&sales)
+	err = readJSON(ctx, "sales", p.SaleJSONPath, &sales)
 	if err != nil {
 		return err
 	}
 
 	var internalSale internal.Sale
+	pg := newProgress(ctx, "sales", p.SaleJSONPath, len(sales))
+	defer func() { pg.Done(err) }()
+
 	for _, sale := range sales {
 		internalSale = JSONToSale(sale)
-		if err := p.sr.Save(&internalSale); err != nil {
+		if err := p.sr.Save(pg.ctx, &internalSale); err != nil {
 			return err
 		}
+		pg.Saved()
 	}
 
 	return nil
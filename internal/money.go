@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// ErrMoneyInvalid is used when an amount of money cannot be parsed.
+var ErrMoneyInvalid = errors.New("invalid amount of money")
+
+// Money is an amount of money in cents, so it adds and multiplies exactly.
+// Amounts with more than two decimals are rounded to the cent with banker's rounding
+// (half to even) wherever they enter: parsing, json and database values.
+// It is stored in DECIMAL(12,2) columns and encoded in json as a number with two decimals, e.g. 10.50.
+type Money int64
+
+// ParseMoney parses a decimal amount, e.g. "10.5", "-3.125" or "1e2", rounding it to the cent.
+func ParseMoney(s string) (m Money, err error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		err = fmt.Errorf("%w: %q", ErrMoneyInvalid, s)
+		return
+	}
+	return moneyFromRat(r, s)
+}
+
+// MustParseMoney is like ParseMoney but panics if s cannot be parsed. It is meant for constants.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromFloat returns the amount closest to f, rounded to the cent.
+// It is meant for the sources that only provide floats, such as sqlite aggregates.
+func MoneyFromFloat(f float64) (m Money, err error) {
+	return ParseMoney(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// moneyFromRat rounds r to the cent, half to even.
+func moneyFromRat(r *big.Rat, s string) (m Money, err error) {
+	cents, ok := roundHalfEven(r, 100)
+	if !ok {
+		err = fmt.Errorf("%w: %q out of range", ErrMoneyInvalid, s)
+		return
+	}
+	return Money(cents), nil
+}
+
+// roundHalfEven returns r in units of 1/scale, rounded half to even, and whether it fits an int64.
+func roundHalfEven(r *big.Rat, scale int64) (units int64, ok bool) {
+	scaled := new(big.Rat).Mul(r, big.NewRat(scale, 1))
+	q, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+
+	// - compare twice the remainder with the denominator to tell below, at and above half a unit
+	half := new(big.Int).Abs(rem)
+	half.Lsh(half, 1)
+	if cmp := half.Cmp(scaled.Denom()); cmp > 0 || (cmp == 0 && q.Bit(0) == 1) {
+		q.Add(q, big.NewInt(int64(rem.Sign())))
+	}
+
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return q.Int64(), true
+}
+
+// Mul returns the amount multiplied by n, e.g. a price by a quantity.
+func (m Money) Mul(n int) Money {
+	return m * Money(n)
+}
+
+// String returns the amount with two decimals, e.g. "10.50".
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Float64 returns the amount as a float, for the consumers that need one, such as metrics.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// MarshalJSON encodes the amount as a number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes the amount from a number or a string holding a number.
+func (m *Money) UnmarshalJSON(data []byte) (err error) {
+	s := string(data)
+	if s == "null" {
+		return
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	*m, err = ParseMoney(s)
+	if err != nil {
+		// - a type error lets the decoder report the field
+		return &json.UnmarshalTypeError{Value: string(data), Type: reflect.TypeOf(*m)}
+	}
+	return
+}
+
+// Scan reads the amount from a database value: DECIMAL columns come as text, sqlite ones as numbers.
+// NULL reads as zero.
+func (m *Money) Scan(src any) (err error) {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v * 100)
+	case float64:
+		*m, err = MoneyFromFloat(v)
+	case []byte:
+		*m, err = ParseMoney(string(v))
+	case string:
+		*m, err = ParseMoney(v)
+	default:
+		err = fmt.Errorf("%w: cannot scan %T", ErrMoneyInvalid, src)
+	}
+	return
+}
+
+// Value writes the amount as a decimal string, exact in DECIMAL columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
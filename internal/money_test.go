@@ -0,0 +1,120 @@
+package internal_test
+
+import (
+	"app/internal"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ParseMoney function
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expectMoney internal.Money
+		expectError bool
+	}{
+		{name: "integer", input: "10", expectMoney: 1000},
+		{name: "two decimals", input: "10.05", expectMoney: 1005},
+		{name: "exponent", input: "1.5e2", expectMoney: 15000},
+		{name: "negative", input: "-0.5", expectMoney: -50},
+		{name: "half to even down", input: "2.345", expectMoney: 234},
+		{name: "half to even up", input: "2.355", expectMoney: 236},
+		{name: "above half", input: "2.3451", expectMoney: 235},
+		{name: "negative half to even", input: "-2.355", expectMoney: -236},
+		{name: "invalid", input: "ten", expectError: true},
+		{name: "out of range", input: "1e30", expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			m, err := internal.ParseMoney(testCase.input)
+
+			// assert
+			if testCase.expectError {
+				require.ErrorIs(t, err, internal.ErrMoneyInvalid)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectMoney, m)
+		})
+	}
+}
+
+// Tests for the encodings of Money
+func TestMoneyEncoding(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "10.50", internal.Money(1050).String())
+		require.Equal(t, "-0.05", internal.Money(-5).String())
+	})
+
+	t.Run("json number or string", func(t *testing.T) {
+		// arrange
+		var v struct {
+			Number internal.Money `json:"number"`
+			String internal.Money `json:"string"`
+		}
+
+		// act
+		err := json.Unmarshal([]byte(`{"number": 0.125, "string": "97.01"}`), &v)
+		out, errMarshal := json.Marshal(v)
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, errMarshal)
+		require.Equal(t, internal.Money(12), v.Number)
+		require.Equal(t, internal.Money(9701), v.String)
+		require.JSONEq(t, `{"number": 0.12, "string": 97.01}`, string(out))
+		require.Equal(t, `{"number":0.12,"string":97.01}`, string(out))
+	})
+
+	t.Run("json invalid", func(t *testing.T) {
+		// arrange
+		var v struct {
+			Price internal.Money `json:"price"`
+		}
+
+		// act
+		err := json.Unmarshal([]byte(`{"price": "cheap"}`), &v)
+
+		// assert
+		var typeErr *json.UnmarshalTypeError
+		require.ErrorAs(t, err, &typeErr)
+	})
+
+	t.Run("scan database values", func(t *testing.T) {
+		testCases := []struct {
+			name        string
+			src         any
+			expectMoney internal.Money
+		}{
+			{name: "decimal text", src: []byte("60.67"), expectMoney: 6067},
+			{name: "decimal string", src: "60.67", expectMoney: 6067},
+			{name: "integer", src: int64(60), expectMoney: 6000},
+			{name: "float sum", src: 0.1 + 0.2, expectMoney: 30},
+			{name: "null", src: nil, expectMoney: 0},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				// act
+				var m internal.Money
+				err := m.Scan(testCase.src)
+
+				// assert
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectMoney, m)
+			})
+		}
+	})
+
+	t.Run("value", func(t *testing.T) {
+		v, err := internal.Money(6067).Value()
+
+		require.NoError(t, err)
+		require.Equal(t, "60.67", v)
+	})
+}
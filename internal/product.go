@@ -1,11 +1,37 @@
 package internal
 
+import "errors"
+
+var (
+	// ErrProductNotFound is used when a product does not exist.
+	ErrProductNotFound = errors.New("product not found")
+	// ErrProductSkuExists is used when another product has the SKU of a product.
+	ErrProductSkuExists = errors.New("product sku exists")
+	// ErrProductArchived is used when an archived product is sold.
+	ErrProductArchived = errors.New("product archived")
+)
+
+// UnitDefault is the unit of measure of the products saved without one.
+const UnitDefault = "unit"
+
 // ProductAttributes is the struct that represents the attributes of a product.
 type ProductAttributes struct {
 	// Description is the description of the product.
 	Description string
 	// Price is the price of the product.
-	Price float64
+	Price Money
+	// Currency is the currency of the price.
+	Currency Currency
+	// TaxCategory is the tax category of the product, which the tax rate of its sales depends on.
+	TaxCategory string
+	// Sku is the stock keeping unit of the product, unique among the products. Empty if the product has none.
+	Sku string
+	// CategoryId is the category of the product, 0 if it has none.
+	CategoryId int
+	// Unit is the unit of measure the product is sold in, e.g. "unit" or "kg".
+	Unit string
+	// Archived reports whether the product is discontinued: it cannot be sold, but its sales stay in the history.
+	Archived bool
 }
 
 // Product is the struct that represents a product.
@@ -16,6 +42,22 @@ type Product struct {
 	ProductAttributes
 }
 
+// ProductPrice is the struct that represents a price of a product over a period of time.
+type ProductPrice struct {
+	// Id is the unique identifier of the product price.
+	Id int
+	// ProductId is the product id of the price.
+	ProductId int
+	// Price is the price of the product during the period.
+	Price Money
+	// Currency is the currency of the price.
+	Currency Currency
+	// ValidFrom is the datetime, YYYY-MM-DD HH:MM:SS in UTC, from which the price applies.
+	ValidFrom string
+	// ValidTo is the datetime at which the next price replaced it, empty for the current price.
+	ValidTo string
+}
+
 type TopProduct struct {
 	Id          int
 	Description string
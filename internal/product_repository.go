@@ -1,10 +1,24 @@
 package internal
 
+import "context"
+
 // RepositoryProduct is the interface that wraps the basic methods that a product repository must have.
 type RepositoryProduct interface {
 	// FindAll returns all products saved in the database.
-	FindAll() (p []Product, err error)
-	GetTopProducts() ([]TopProduct, error)
-	// Save saves a product into the database.
-	Save(p *Product) (err error)
+	FindAll(ctx context.Context) (p []Product, err error)
+	GetTopProducts(ctx context.Context) ([]TopProduct, error)
+	// FindPrices returns the price history of the product id, oldest first. It fails with ErrProductNotFound.
+	FindPrices(ctx context.Context, id int) (pp []ProductPrice, err error)
+	// Search returns the page of q of the products whose description matches its text, the best ranked first.
+	Search(ctx context.Context, q SearchQuery) (m ProductMatches, err error)
+	// Save saves a product into the database, tracking its stock with no units in WarehouseDefault unless ctx was
+	// derived with WithStockUntracked. It fails with ErrProductSkuExists if another product has its SKU,
+	// and with ErrCategoryNotFound if its category does not exist.
+	Save(ctx context.Context, p *Product) (err error)
+	// UpdatePrice changes the price of the product p.Id to p.Price, closing its current price in the history.
+	// It fills the other attributes of p and fails with ErrProductNotFound.
+	UpdatePrice(ctx context.Context, p *Product) (err error)
+	// UpdateArchived archives the product p.Id, or restores it, as p.Archived says.
+	// It fills the other attributes of p and fails with ErrProductNotFound.
+	UpdateArchived(ctx context.Context, p *Product) (err error)
 }
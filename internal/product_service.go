@@ -1,10 +1,23 @@
 package internal
 
+import "context"
+
 // ServiceProduct is the interface that wraps the basic Product methods.
 type ServiceProduct interface {
 	// FindAll returns all products.
-	FindAll() (p []Product, err error)
-	GetTopProducts() ([]TopProduct, error)
-	// Save saves a product.
-	Save(p *Product) (err error)
+	FindAll(ctx context.Context) (p []Product, err error)
+	GetTopProducts(ctx context.Context) ([]TopProduct, error)
+	// FindPrices returns the price history of the product id, oldest first. It fails with ErrProductNotFound.
+	FindPrices(ctx context.Context, id int) (pp []ProductPrice, err error)
+	// Search returns the page of q of the products whose description matches its text, the best ranked first.
+	Search(ctx context.Context, q SearchQuery) (m ProductMatches, err error)
+	// Save saves a product. It fails with ErrProductSkuExists if another product has its SKU,
+	// and with ErrCategoryNotFound if its category does not exist.
+	Save(ctx context.Context, p *Product) (err error)
+	// UpdatePrice changes the price of the product p.Id to p.Price, closing its current price in the history.
+	// It fills the other attributes of p and fails with ErrProductNotFound.
+	UpdatePrice(ctx context.Context, p *Product) (err error)
+	// UpdateArchived archives the product p.Id, or restores it, as p.Archived says.
+	// It fills the other attributes of p and fails with ErrProductNotFound.
+	UpdateArchived(ctx context.Context, p *Product) (err error)
 }
@@ -0,0 +1,130 @@
+package internal
+
+// Promotion kinds.
+const (
+	// PromotionPercentage takes a percentage off the line.
+	PromotionPercentage = "percentage"
+	// PromotionBuyXGetY gives Get units free for every Buy units paid.
+	PromotionBuyXGetY = "buy_x_get_y"
+)
+
+// PromotionAttributes is the struct that represents the attributes of a promotion.
+type PromotionAttributes struct {
+	// Name is the name of the promotion.
+	Name string
+	// Kind is the kind of the promotion, PromotionPercentage or PromotionBuyXGetY.
+	Kind string
+	// ProductId is the product the promotion applies to, nil for every product.
+	ProductId *int
+	// Condition is the condition of the customers the promotion applies to, nil for every condition.
+	Condition *int
+	// Percent is the fraction taken off a PromotionPercentage line, e.g. 0.10 for 10%.
+	Percent Rate
+	// Buy is the number of units paid for every Get free units of a PromotionBuyXGetY promotion.
+	Buy int
+	// Get is the number of units free for every Buy paid units of a PromotionBuyXGetY promotion.
+	Get int
+	// ValidFrom is the first date the promotion applies on, YYYY-MM-DD.
+	ValidFrom string
+	// ValidTo is the last date the promotion applies on, YYYY-MM-DD, empty if it has no end.
+	ValidTo string
+	// Stackable reports whether the promotion combines with the other stackable promotions of a line.
+	// A promotion that is not stackable applies alone.
+	Stackable bool
+}
+
+// Promotion is the struct that represents a promotion.
+type Promotion struct {
+	// Id is the unique identifier of the promotion.
+	Id int
+	// PromotionAttributes is the attributes of the promotion.
+	PromotionAttributes
+}
+
+// Matches reports whether the promotion applies to product sold to a customer of condition at datetime,
+// a date or a datetime starting with its date.
+func (p PromotionAttributes) Matches(product, condition int, datetime string) bool {
+	date := datetime
+	if len(date) > len("2006-01-02") {
+		date = date[:len("2006-01-02")]
+	}
+	return (p.ProductId == nil || *p.ProductId == product) &&
+		(p.Condition == nil || *p.Condition == condition) &&
+		p.ValidFrom <= date && (p.ValidTo == "" || date <= p.ValidTo)
+}
+
+// Discount returns the discount of the promotion on a line of quantity units at unitPrice.
+func (p PromotionAttributes) Discount(quantity int, unitPrice Money) Money {
+	switch p.Kind {
+	case PromotionPercentage:
+		var c Converted
+		c.Add(unitPrice.Mul(quantity), p.Percent)
+		return c.Money()
+	case PromotionBuyXGetY:
+		if p.Buy <= 0 || p.Get <= 0 {
+			return 0
+		}
+		return unitPrice.Mul(quantity / (p.Buy + p.Get) * p.Get)
+	}
+	return 0
+}
+
+// SaleDiscount is a discount of a promotion applied to a sale.
+type SaleDiscount struct {
+	// PromotionId is the id of the applied promotion.
+	PromotionId int
+	// Amount is the amount taken off the sale.
+	Amount Money
+}
+
+// Promotions are the configured promotions, from which the discounts of every sale are chosen.
+type Promotions []Promotion
+
+// Discounts returns the discounts of a line of quantity units of product at unitPrice, sold to a customer of
+// condition at datetime, and their total, never more than the line. The stackable matching promotions add up;
+// the best matching promotion that is not stackable applies alone when it takes off more than all of them.
+// Ties go to the stackable promotions, then to the first promotion.
+func (p Promotions) Discounts(product, condition, quantity int, unitPrice Money, datetime string) (d []SaleDiscount, total Money) {
+	line := unitPrice.Mul(quantity)
+
+	var (
+		stacked  []SaleDiscount
+		stackSum Money
+		best     SaleDiscount
+	)
+	for _, v := range p {
+		if !v.Matches(product, condition, datetime) {
+			continue
+		}
+		amount := v.Discount(quantity, unitPrice)
+		if amount <= 0 {
+			continue
+		}
+
+		if v.Stackable {
+			stacked = append(stacked, SaleDiscount{PromotionId: v.Id, Amount: amount})
+			stackSum += amount
+			continue
+		}
+		if amount > best.Amount {
+			best = SaleDiscount{PromotionId: v.Id, Amount: amount}
+		}
+	}
+
+	if best.Amount > stackSum {
+		stacked = []SaleDiscount{best}
+	}
+	// - the line cannot go below 0: the last discounts are cut
+	for ix := range stacked {
+		if stacked[ix].Amount > line-total {
+			stacked[ix].Amount = line - total
+		}
+		total += stacked[ix].Amount
+	}
+	for _, v := range stacked {
+		if v.Amount > 0 {
+			d = append(d, v)
+		}
+	}
+	return
+}
@@ -0,0 +1,12 @@
+package internal
+
+import "context"
+
+// RepositoryPromotion is the interface that wraps the basic methods that a promotion repository should implement.
+type RepositoryPromotion interface {
+	// FindAll returns all promotions saved in the database.
+	FindAll(ctx context.Context) (p []Promotion, err error)
+	// Save saves a promotion into the database. It fails with ErrProductNotFound
+	// if the product of the promotion does not exist.
+	Save(ctx context.Context, p *Promotion) (err error)
+}
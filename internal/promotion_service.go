@@ -0,0 +1,11 @@
+package internal
+
+import "context"
+
+// ServicePromotion is the interface that wraps the basic methods that a promotion service should implement.
+type ServicePromotion interface {
+	// FindAll returns all promotions.
+	FindAll(ctx context.Context) (p []Promotion, err error)
+	// Save saves a promotion.
+	Save(ctx context.Context, p *Promotion) (err error)
+}
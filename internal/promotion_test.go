@@ -0,0 +1,114 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Promotions.Discounts method
+func TestPromotionsDiscounts(t *testing.T) {
+	product, preferred := 1, 1
+	promotion := func(id int, kind string, stackable bool, attrs internal.PromotionAttributes) internal.Promotion {
+		attrs.Kind, attrs.Stackable = kind, stackable
+		if attrs.ValidFrom == "" {
+			attrs.ValidFrom = "2024-01-01"
+		}
+		return internal.Promotion{Id: id, PromotionAttributes: attrs}
+	}
+	percent := func(r string) internal.PromotionAttributes {
+		return internal.PromotionAttributes{Percent: internal.MustParseRate(r)}
+	}
+
+	testCases := []struct {
+		name           string
+		promotions     internal.Promotions
+		condition      int
+		quantity       int
+		datetime       string
+		expectDiscount []internal.SaleDiscount
+		expectTotal    internal.Money
+	}{
+		{
+			name:        "no promotion",
+			quantity:    3,
+			datetime:    "2024-02-01 10:00:00",
+			expectTotal: 0,
+		}, {
+			name:           "percentage rounded to the cent",
+			promotions:     internal.Promotions{promotion(1, internal.PromotionPercentage, false, percent("0.125"))},
+			quantity:       1,
+			datetime:       "2024-02-01 10:00:00",
+			expectDiscount: []internal.SaleDiscount{{PromotionId: 1, Amount: internal.MustParseMoney("1.24")}},
+			expectTotal:    internal.MustParseMoney("1.24"),
+		}, {
+			name:           "buy 2 get 1",
+			promotions:     internal.Promotions{promotion(1, internal.PromotionBuyXGetY, false, internal.PromotionAttributes{ProductId: &product, Buy: 2, Get: 1})},
+			quantity:       7,
+			datetime:       "2024-02-01 10:00:00",
+			expectDiscount: []internal.SaleDiscount{{PromotionId: 1, Amount: internal.MustParseMoney("19.90")}},
+			expectTotal:    internal.MustParseMoney("19.90"),
+		}, {
+			name: "out of the validity window or for another condition",
+			promotions: internal.Promotions{
+				promotion(1, internal.PromotionPercentage, false, internal.PromotionAttributes{Percent: internal.MustParseRate("0.1"), ValidFrom: "2024-03-01"}),
+				promotion(2, internal.PromotionPercentage, false, internal.PromotionAttributes{Percent: internal.MustParseRate("0.1"), ValidTo: "2024-01-31"}),
+				promotion(3, internal.PromotionPercentage, false, internal.PromotionAttributes{Percent: internal.MustParseRate("0.1"), Condition: &preferred}),
+			},
+			condition:   0,
+			quantity:    1,
+			datetime:    "2024-02-01 10:00:00",
+			expectTotal: 0,
+		}, {
+			name: "stackable promotions add up, rounded half to even",
+			promotions: internal.Promotions{
+				promotion(1, internal.PromotionPercentage, true, percent("0.10")),
+				promotion(2, internal.PromotionPercentage, true, internal.PromotionAttributes{Percent: internal.MustParseRate("0.05"), Condition: &preferred}),
+				promotion(3, internal.PromotionPercentage, false, percent("0.12")),
+			},
+			condition: 1,
+			quantity:  10,
+			datetime:  "2024-02-01 10:00:00",
+			expectDiscount: []internal.SaleDiscount{
+				{PromotionId: 1, Amount: internal.MustParseMoney("9.95")},
+				{PromotionId: 2, Amount: internal.MustParseMoney("4.98")},
+			},
+			expectTotal: internal.MustParseMoney("14.93"),
+		}, {
+			name: "a better exclusive promotion applies alone",
+			promotions: internal.Promotions{
+				promotion(1, internal.PromotionPercentage, true, percent("0.10")),
+				promotion(2, internal.PromotionBuyXGetY, false, internal.PromotionAttributes{Buy: 1, Get: 1}),
+			},
+			quantity:       2,
+			datetime:       "2024-02-01 10:00:00",
+			expectDiscount: []internal.SaleDiscount{{PromotionId: 2, Amount: internal.MustParseMoney("9.95")}},
+			expectTotal:    internal.MustParseMoney("9.95"),
+		}, {
+			name: "never more than the line",
+			promotions: internal.Promotions{
+				promotion(1, internal.PromotionPercentage, true, percent("0.80")),
+				promotion(2, internal.PromotionPercentage, true, percent("0.50")),
+			},
+			quantity: 1,
+			datetime: "2024-02-01 10:00:00",
+			expectDiscount: []internal.SaleDiscount{
+				{PromotionId: 1, Amount: internal.MustParseMoney("7.96")},
+				{PromotionId: 2, Amount: internal.MustParseMoney("1.99")},
+			},
+			expectTotal: internal.MustParseMoney("9.95"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			d, total := testCase.promotions.Discounts(product, testCase.condition, testCase.quantity, internal.MustParseMoney("9.95"), testCase.datetime)
+
+			// assert
+			require.Equal(t, testCase.expectDiscount, d)
+			require.Equal(t, testCase.expectTotal, total)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	// ErrStockAlertExists is used when a product was already alerted on the date of an alert.
+	ErrStockAlertExists = errors.New("stock alert exists")
+)
+
+// ReorderPolicy is the struct that represents the policy of the reorder suggestions.
+type ReorderPolicy struct {
+	// WindowDays is the number of trailing days the sales velocity is computed over.
+	WindowDays int
+	// MinDays is the number of days of sales under which the stock of a product runs low.
+	MinDays int
+	// TargetDays is the number of days of sales the stock covers after the suggested reorder.
+	TargetDays int
+}
+
+// ReorderPolicyDefault is the policy of the reorder suggestions when none is configured.
+var ReorderPolicyDefault = ReorderPolicy{WindowDays: 30, MinDays: 7, TargetDays: 30}
+
+// Since returns the datetime from which the sales count at now, in the layout of the invoice datetimes.
+func (p ReorderPolicy) Since(now time.Time) string {
+	return now.UTC().AddDate(0, 0, -p.WindowDays).Format(time.DateTime)
+}
+
+// StockSales is the struct that represents the stock of a tracked product and the units sold over a window.
+type StockSales struct {
+	// ProductId is the product id.
+	ProductId int
+	// Description is the description of the product.
+	Description string
+	// Stock is the number of units held in every warehouse.
+	Stock int
+	// Sold is the number of units sold on the invoices of the window.
+	Sold int
+}
+
+// ReorderSuggestion is the struct that represents the reorder suggestion of a product whose stock runs low.
+type ReorderSuggestion struct {
+	// StockSales is the stock and the sales the suggestion is computed from.
+	StockSales
+	// DailySales is the sales velocity, the units sold per day over the window, rounded to 2 decimals.
+	DailySales float64
+	// DaysOfCover is the number of days of sales the stock covers, rounded to 2 decimals.
+	DaysOfCover float64
+	// Quantity is the suggested number of units to reorder.
+	Quantity int
+}
+
+// Suggest returns the reorder suggestion of s, and whether its stock covers fewer than MinDays of sales.
+// A product without sales over the window never runs low. The comparisons are made on integers, so they are exact.
+func (p ReorderPolicy) Suggest(s StockSales) (r ReorderSuggestion, low bool) {
+	r.StockSales = s
+	if p.WindowDays <= 0 || s.Sold <= 0 {
+		return
+	}
+
+	r.DailySales = round2(float64(s.Sold) / float64(p.WindowDays))
+	r.DaysOfCover = round2(float64(s.Stock) * float64(p.WindowDays) / float64(s.Sold))
+	// - stock / (sold / window) < min days
+	if s.Stock*p.WindowDays >= p.MinDays*s.Sold {
+		return
+	}
+
+	// - the units sold over the target days, rounded up, less the stock
+	r.Quantity = (s.Sold*p.TargetDays+p.WindowDays-1)/p.WindowDays - s.Stock
+	if r.Quantity < 1 {
+		r.Quantity = 1
+	}
+	return r, true
+}
+
+// Suggestions returns the reorder suggestions of the products of s whose stock runs low, an empty list if none.
+func (p ReorderPolicy) Suggestions(s []StockSales) (r []ReorderSuggestion) {
+	r = []ReorderSuggestion{}
+	for _, v := range s {
+		if sg, low := p.Suggest(v); low {
+			r = append(r, sg)
+		}
+	}
+	return
+}
+
+// round2 rounds f to 2 decimals.
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// StockAlert is the struct that represents an alert of a product whose stock runs low.
+type StockAlert struct {
+	// Id is the unique identifier of the stock alert.
+	Id int
+	// Date is the date of the alert, YYYY-MM-DD. A product is alerted once a day at most.
+	Date string
+	// ReorderSuggestion is the suggestion the alert was raised with.
+	ReorderSuggestion
+}
+
+// WebhookEventStockLow is the webhook event of a stock alert.
+const WebhookEventStockLow = "stock.low"
+
+// WebhookDelivery is the struct that represents an event queued for the outbound webhook.
+type WebhookDelivery struct {
+	// Id is the unique identifier of the delivery.
+	Id int
+	// Event is the event, e.g. WebhookEventStockLow.
+	Event string
+	// Payload is the JSON body posted to the webhook.
+	Payload []byte
+	// Attempts is the number of failed attempts to deliver the event.
+	Attempts int
+	// Delivered reports whether the webhook accepted the event.
+	Delivered bool
+	// LastError is the error of the last failed attempt, empty if none.
+	LastError string
+}
@@ -0,0 +1,14 @@
+package internal
+
+import "context"
+
+// ServiceReorder is the interface that wraps the basic methods that a reorder service should implement.
+type ServiceReorder interface {
+	// Suggestions returns the reorder suggestions of the products whose stock runs low.
+	Suggestions(ctx context.Context) (r []ReorderSuggestion, err error)
+	// Alerts returns all stock alerts, oldest first.
+	Alerts(ctx context.Context) (a []StockAlert, err error)
+	// RaiseAlerts saves an alert for every product whose stock runs low and that was not alerted today,
+	// returning the new alerts.
+	RaiseAlerts(ctx context.Context) (a []StockAlert, err error)
+}
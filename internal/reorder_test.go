@@ -0,0 +1,60 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ReorderPolicy.Suggest method
+func TestReorderPolicySuggest(t *testing.T) {
+	policy := internal.ReorderPolicy{WindowDays: 30, MinDays: 7, TargetDays: 30}
+	testCases := []struct {
+		name           string
+		input          internal.StockSales
+		expectLow      bool
+		expectDaily    float64
+		expectCover    float64
+		expectQuantity int
+	}{
+		{name: "no sales", input: internal.StockSales{Stock: 0, Sold: 0}},
+		{name: "enough stock", input: internal.StockSales{Stock: 10, Sold: 30}, expectDaily: 1, expectCover: 10},
+		{name: "exactly min days", input: internal.StockSales{Stock: 7, Sold: 30}, expectDaily: 1, expectCover: 7},
+		{name: "low stock", input: internal.StockSales{Stock: 6, Sold: 30}, expectLow: true, expectDaily: 1, expectCover: 6, expectQuantity: 24},
+		{name: "out of stock", input: internal.StockSales{Stock: 0, Sold: 45}, expectLow: true, expectDaily: 1.5, expectCover: 0, expectQuantity: 45},
+		{name: "quantity rounded up", input: internal.StockSales{Stock: 0, Sold: 1}, expectLow: true, expectDaily: 0.03, expectCover: 0, expectQuantity: 1},
+		{name: "fractional cover", input: internal.StockSales{Stock: 2, Sold: 9}, expectLow: true, expectDaily: 0.3, expectCover: 6.67, expectQuantity: 7},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			r, low := policy.Suggest(testCase.input)
+
+			// assert
+			require.Equal(t, testCase.expectLow, low)
+			require.Equal(t, testCase.input, r.StockSales)
+			require.Equal(t, testCase.expectDaily, r.DailySales)
+			require.Equal(t, testCase.expectCover, r.DaysOfCover)
+			require.Equal(t, testCase.expectQuantity, r.Quantity)
+		})
+	}
+}
+
+// Tests for ReorderPolicy.Suggestions and ReorderPolicy.Since methods
+func TestReorderPolicySuggestions(t *testing.T) {
+	policy := internal.ReorderPolicyDefault
+
+	r := policy.Suggestions([]internal.StockSales{
+		{ProductId: 1, Stock: 100, Sold: 30},
+		{ProductId: 2, Stock: 1, Sold: 30},
+	})
+	require.Len(t, r, 1)
+	require.Equal(t, 2, r[0].ProductId)
+	require.Equal(t, []internal.ReorderSuggestion{}, policy.Suggestions(nil))
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, "2024-03-01 12:00:00", policy.Since(now))
+}
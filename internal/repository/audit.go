@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	// AuditEntityCustomers is the audited entity of the customers table.
+	AuditEntityCustomers = "customers"
+	// AuditEntityProducts is the audited entity of the products table.
+	AuditEntityProducts = "products"
+	// AuditEntityCategories is the audited entity of the categories table.
+	AuditEntityCategories = "categories"
+	// AuditEntityInvoices is the audited entity of the invoices table.
+	AuditEntityInvoices = "invoices"
+	// AuditEntitySales is the audited entity of the sales table.
+	AuditEntitySales = "sales"
+	// AuditEntityExchangeRates is the audited entity of the exchange_rates table.
+	AuditEntityExchangeRates = "exchange_rates"
+	// AuditEntityTaxRates is the audited entity of the tax_rates table.
+	AuditEntityTaxRates = "tax_rates"
+	// AuditEntityPromotions is the audited entity of the promotions table.
+	AuditEntityPromotions = "promotions"
+	// AuditEntityStockMovements is the audited entity of the stock_movements table.
+	AuditEntityStockMovements = "stock_movements"
+	// AuditEntityStockAlerts is the audited entity of the stock_alerts table.
+	AuditEntityStockAlerts = "stock_alerts"
+
+	// auditTimestampLayout is the layout of the audit timestamps in the databases.
+	// It is fixed width, so timestamps stored as text sort in time order.
+	auditTimestampLayout = "2006-01-02 15:04:05.000000"
+)
+
+// execer is implemented by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// querier is implemented by *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// rowQuerier is implemented by *sql.DB and *sql.Tx.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// newAuditRecord returns the audit record of the change of the record id of entity from before to after,
+// made by the actor carried by ctx. A nil before is a creation, a nil after a deletion.
+func newAuditRecord(ctx context.Context, entity string, id int, before, after any) (a internal.AuditRecord, err error) {
+	a.Actor = internal.ActorFrom(ctx)
+	a.Timestamp = time.Now().UTC()
+	a.Entity = entity
+	a.EntityId = id
+
+	switch {
+	case before == nil:
+		a.Action = internal.AuditActionCreate
+	case after == nil:
+		a.Action = internal.AuditActionDelete
+	default:
+		a.Action = internal.AuditActionUpdate
+	}
+
+	if before != nil {
+		a.Before, err = json.Marshal(before)
+		if err != nil {
+			return
+		}
+	}
+	if after != nil {
+		a.After, err = json.Marshal(after)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// writeAudit inserts the audit record of a change with query, within the transaction of the change.
+// The placeholders of query are actor, timestamp, entity, entity_id, action, before and after.
+func writeAudit(ctx context.Context, tx execer, query string, entity string, id int, before, after any) (err error) {
+	a, err := newAuditRecord(ctx, entity, id, before, after)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, query,
+		a.Actor, a.Timestamp.Format(auditTimestampLayout), a.Entity, a.EntityId, a.Action, nullJSON(a.Before), nullJSON(a.After),
+	)
+	return
+}
+
+// nullJSON returns the JSON as a nullable string.
+func nullJSON(b json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(b), Valid: b != nil}
+}
+
+// inTx runs fn in a transaction of db, committed if fn succeeds and rolled back otherwise.
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// queryInvoices returns the invoices selected by query, by id.
+// The columns of query are id, datetime, discount, subtotal, tax, total, customer_id and currency.
+func queryInvoices(ctx context.Context, q querier, query string) (i map[int]internal.Invoice, err error) {
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	i = make(map[int]internal.Invoice)
+	for rows.Next() {
+		var iv internal.Invoice
+		err = rows.Scan(&iv.Id, &iv.Datetime, &iv.Discount, &iv.Subtotal, &iv.Tax, &iv.Total, &iv.CustomerId, &iv.Currency)
+		if err != nil {
+			return
+		}
+		i[iv.Id] = iv
+	}
+	err = rows.Err()
+	return
+}
+
+// auditQuery builds the query of the audit records matching f, oldest first.
+// quote quotes an identifier and placeholder returns the n-th placeholder, starting at 1.
+func auditQuery(f internal.AuditFilter, selectColumns string, quote func(string) string, placeholder func(n int) string) (query string, args []any) {
+	var conditions []string
+	add := func(column, op string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, quote(column)+" "+op+" "+placeholder(len(args)))
+	}
+
+	if f.Entity != "" {
+		add("entity", "=", f.Entity)
+	}
+	if f.EntityId != 0 {
+		add("entity_id", "=", f.EntityId)
+	}
+	if !f.From.IsZero() {
+		add("timestamp", ">=", f.From.UTC().Format(auditTimestampLayout))
+	}
+	if !f.To.IsZero() {
+		add("timestamp", "<", f.To.UTC().Format(auditTimestampLayout))
+	}
+
+	query = "SELECT " + selectColumns + " FROM audit_records"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY " + quote("id")
+	return
+}
+
+// queryAudit returns the audit records selected by query.
+// The columns of query are id, actor, timestamp, entity, entity_id, action, before and after.
+func queryAudit(ctx context.Context, q querier, query string, args ...any) (a []internal.AuditRecord, err error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	a = []internal.AuditRecord{}
+	for rows.Next() {
+		var (
+			ar            internal.AuditRecord
+			timestamp     string
+			before, after sql.NullString
+		)
+		err = rows.Scan(&ar.Id, &ar.Actor, &timestamp, &ar.Entity, &ar.EntityId, &ar.Action, &before, &after)
+		if err != nil {
+			return
+		}
+
+		ar.Timestamp, err = time.Parse(auditTimestampLayout, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("audit record %d: %w", ar.Id, err)
+		}
+		if before.Valid {
+			ar.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			ar.After = json.RawMessage(after.String)
+		}
+		a = append(a, ar)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewAuditMemory creates new memory repository for audit record entity.
+func NewAuditMemory(db *MemoryDB) *AuditMemory {
+	return &AuditMemory{db}
+}
+
+// AuditMemory is the memory repository implementation for audit record entity.
+type AuditMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns the audit records matching the filter, oldest first.
+func (r *AuditMemory) FindAll(ctx context.Context, f internal.AuditFilter) (a []internal.AuditRecord, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	a = []internal.AuditRecord{}
+	for _, ar := range r.db.auditRecords {
+		switch {
+		case f.Entity != "" && ar.Entity != f.Entity,
+			f.EntityId != 0 && ar.EntityId != f.EntityId,
+			!f.From.IsZero() && ar.Timestamp.Before(f.From),
+			!f.To.IsZero() && !ar.Timestamp.Before(f.To):
+			continue
+		}
+		a = append(a, ar)
+	}
+
+	return
+}
+
+// audit appends the audit record of a change to the audit table. The caller must hold the write lock.
+func (db *MemoryDB) audit(ctx context.Context, entity string, id int, before, after any) (err error) {
+	a, err := newAuditRecord(ctx, entity, id, before, after)
+	if err != nil {
+		return
+	}
+
+	db.lastAuditId++
+	a.Id = db.lastAuditId
+	db.auditRecords = append(db.auditRecords, a)
+	return
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	InsertAuditRecordQuery = "INSERT INTO audit_records (`actor`, `timestamp`, `entity`, `entity_id`, `action`, `before`, `after`) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	AuditRecordColumns     = "`id`, `actor`, DATE_FORMAT(`timestamp`, '%Y-%m-%d %H:%i:%s.%f'), `entity`, `entity_id`, `action`, `before`, `after`"
+)
+
+// NewAuditMySQL creates new mysql repository for audit record entity.
+func NewAuditMySQL(db *sql.DB) *AuditMySQL {
+	return &AuditMySQL{db}
+}
+
+// AuditMySQL is the MySQL repository implementation for audit record entity.
+type AuditMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns the audit records matching the filter, oldest first.
+func (r *AuditMySQL) FindAll(ctx context.Context, f internal.AuditFilter) (a []internal.AuditRecord, err error) {
+	defer observe(ctx, "audit_records.FindAll", time.Now(), &err)
+
+	query, args := auditQuery(f, AuditRecordColumns,
+		func(s string) string { return "`" + s + "`" },
+		func(n int) string { return "?" },
+	)
+	a, err = queryAudit(ctx, r.db, query, args...)
+	return
+}
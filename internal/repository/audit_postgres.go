@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	InsertAuditRecordPostgresQuery = `INSERT INTO audit_records ("actor", "timestamp", "entity", "entity_id", "action", "before", "after") VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	AuditRecordColumnsPostgres     = `"id", "actor", to_char("timestamp", 'YYYY-MM-DD HH24:MI:SS.US'), "entity", "entity_id", "action", "before", "after"`
+)
+
+// NewAuditPostgres creates new postgres repository for audit record entity.
+func NewAuditPostgres(db *sql.DB) *AuditPostgres {
+	return &AuditPostgres{db}
+}
+
+// AuditPostgres is the Postgres repository implementation for audit record entity.
+type AuditPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns the audit records matching the filter, oldest first.
+func (r *AuditPostgres) FindAll(ctx context.Context, f internal.AuditFilter) (a []internal.AuditRecord, err error) {
+	defer observe(ctx, "audit_records.FindAll", time.Now(), &err)
+
+	query, args := auditQuery(f, AuditRecordColumnsPostgres,
+		func(s string) string { return `"` + s + `"` },
+		func(n int) string { return "$" + strconv.Itoa(n) },
+	)
+	a, err = queryAudit(ctx, r.db, query, args...)
+	return
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	InsertAuditRecordSQLiteQuery = `INSERT INTO audit_records ("actor", "timestamp", "entity", "entity_id", "action", "before", "after") VALUES (?, ?, ?, ?, ?, ?, ?)`
+	AuditRecordColumnsSQLite     = `"id", "actor", "timestamp", "entity", "entity_id", "action", "before", "after"`
+)
+
+// NewAuditSQLite creates new sqlite repository for audit record entity.
+func NewAuditSQLite(db *sql.DB) *AuditSQLite {
+	return &AuditSQLite{db}
+}
+
+// AuditSQLite is the SQLite repository implementation for audit record entity.
+type AuditSQLite struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns the audit records matching the filter, oldest first.
+func (r *AuditSQLite) FindAll(ctx context.Context, f internal.AuditFilter) (a []internal.AuditRecord, err error) {
+	defer observe(ctx, "audit_records.FindAll", time.Now(), &err)
+
+	query, args := auditQuery(f, AuditRecordColumnsSQLite,
+		func(s string) string { return `"` + s + `"` },
+		func(n int) string { return "?" },
+	)
+	a, err = queryAudit(ctx, r.db, query, args...)
+	return
+}
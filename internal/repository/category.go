@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"app/internal"
+)
+
+// catalogQueries are the queries of a sql database on the categories and the SKUs of the products.
+// The arguments of each query are listed in the order of its placeholders.
+type catalogQueries struct {
+	// categoryExists counts the categories of an id: id.
+	categoryExists string
+	// skuExists counts the products of a SKU: sku.
+	skuExists string
+}
+
+// errCategoryNotFound returns the error of the category id that does not exist.
+func errCategoryNotFound(id int) error {
+	return fmt.Errorf("%w: %d", internal.ErrCategoryNotFound, id)
+}
+
+// errCategoryExists returns the error of a category whose parent already has a category of its name.
+func errCategoryExists(c internal.Category) error {
+	return fmt.Errorf("%w: %s under %d", internal.ErrCategoryExists, c.Name, c.ParentId)
+}
+
+// errProductSkuExists returns the error of a product whose SKU another product has.
+func errProductSkuExists(sku string) error {
+	return fmt.Errorf("%w: %s", internal.ErrProductSkuExists, sku)
+}
+
+// errProductArchived returns the error of a sale of the archived product id.
+func errProductArchived(id int) error {
+	return fmt.Errorf("%w: %d", internal.ErrProductArchived, id)
+}
+
+// productWriteError returns err, the error of a write of p, as the conflict of its SKU when it violates
+// the unique key of the SKUs: checkProductCatalog does not see the products of the concurrent writes.
+func productWriteError(p internal.Product, err error) error {
+	if uniqueViolation(err) {
+		return errProductSkuExists(p.Sku)
+	}
+	return err
+}
+
+// categoryWriteError returns err, the error of a write of c, as the conflict of its name when it violates
+// the unique key of the names under a parent: the check of the name does not see the categories of the concurrent writes.
+func categoryWriteError(c internal.Category, err error) error {
+	if uniqueViolation(err) {
+		return errCategoryExists(c)
+	}
+	return err
+}
+
+// productSku returns the SKU of p as stored, NULL if it has none, so the products without one do not collide.
+func productSku(p internal.Product) sql.NullString {
+	return sql.NullString{String: p.Sku, Valid: p.Sku != ""}
+}
+
+// productCategoryId returns the category of p as stored, NULL if it has none.
+func productCategoryId(p internal.Product) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(p.CategoryId), Valid: p.CategoryId != 0}
+}
+
+// categoryParentId returns the parent of c as stored, NULL for a root category.
+func categoryParentId(c internal.Category) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(c.ParentId), Valid: c.ParentId != 0}
+}
+
+// checkCategory checks that the category id exists, unless it is 0.
+func checkCategory(ctx context.Context, q rowQuerier, query string, id int) (err error) {
+	if id == 0 {
+		return
+	}
+	var n int
+	err = q.QueryRowContext(ctx, query, id).Scan(&n)
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		return errCategoryNotFound(id)
+	}
+	return
+}
+
+// checkProductCatalog checks that the SKU of p is free and that its category exists.
+// The check of the SKU is a fast path: the writes of concurrent transactions are caught by productWriteError.
+func checkProductCatalog(ctx context.Context, q rowQuerier, cq catalogQueries, p internal.Product) (err error) {
+	err = checkCategory(ctx, q, cq.categoryExists, p.CategoryId)
+	if err != nil {
+		return
+	}
+	if p.Sku == "" {
+		return
+	}
+	var n int
+	err = q.QueryRowContext(ctx, cq.skuExists, p.Sku).Scan(&n)
+	if err != nil {
+		return
+	}
+	if n > 0 {
+		return errProductSkuExists(p.Sku)
+	}
+	return
+}
+
+// queryCategories returns the categories selected by query: id, name and parent_id, by id.
+func queryCategories(ctx context.Context, db querier, query string) (c []internal.Category, err error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	c = []internal.Category{}
+	for rows.Next() {
+		var (
+			ct       internal.Category
+			parentId sql.NullInt64
+		)
+		err = rows.Scan(&ct.Id, &ct.Name, &parentId)
+		if err != nil {
+			return
+		}
+		ct.ParentId = int(parentId.Int64)
+		c = append(c, ct)
+	}
+	err = rows.Err()
+	return
+}
+
+// queryCategoryRevenue returns the revenue selected by query with args: category_id, units and revenue.
+func queryCategoryRevenue(ctx context.Context, db querier, query string, args ...any) (r []internal.CategoryRevenue, err error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	r = []internal.CategoryRevenue{}
+	for rows.Next() {
+		var cr internal.CategoryRevenue
+		err = rows.Scan(&cr.CategoryId, &cr.Units, &cr.Revenue)
+		if err != nil {
+			return
+		}
+		r = append(r, cr)
+	}
+	err = rows.Err()
+	return
+}
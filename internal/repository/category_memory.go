@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewCategoriesMemory creates new memory repository for category entity.
+func NewCategoriesMemory(db *MemoryDB) *CategoriesMemory {
+	return &CategoriesMemory{db}
+}
+
+// CategoriesMemory is the memory repository implementation for category entity.
+type CategoriesMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all categories from the database, by id.
+func (r *CategoriesMemory) FindAll(ctx context.Context) (c []internal.Category, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	c = []internal.Category{}
+	for _, id := range sortedKeys(r.db.categories) {
+		c = append(c, r.db.categories[id])
+	}
+
+	return
+}
+
+// Save saves the category into the database.
+func (r *CategoriesMemory) Save(ctx context.Context, c *internal.Category) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// check the parent exists and has no category of the same name
+	err = r.db.checkCategory((*c).ParentId)
+	if err != nil {
+		return
+	}
+	for _, ct := range r.db.categories {
+		if ct.ParentId == (*c).ParentId && ct.Name == (*c).Name {
+			return errCategoryExists(*c)
+		}
+	}
+
+	// set the id
+	r.db.lastCategoryId++
+	(*c).Id = r.db.lastCategoryId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityCategories, (*c).Id, nil, *c)
+	if err != nil {
+		r.db.lastCategoryId--
+		return
+	}
+
+	// insert the category
+	r.db.categories[(*c).Id] = *c
+
+	return
+}
+
+// GetCategoryRevenue returns the sales of the products of every category with sales, converted to currency.
+func (r *CategoriesMemory) GetCategoryRevenue(ctx context.Context, currency internal.Currency) (cr []internal.CategoryRevenue, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	// every invoice can be converted, as the sql repositories check
+	rates := make(map[int]internal.Rate, len(r.db.invoices))
+	for _, id := range sortedKeys(r.db.invoices) {
+		iv := r.db.invoices[id]
+		rate, ok := r.db.rate(iv.Currency, currency, iv.Datetime)
+		if !ok {
+			return nil, errExchangeRateNotFound(iv.Id, iv.Currency, currency)
+		}
+		rates[id] = rate
+	}
+
+	// group the converted sales by the category of their product
+	units := make(map[int]int)
+	revenue := make(map[int]*internal.Converted)
+	for _, sa := range r.db.sales {
+		id := r.db.products[sa.ProductId].CategoryId
+		if id == 0 {
+			continue
+		}
+		if revenue[id] == nil {
+			revenue[id] = new(internal.Converted)
+		}
+		units[id] += sa.Quantity
+		revenue[id].Add(sa.UnitPrice.Mul(sa.Quantity)-sa.Discount, rates[sa.InvoiceId])
+	}
+
+	cr = []internal.CategoryRevenue{}
+	for _, id := range sortedKeys(units) {
+		cr = append(cr, internal.CategoryRevenue{CategoryId: id, Units: units[id], Revenue: revenue[id].Money()})
+	}
+
+	return
+}
+
+// checkCategory checks the category id exists, unless it is 0, as checkCategory does. The caller must hold the lock.
+func (db *MemoryDB) checkCategory(id int) error {
+	if _, ok := db.categories[id]; id != 0 && !ok {
+		return errCategoryNotFound(id)
+	}
+	return nil
+}
+
+// checkProductCatalog checks the category of p exists and its sku is free, as checkProductCatalog does.
+// The caller must hold the lock.
+func (db *MemoryDB) checkProductCatalog(p internal.Product) error {
+	err := db.checkCategory(p.CategoryId)
+	if err != nil {
+		return err
+	}
+	if p.Sku == "" {
+		return nil
+	}
+	for _, pr := range db.products {
+		if pr.Sku == p.Sku {
+			return errProductSkuExists(p.Sku)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectCategoriesQuery   = "SELECT `id`, `name`, `parent_id` FROM categories ORDER BY `id`"
+	ExistsCategoryQuery     = "SELECT COUNT(*) FROM categories WHERE `id` = ?"
+	ExistsCategoryNameQuery = "SELECT COUNT(*) FROM categories WHERE `name` = ? AND COALESCE(`parent_id`, 0) = ?"
+	// CategoryRevenueQuery selects the units and the revenue of the sales of the products of every category,
+	// converted to the currency of both placeholders.
+	CategoryRevenueQuery = "SELECT p.`category_id`, SUM(s.`quantity`), SUM(CASE WHEN i.`currency` = ? THEN s.`quantity` * s.`unit_price` - s.`discount` ELSE (s.`quantity` * s.`unit_price` - s.`discount`) * " + InvoiceExchangeRate + " END) FROM sales AS s INNER JOIN products AS p ON p.`id` = s.`product_id` INNER JOIN invoices AS i ON i.`id` = s.`invoice_id` WHERE p.`category_id` IS NOT NULL GROUP BY p.`category_id` ORDER BY p.`category_id`"
+)
+
+// NewCategoriesMySQL creates new mysql repository for category entity.
+func NewCategoriesMySQL(db *sql.DB) *CategoriesMySQL {
+	return &CategoriesMySQL{db}
+}
+
+// CategoriesMySQL is the MySQL repository implementation for category entity.
+type CategoriesMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all categories from the database, by id.
+func (r *CategoriesMySQL) FindAll(ctx context.Context) (c []internal.Category, err error) {
+	defer observe(ctx, "categories.FindAll", time.Now(), &err)
+
+	c, err = queryCategories(ctx, r.db, SelectCategoriesQuery)
+	return
+}
+
+// Save saves the category into the database.
+func (r *CategoriesMySQL) Save(ctx context.Context, c *internal.Category) (err error) {
+	defer observe(ctx, "categories.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the parent exists and has no category of the same name
+		err = checkCategory(ctx, tx, ExistsCategoryQuery, (*c).ParentId)
+		if err != nil {
+			return err
+		}
+		var n int
+		err = tx.QueryRowContext(ctx, ExistsCategoryNameQuery, (*c).Name, (*c).ParentId).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return errCategoryExists(*c)
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO categories (`name`, `parent_id`) VALUES (?, ?)",
+			(*c).Name, categoryParentId(*c),
+		)
+		if err != nil {
+			return categoryWriteError(*c, err)
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*c).Id = int(id)
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityCategories, (*c).Id, nil, *c)
+	})
+	return
+}
+
+// GetCategoryRevenue returns the sales of the products of every category with sales, converted to currency.
+func (r *CategoriesMySQL) GetCategoryRevenue(ctx context.Context, currency internal.Currency) (cr []internal.CategoryRevenue, err error) {
+	defer observe(ctx, "categories.GetCategoryRevenue", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, r.db, MissingExchangeRatesQuery, currency, currency, currency)
+	if err != nil {
+		return
+	}
+
+	cr, err = queryCategoryRevenue(ctx, r.db, CategoryRevenueQuery, currency, currency)
+	return
+}
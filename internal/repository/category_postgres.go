@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectCategoriesPostgresQuery   = `SELECT "id", "name", "parent_id" FROM categories ORDER BY "id"`
+	ExistsCategoryPostgresQuery     = `SELECT COUNT(*) FROM categories WHERE "id" = $1`
+	ExistsCategoryNamePostgresQuery = `SELECT COUNT(*) FROM categories WHERE "name" = $1 AND COALESCE("parent_id", 0) = $2`
+	// CategoryRevenuePostgresQuery selects the units and the revenue of the sales of the products of every category,
+	// converted to the currency of the placeholder.
+	CategoryRevenuePostgresQuery = `SELECT p."category_id", SUM(s."quantity"), SUM(CASE WHEN i."currency" = $1 THEN s."quantity" * s."unit_price" - s."discount" ELSE (s."quantity" * s."unit_price" - s."discount") * ` + InvoiceExchangeRatePostgres + ` END) FROM sales AS s INNER JOIN products AS p ON p."id" = s."product_id" INNER JOIN invoices AS i ON i."id" = s."invoice_id" WHERE p."category_id" IS NOT NULL GROUP BY p."category_id" ORDER BY p."category_id"`
+)
+
+// NewCategoriesPostgres creates new postgres repository for category entity.
+func NewCategoriesPostgres(db *sql.DB) *CategoriesPostgres {
+	return &CategoriesPostgres{db}
+}
+
+// CategoriesPostgres is the Postgres repository implementation for category entity.
+type CategoriesPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all categories from the database, by id.
+func (r *CategoriesPostgres) FindAll(ctx context.Context) (c []internal.Category, err error) {
+	defer observe(ctx, "categories.FindAll", time.Now(), &err)
+
+	c, err = queryCategories(ctx, r.db, SelectCategoriesPostgresQuery)
+	return
+}
+
+// Save saves the category into the database.
+func (r *CategoriesPostgres) Save(ctx context.Context, c *internal.Category) (err error) {
+	defer observe(ctx, "categories.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the parent exists and has no category of the same name
+		err = checkCategory(ctx, tx, ExistsCategoryPostgresQuery, (*c).ParentId)
+		if err != nil {
+			return err
+		}
+		var n int
+		err = tx.QueryRowContext(ctx, ExistsCategoryNamePostgresQuery, (*c).Name, (*c).ParentId).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return errCategoryExists(*c)
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO categories ("name", "parent_id") VALUES ($1, $2) RETURNING "id"`,
+			(*c).Name, categoryParentId(*c),
+		).Scan(&(*c).Id)
+		if err != nil {
+			return categoryWriteError(*c, err)
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityCategories, (*c).Id, nil, *c)
+	})
+	return
+}
+
+// GetCategoryRevenue returns the sales of the products of every category with sales, converted to currency.
+func (r *CategoriesPostgres) GetCategoryRevenue(ctx context.Context, currency internal.Currency) (cr []internal.CategoryRevenue, err error) {
+	defer observe(ctx, "categories.GetCategoryRevenue", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, r.db, MissingExchangeRatesPostgresQuery, currency, currency)
+	if err != nil {
+		return
+	}
+
+	cr, err = queryCategoryRevenue(ctx, r.db, CategoryRevenuePostgresQuery, currency)
+	return
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// mysqlDuplicateEntry is the mysql error number of a duplicate entry for a unique key.
+	mysqlDuplicateEntry = 1062
+	// postgresUniqueViolation is the postgres error code of a unique violation.
+	postgresUniqueViolation = "23505"
+)
+
+// uniqueViolation returns whether err is the violation of a unique key, in any of the sql drivers.
+// The checks run before a write only tell the conflicts of the committed rows, so the writes map it
+// to the conflict they checked, which a concurrent write made.
+func uniqueViolation(err error) bool {
+	var (
+		errMySQL    *mysql.MySQLError
+		errPostgres *pgconn.PgError
+		errSQLite   *sqlite.Error
+	)
+	switch {
+	case errors.As(err, &errMySQL):
+		return errMySQL.Number == mysqlDuplicateEntry
+	case errors.As(err, &errPostgres):
+		return errPostgres.Code == postgresUniqueViolation
+	case errors.As(err, &errSQLite):
+		return errSQLite.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the unique keys of the sql repositories, violated by a concurrent write that the checks run before
+// the write do not see. The concurrent write is a trigger that inserts the conflicting row right before the write.
+func TestRepositoryUniqueViolation(t *testing.T) {
+	t.Run("customers - email taken by a concurrent save", func(t *testing.T) {
+		// arrange
+		db, err := repository.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec(`CREATE TRIGGER concurrent_save BEFORE INSERT ON customers WHEN NEW."email" IS NOT NULL BEGIN
+			INSERT INTO customers ("first_name", "last_name", "condition", "email", "phone", "created_at", "updated_at")
+			VALUES ('Jane', 'Doe', 1, NEW."email", '', NEW."created_at", NEW."updated_at");
+		END`)
+		require.NoError(t, err)
+		c := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe", Condition: 1, Email: "john@example.com"}}
+
+		// act
+		err = repository.NewCustomersSQLite(db).Save(context.Background(), &c)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrCustomerEmailExists)
+	})
+
+	t.Run("customers - email taken by a concurrent update", func(t *testing.T) {
+		// arrange
+		db, err := repository.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer db.Close()
+		rp := repository.NewCustomersSQLite(db)
+		c := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe", Condition: 1}}
+		require.NoError(t, rp.Save(context.Background(), &c))
+		_, err = db.Exec(`CREATE TRIGGER concurrent_update BEFORE UPDATE ON customers WHEN NEW."email" IS NOT NULL BEGIN
+			INSERT INTO customers ("first_name", "last_name", "condition", "email", "phone", "created_at", "updated_at")
+			VALUES ('Jane', 'Doe', 1, NEW."email", '', NEW."created_at", NEW."updated_at");
+		END`)
+		require.NoError(t, err)
+		c.Email = "john@example.com"
+
+		// act
+		err = rp.Update(context.Background(), &c)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrCustomerEmailExists)
+	})
+
+	t.Run("products - sku taken by a concurrent save", func(t *testing.T) {
+		// arrange
+		db, err := repository.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec(`CREATE TRIGGER concurrent_save BEFORE INSERT ON products WHEN NEW."sku" IS NOT NULL BEGIN
+			INSERT INTO products ("description", "price", "sku") VALUES ('Other', NEW."price", NEW."sku");
+		END`)
+		require.NoError(t, err)
+		p := internal.Product{ProductAttributes: internal.ProductAttributes{Description: "Product 1", Price: internal.MustParseMoney("10"), Sku: "SKU-1"}}
+
+		// act
+		err = repository.NewProductsSQLite(db).Save(context.Background(), &p)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrProductSkuExists)
+	})
+
+	t.Run("categories - name taken by a concurrent save", func(t *testing.T) {
+		// arrange
+		db, err := repository.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec(`CREATE TRIGGER concurrent_save BEFORE INSERT ON categories BEGIN
+			INSERT INTO categories ("name", "parent_id") VALUES (NEW."name", NEW."parent_id");
+		END`)
+		require.NoError(t, err)
+		c := internal.Category{CategoryAttributes: internal.CategoryAttributes{Name: "Drinks"}}
+
+		// act
+		err = repository.NewCategoriesSQLite(db).Save(context.Background(), &c)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrCategoryExists)
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal"
+)
+
+// errExchangeRateNotFound returns the error of an invoice whose total cannot be converted into to.
+func errExchangeRateNotFound(invoiceId int, from, to internal.Currency) error {
+	return fmt.Errorf("%w: invoice %d from %s to %s", internal.ErrExchangeRateNotFound, invoiceId, from, to)
+}
+
+// errCurrencyMismatch returns the error of a sale whose product is not in the currency of its invoice.
+func errCurrencyMismatch(productId int, product internal.Currency, invoiceId int, invoice internal.Currency) error {
+	return fmt.Errorf("%w: product %d is in %s, invoice %d in %s", internal.ErrCurrencyMismatch, productId, product, invoiceId, invoice)
+}
+
+// errExchangeRateExists returns the error of an exchange rate already effective from the same date.
+func errExchangeRateExists(e internal.ExchangeRate) error {
+	return fmt.Errorf("%w: %s to %s from %s", internal.ErrExchangeRateExists, e.From, e.To, e.EffectiveDate)
+}
+
+// checkExchangeRates checks that every invoice can be converted into to.
+// query selects the id and currency of the invoices without a rate effective at their date, args are its arguments.
+func checkExchangeRates(ctx context.Context, q querier, query string, to internal.Currency, args ...any) (err error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var (
+			id   int
+			from internal.Currency
+		)
+		err = rows.Scan(&id, &from)
+		if err != nil {
+			return
+		}
+		return errExchangeRateNotFound(id, from, to)
+	}
+	err = rows.Err()
+	return
+}
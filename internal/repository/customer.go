@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/internal"
+)
+
+// customerQueries are the queries of a sql database on the customers and their addresses.
+// The arguments of each query are listed in the order of its placeholders.
+type customerQueries struct {
+	// customers selects every customer, as scanCustomer expects them, by id.
+	customers string
+	// customer selects the customer of an id, as scanCustomer expects it: id.
+	customer string
+	// emailExists counts the other customers of an email: email, id.
+	emailExists string
+	// update sets the attributes of a customer: first_name, last_name, condition, email, phone, updated_at, id.
+	update string
+	// addresses selects every address, as scanCustomerAddress expects them, by customer_id and kind.
+	addresses string
+	// customerAddresses selects the addresses of a customer, as scanCustomerAddress expects them, by kind: customer_id.
+	customerAddresses string
+	// insertAddress inserts an address: customer_id, kind, line1, line2, city, state, postal_code, country.
+	insertAddress string
+	// deleteAddresses deletes the addresses of a customer: customer_id.
+	deleteAddresses string
+	// audit inserts an audit record, as writeAudit expects it.
+	audit string
+}
+
+// customerDatetime returns t in the layout of the creation and update datetimes of the customers,
+// the one of the invoice datetimes.
+func customerDatetime(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
+
+// errCustomerNotFound returns the error of the customer id that does not exist.
+func errCustomerNotFound(id int) error {
+	return fmt.Errorf("%w: %d", internal.ErrCustomerNotFound, id)
+}
+
+// errCustomerEmailExists returns the error of a customer whose email another customer has.
+func errCustomerEmailExists(email string) error {
+	return fmt.Errorf("%w: %s", internal.ErrCustomerEmailExists, email)
+}
+
+// customerWriteError returns err, the error of a write of c, as the conflict of its email when it violates
+// the unique key of the emails: checkCustomerEmail does not see the customers of the concurrent writes.
+func customerWriteError(c internal.Customer, err error) error {
+	if uniqueViolation(err) {
+		return errCustomerEmailExists(c.Email)
+	}
+	return err
+}
+
+// customerEmail returns the email of c as stored, NULL if it has none, so the customers without one do not collide.
+func customerEmail(c internal.Customer) sql.NullString {
+	return sql.NullString{String: c.Email, Valid: c.Email != ""}
+}
+
+// scanCustomer scans the id, first name, last name, condition, email, phone, creation and update datetimes
+// of a customer from row into c, the email read as "" when NULL.
+func scanCustomer(row scanner, c *internal.Customer) error {
+	return row.Scan(&(*c).Id, &(*c).FirstName, &(*c).LastName, &(*c).Condition, &(*c).Email, &(*c).Phone, &(*c).CreatedAt, &(*c).UpdatedAt)
+}
+
+// scanCustomerAddress scans the customer id, kind, line 1, line 2, city, state, postal code and country
+// of an address from row.
+func scanCustomerAddress(row scanner) (customerId int, a internal.CustomerAddress, err error) {
+	err = row.Scan(&customerId, &a.Kind, &a.Line1, &a.Line2, &a.City, &a.State, &a.PostalCode, &a.Country)
+	return
+}
+
+// checkCustomerEmail checks that no customer but c has its email, unless it has none.
+// It is a fast path: the writes of concurrent transactions are caught by customerWriteError.
+func checkCustomerEmail(ctx context.Context, q rowQuerier, cq customerQueries, c internal.Customer) (err error) {
+	if c.Email == "" {
+		return
+	}
+	var n int
+	err = q.QueryRowContext(ctx, cq.emailExists, c.Email, c.Id).Scan(&n)
+	if err != nil {
+		return
+	}
+	if n > 0 {
+		return errCustomerEmailExists(c.Email)
+	}
+	return
+}
+
+// insertCustomerAddresses inserts the addresses of c within tx.
+func insertCustomerAddresses(ctx context.Context, tx execer, cq customerQueries, c internal.Customer) (err error) {
+	for _, a := range c.Addresses {
+		_, err = tx.ExecContext(ctx, cq.insertAddress, c.Id, a.Kind, a.Line1, a.Line2, a.City, a.State, a.PostalCode, a.Country)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// queryCustomers returns every customer, by id, with its addresses.
+func queryCustomers(ctx context.Context, db querier, cq customerQueries) (c []internal.Customer, err error) {
+	// - addresses, by customer
+	addresses, err := queryCustomerAddresses(ctx, db, cq.addresses)
+	if err != nil {
+		return
+	}
+
+	// - customers
+	rows, err := db.QueryContext(ctx, cq.customers)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cs internal.Customer
+		err = scanCustomer(rows, &cs)
+		if err != nil {
+			return
+		}
+		cs.Addresses = addresses[cs.Id]
+		c = append(c, cs)
+	}
+	err = rows.Err()
+	return
+}
+
+// queryCustomerAddresses returns the addresses selected by query with args, by customer.
+func queryCustomerAddresses(ctx context.Context, db querier, query string, args ...any) (a map[int][]internal.CustomerAddress, err error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	a = make(map[int][]internal.CustomerAddress)
+	for rows.Next() {
+		customerId, ad, err := scanCustomerAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		a[customerId] = append(a[customerId], ad)
+	}
+	err = rows.Err()
+	return
+}
+
+// updateCustomerAudited replaces the attributes and the addresses of the customer c.Id within tx, and audits
+// the change. It sets the update datetime of c and fills its creation datetime.
+func updateCustomerAudited(ctx context.Context, tx *sql.Tx, cq customerQueries, c *internal.Customer) (err error) {
+	// - current customer
+	var before internal.Customer
+	err = scanCustomer(tx.QueryRowContext(ctx, cq.customer, (*c).Id), &before)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errCustomerNotFound((*c).Id)
+	}
+	if err != nil {
+		return
+	}
+	addresses, err := queryCustomerAddresses(ctx, tx, cq.customerAddresses, before.Id)
+	if err != nil {
+		return
+	}
+	before.Addresses = addresses[before.Id]
+
+	// - email
+	err = checkCustomerEmail(ctx, tx, cq, *c)
+	if err != nil {
+		return
+	}
+
+	// - customer and addresses
+	(*c).CreatedAt, (*c).UpdatedAt = before.CreatedAt, customerDatetime(time.Now())
+	_, err = tx.ExecContext(ctx, cq.update, (*c).FirstName, (*c).LastName, (*c).Condition, customerEmail(*c), (*c).Phone, (*c).UpdatedAt, (*c).Id)
+	if err != nil {
+		return customerWriteError(*c, err)
+	}
+	_, err = tx.ExecContext(ctx, cq.deleteAddresses, (*c).Id)
+	if err != nil {
+		return
+	}
+	err = insertCustomerAddresses(ctx, tx, cq, *c)
+	if err != nil {
+		return
+	}
+
+	// - audit
+	return writeAudit(ctx, tx, cq.audit, AuditEntityCustomers, (*c).Id, before, *c)
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"app/internal"
+)
+
+// NewCustomersMemory creates new memory repository for customer entity.
+func NewCustomersMemory(db *MemoryDB) *CustomersMemory {
+	return &CustomersMemory{db}
+}
+
+// CustomersMemory is the memory repository implementation for customer entity.
+type CustomersMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all customers from the database, with their addresses.
+func (r *CustomersMemory) FindAll(ctx context.Context) (c []internal.Customer, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, id := range sortedKeys(r.db.customers) {
+		c = append(c, r.db.customers[id])
+	}
+
+	return
+}
+
+// Save saves the customer and its addresses into the database.
+func (r *CustomersMemory) Save(ctx context.Context, c *internal.Customer) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// check the email is free
+	err = r.db.checkCustomerEmail(*c)
+	if err != nil {
+		return
+	}
+
+	// set the id and the datetimes
+	r.db.lastCustomerId++
+	(*c).Id = r.db.lastCustomerId
+	(*c).CreatedAt = customerDatetime(time.Now())
+	(*c).UpdatedAt = (*c).CreatedAt
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityCustomers, (*c).Id, nil, *c)
+	if err != nil {
+		r.db.lastCustomerId--
+		return
+	}
+
+	// insert the customer, with a copy of its addresses
+	r.db.customers[(*c).Id] = copyCustomer(*c)
+
+	return
+}
+
+// Update replaces the attributes and the addresses of the customer c.Id.
+func (r *CustomersMemory) Update(ctx context.Context, c *internal.Customer) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	before, ok := r.db.customers[(*c).Id]
+	if !ok {
+		return errCustomerNotFound((*c).Id)
+	}
+
+	// check the email is free
+	err = r.db.checkCustomerEmail(*c)
+	if err != nil {
+		return
+	}
+
+	// set the datetimes
+	(*c).CreatedAt = before.CreatedAt
+	(*c).UpdatedAt = customerDatetime(time.Now())
+
+	// audit the change, under the same lock as the update
+	err = r.db.audit(ctx, AuditEntityCustomers, (*c).Id, before, *c)
+	if err != nil {
+		return
+	}
+
+	// update the customer
+	r.db.customers[(*c).Id] = copyCustomer(*c)
+
+	return
+}
+
+// checkCustomerEmail checks the email of c: an empty email is allowed, otherwise no other customer may have it.
+// The caller must hold the lock.
+func (db *MemoryDB) checkCustomerEmail(c internal.Customer) error {
+	if c.Email == "" {
+		return nil
+	}
+	for _, cs := range db.customers {
+		if cs.Email == c.Email && cs.Id != c.Id {
+			return errCustomerEmailExists(c.Email)
+		}
+	}
+	return nil
+}
+
+// copyCustomer returns c with a copy of its addresses, so the caller cannot change the stored ones,
+// ordered by kind as the sql repositories select them.
+func copyCustomer(c internal.Customer) internal.Customer {
+	if c.Addresses != nil {
+		c.Addresses = append([]internal.CustomerAddress{}, c.Addresses...)
+		sort.Slice(c.Addresses, func(i, j int) bool { return c.Addresses[i].Kind < c.Addresses[j].Kind })
+	}
+	return c
+}
+
+// GetTopCustomers returns the 5 customers with the highest invoiced amount, converted to currency.
+func (r *CustomersMemory) GetTopCustomers(ctx context.Context, currency internal.Currency) ([]internal.TopCustomer, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	// group the converted invoices by customer
+	nets := make(map[int]*internal.Converted)
+	amounts := make(map[int]*internal.Converted)
+	for _, id := range sortedKeys(r.db.invoices) {
+		iv := r.db.invoices[id]
+		rate, ok := r.db.rate(iv.Currency, currency, iv.Datetime)
+		if !ok {
+			return nil, errExchangeRateNotFound(iv.Id, iv.Currency, currency)
+		}
+		if amounts[iv.CustomerId] == nil {
+			nets[iv.CustomerId] = new(internal.Converted)
+			amounts[iv.CustomerId] = new(internal.Converted)
+		}
+		nets[iv.CustomerId].Add(iv.Subtotal, rate)
+		amounts[iv.CustomerId].Add(iv.Total, rate)
+	}
+
+	topCustomers := make([]internal.TopCustomer, 0, len(amounts))
+	for id, amount := range amounts {
+		cs, ok := r.db.customers[id]
+		if !ok {
+			continue
+		}
+		topCustomers = append(topCustomers, internal.TopCustomer{
+			Id:        cs.Id,
+			FirstName: cs.FirstName,
+			LastName:  cs.LastName,
+			Net:       nets[id].Money(),
+			Amount:    amount.Money(),
+			Currency:  currency,
+		})
+	}
+
+	// order by amount desc, ties by id
+	sort.Slice(topCustomers, func(i, j int) bool {
+		if topCustomers[i].Amount != topCustomers[j].Amount {
+			return topCustomers[i].Amount > topCustomers[j].Amount
+		}
+		return topCustomers[i].Id < topCustomers[j].Id
+	})
+	if len(topCustomers) > 5 {
+		topCustomers = topCustomers[:5]
+	}
+
+	return topCustomers, nil
+}
+
+// Search returns the page of q of the customers matching its text, ranked by internal.MatchScore.
+func (r *CustomersMemory) Search(ctx context.Context, q internal.SearchQuery) (m internal.CustomerMatches, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	c := make([]internal.Customer, 0, len(r.db.customers))
+	for _, id := range sortedKeys(r.db.customers) {
+		c = append(c, r.db.customers[id])
+	}
+	m = searchCustomers(c, q)
+	return
+}
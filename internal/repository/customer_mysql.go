@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"app/internal"
 )
@@ -18,76 +22,194 @@ type CustomersMySQL struct {
 }
 
 const (
-	GetTopCustomersQuery = "SELECT c.`id`, c.`first_name`, c.`last_name`, SUM(i.`total`) AS amount FROM customers AS c INNER JOIN invoices AS i ON c.`id` = i.`customer_id` GROUP BY c.`id` ORDER BY amount DESC LIMIT 5"
+	GetTopCustomersQuery = "SELECT c.`id`, c.`first_name`, c.`last_name`, SUM(" + ConvertedInvoiceSubtotal + ") AS net, SUM(" + ConvertedInvoiceTotal + ") AS amount FROM customers AS c INNER JOIN invoices AS i ON c.`id` = i.`customer_id` GROUP BY c.`id` ORDER BY amount DESC LIMIT 5"
+
+	SelectCustomersQuery           = "SELECT `id`, `first_name`, `last_name`, `condition`, COALESCE(`email`, ''), `phone`, `created_at`, `updated_at` FROM customers ORDER BY `id`"
+	SelectCustomerQuery            = "SELECT `id`, `first_name`, `last_name`, `condition`, COALESCE(`email`, ''), `phone`, `created_at`, `updated_at` FROM customers WHERE `id` = ?"
+	ExistsCustomerEmailQuery       = "SELECT COUNT(*) FROM customers WHERE `email` = ? AND `id` <> ?"
+	InsertCustomerQuery            = "INSERT INTO customers (`first_name`, `last_name`, `condition`, `email`, `phone`, `created_at`, `updated_at`) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	UpdateCustomerQuery            = "UPDATE customers SET `first_name` = ?, `last_name` = ?, `condition` = ?, `email` = ?, `phone` = ?, `updated_at` = ? WHERE `id` = ?"
+	SelectCustomerAddressesQuery   = "SELECT `customer_id`, `kind`, `line1`, `line2`, `city`, `state`, `postal_code`, `country` FROM customer_addresses ORDER BY `customer_id`, `kind`"
+	SelectAddressesOfCustomerQuery = "SELECT `customer_id`, `kind`, `line1`, `line2`, `city`, `state`, `postal_code`, `country` FROM customer_addresses WHERE `customer_id` = ? ORDER BY `kind`"
+	InsertCustomerAddressQuery     = "INSERT INTO customer_addresses (`customer_id`, `kind`, `line1`, `line2`, `city`, `state`, `postal_code`, `country`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	DeleteCustomerAddressesQuery   = "DELETE FROM customer_addresses WHERE `customer_id` = ?"
 )
 
-// FindAll returns all customers from the database.
-func (r *CustomersMySQL) FindAll() (c []internal.Customer, err error) {
-	// execute the query
-	rows, err := r.db.Query("SELECT `id`, `first_name`, `last_name`, `condition` FROM customers")
+// customerMySQLQueries are the queries of the customers and their addresses.
+var customerMySQLQueries = customerQueries{
+	customers:         SelectCustomersQuery,
+	customer:          SelectCustomerQuery,
+	emailExists:       ExistsCustomerEmailQuery,
+	update:            UpdateCustomerQuery,
+	addresses:         SelectCustomerAddressesQuery,
+	customerAddresses: SelectAddressesOfCustomerQuery,
+	insertAddress:     InsertCustomerAddressQuery,
+	deleteAddresses:   DeleteCustomerAddressesQuery,
+	audit:             InsertAuditRecordQuery,
+}
+
+const (
+	// SoundsLikeCustomerCondition is the condition that the term, its only placeholder, sounds like a word of the first
+	// or last name.
+	SoundsLikeCustomerCondition = soundsLikeWordPrefix + "CONCAT_WS(' ', `first_name`, `last_name`)" + soundsLikeWordSuffix
+	// SearchCustomersQuery selects a page of the customers matching the condition that replaces the second %s verb,
+	// scored by the expression that replaces the first.
+	SearchCustomersQuery = "SELECT `id`, `first_name`, `last_name`, `condition`, COALESCE(`email`, ''), `phone`, `created_at`, `updated_at`, %s AS score FROM customers WHERE %s ORDER BY score DESC, `id` LIMIT ? OFFSET ?"
+	// CountCustomersQuery counts the customers matching the condition that replaces the %s verb.
+	CountCustomersQuery = "SELECT COUNT(*) FROM customers WHERE %s"
+	// SearchCustomerAddressesQuery selects the addresses of the customers of the ids that replace the %s verb,
+	// a placeholder each.
+	SearchCustomerAddressesQuery = "SELECT `customer_id`, `kind`, `line1`, `line2`, `city`, `state`, `postal_code`, `country` FROM customer_addresses WHERE `customer_id` IN (%s) ORDER BY `customer_id`, `kind`"
+)
+
+// customersFullTextSearch is the search of the customers in the full-text index of their names.
+var customersFullTextSearch = fullTextSearch{columns: "`first_name`, `last_name`", soundsLike: SoundsLikeCustomerCondition}
+
+// FindAll returns all customers from the database, with their addresses.
+func (r *CustomersMySQL) FindAll(ctx context.Context) (c []internal.Customer, err error) {
+	defer observe(ctx, "customers.FindAll", time.Now(), &err)
+
+	return queryCustomers(ctx, r.db, customerMySQLQueries)
+}
+
+// Save saves the customer and its addresses into the database.
+func (r *CustomersMySQL) Save(ctx context.Context, c *internal.Customer) (err error) {
+	defer observe(ctx, "customers.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the email is free
+		err = checkCustomerEmail(ctx, tx, customerMySQLQueries, *c)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		(*c).CreatedAt = customerDatetime(time.Now())
+		(*c).UpdatedAt = (*c).CreatedAt
+		res, err := tx.ExecContext(ctx, InsertCustomerQuery,
+			(*c).FirstName, (*c).LastName, (*c).Condition, customerEmail(*c), (*c).Phone, (*c).CreatedAt, (*c).UpdatedAt,
+		)
+		if err != nil {
+			return customerWriteError(*c, err)
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*c).Id = int(id)
+
+		// insert the addresses
+		err = insertCustomerAddresses(ctx, tx, customerMySQLQueries, *c)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityCustomers, (*c).Id, nil, *c)
+	})
+	return
+}
+
+// Update replaces the attributes and the addresses of the customer c.Id.
+func (r *CustomersMySQL) Update(ctx context.Context, c *internal.Customer) (err error) {
+	defer observe(ctx, "customers.Update", time.Now(), &err)
+
+	// update the record, its addresses and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateCustomerAudited(ctx, tx, customerMySQLQueries, c)
+	})
+	return
+}
+
+// GetTopCustomers returns the 5 customers with the highest invoiced amount, converted to currency.
+func (c *CustomersMySQL) GetTopCustomers(ctx context.Context, currency internal.Currency) (topCustomers []internal.TopCustomer, err error) {
+	defer observe(ctx, "customers.GetTopCustomers", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, c.db, MissingExchangeRatesQuery, currency, currency, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.QueryContext(ctx, GetTopCustomersQuery, currency, currency, currency, currency)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	// iterate over the rows
+	topCustomers = []internal.TopCustomer{}
 	for rows.Next() {
-		var cs internal.Customer
-		// scan the row into the customer
-		err := rows.Scan(&cs.Id, &cs.FirstName, &cs.LastName, &cs.Condition)
+		tc := internal.TopCustomer{Currency: currency}
+		err := rows.Scan(&tc.Id, &tc.FirstName, &tc.LastName, &tc.Net, &tc.Amount)
 		if err != nil {
 			return nil, err
 		}
-		// append the customer to the slice
-		c = append(c, cs)
+
+		topCustomers = append(topCustomers, tc)
 	}
 	err = rows.Err()
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	return
+	return topCustomers, nil
 }
 
-// Save saves the customer into the database.
-func (r *CustomersMySQL) Save(c *internal.Customer) (err error) {
-	// execute the query
-	res, err := r.db.Exec(
-		"INSERT INTO customers (`first_name`, `last_name`, `condition`) VALUES (?, ?, ?)",
-		(*c).FirstName, (*c).LastName, (*c).Condition,
-	)
-	if err != nil {
-		return err
+// Search returns the page of q of the customers matching its text, ranked by the relevance of the full-text index.
+func (r *CustomersMySQL) Search(ctx context.Context, q internal.SearchQuery) (m internal.CustomerMatches, err error) {
+	defer observe(ctx, "customers.Search", time.Now(), &err)
+
+	m.Matches = []internal.CustomerMatch{}
+	terms := q.Terms()
+	if len(terms) == 0 {
+		return
 	}
+	cond, condArgs := customersFullTextSearch.condition(terms)
+	score, scoreArgs := customersFullTextSearch.score(terms)
 
-	// get the last inserted id
-	id, err := res.LastInsertId()
+	// count the customers found
+	err = r.db.QueryRowContext(ctx, fmt.Sprintf(CountCustomersQuery, cond), condArgs...).Scan(&m.Total)
 	if err != nil {
-		return err
+		return
 	}
 
-	// set the id
-	(*c).Id = int(id)
-
-	return
-}
-
-func (c *CustomersMySQL) GetTopCustomers() ([]internal.TopCustomer, error) {
-	rows, err := c.db.Query(GetTopCustomersQuery)
+	// execute the query of the page
+	args := append(append(scoreArgs, condArgs...), q.Limit, q.Offset)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(SearchCustomersQuery, score, cond), args...)
 	if err != nil {
-		return nil, err
+		return
 	}
+	defer rows.Close()
 
-	topCustomers := []internal.TopCustomer{}
 	for rows.Next() {
-		var tc internal.TopCustomer
-		err := rows.Scan(&tc.Id, &tc.FirstName, &tc.LastName, &tc.Amount)
+		var cm internal.CustomerMatch
+		err = rows.Scan(&cm.Id, &cm.FirstName, &cm.LastName, &cm.Condition, &cm.Email, &cm.Phone, &cm.CreatedAt, &cm.UpdatedAt, &cm.Score)
 		if err != nil {
-			return nil, err
+			return
 		}
-
-		topCustomers = append(topCustomers, tc)
+		m.Matches = append(m.Matches, cm)
+	}
+	err = rows.Err()
+	if err != nil || len(m.Matches) == 0 {
+		return
 	}
 
-	return topCustomers, nil
+	// the addresses of the customers of the page
+	ids := make([]any, len(m.Matches))
+	for ix, cm := range m.Matches {
+		ids[ix] = cm.Id
+	}
+	query := fmt.Sprintf(SearchCustomerAddressesQuery, strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "))
+	addresses, err := queryCustomerAddresses(ctx, r.db, query, ids...)
+	if err != nil {
+		return
+	}
+	for ix := range m.Matches {
+		m.Matches[ix].Addresses = addresses[m.Matches[ix].Id]
+	}
+	return
 }
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+// NewCustomersPostgres creates new postgres repository for customer entity.
+func NewCustomersPostgres(db *sql.DB) *CustomersPostgres {
+	return &CustomersPostgres{db}
+}
+
+// CustomersPostgres is the Postgres repository implementation for customer entity.
+type CustomersPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+const (
+	GetTopCustomersPostgresQuery = `SELECT c."id", c."first_name", c."last_name", SUM(` + ConvertedInvoiceSubtotalPostgres + `) AS net, SUM(` + ConvertedInvoiceTotalPostgres + `) AS amount FROM customers AS c INNER JOIN invoices AS i ON c."id" = i."customer_id" GROUP BY c."id" ORDER BY amount DESC LIMIT 5`
+
+	CustomerColumnsPostgres                = `"id", "first_name", "last_name", "condition", COALESCE("email", ''), "phone", to_char("created_at", 'YYYY-MM-DD HH24:MI:SS'), to_char("updated_at", 'YYYY-MM-DD HH24:MI:SS')`
+	CustomerAddressColumnsPostgres         = `"customer_id", "kind", "line1", "line2", "city", "state", "postal_code", "country"`
+	SelectCustomersPostgresQuery           = `SELECT ` + CustomerColumnsPostgres + ` FROM customers ORDER BY "id"`
+	SelectCustomerPostgresQuery            = `SELECT ` + CustomerColumnsPostgres + ` FROM customers WHERE "id" = $1`
+	ExistsCustomerEmailPostgresQuery       = `SELECT COUNT(*) FROM customers WHERE "email" = $1 AND "id" <> $2`
+	InsertCustomerPostgresQuery            = `INSERT INTO customers ("first_name", "last_name", "condition", "email", "phone", "created_at", "updated_at") VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING "id"`
+	UpdateCustomerPostgresQuery            = `UPDATE customers SET "first_name" = $1, "last_name" = $2, "condition" = $3, "email" = $4, "phone" = $5, "updated_at" = $6 WHERE "id" = $7`
+	SelectCustomerAddressesPostgresQuery   = `SELECT ` + CustomerAddressColumnsPostgres + ` FROM customer_addresses ORDER BY "customer_id", "kind"`
+	SelectAddressesOfCustomerPostgresQuery = `SELECT ` + CustomerAddressColumnsPostgres + ` FROM customer_addresses WHERE "customer_id" = $1 ORDER BY "kind"`
+	InsertCustomerAddressPostgresQuery     = `INSERT INTO customer_addresses (` + CustomerAddressColumnsPostgres + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	DeleteCustomerAddressesPostgresQuery   = `DELETE FROM customer_addresses WHERE "customer_id" = $1`
+)
+
+// customerPostgresQueries are the queries of the customers and their addresses.
+var customerPostgresQueries = customerQueries{
+	customers:         SelectCustomersPostgresQuery,
+	customer:          SelectCustomerPostgresQuery,
+	emailExists:       ExistsCustomerEmailPostgresQuery,
+	update:            UpdateCustomerPostgresQuery,
+	addresses:         SelectCustomerAddressesPostgresQuery,
+	customerAddresses: SelectAddressesOfCustomerPostgresQuery,
+	insertAddress:     InsertCustomerAddressPostgresQuery,
+	deleteAddresses:   DeleteCustomerAddressesPostgresQuery,
+	audit:             InsertAuditRecordPostgresQuery,
+}
+
+// FindAll returns all customers from the database, with their addresses.
+func (r *CustomersPostgres) FindAll(ctx context.Context) (c []internal.Customer, err error) {
+	defer observe(ctx, "customers.FindAll", time.Now(), &err)
+
+	return queryCustomers(ctx, r.db, customerPostgresQueries)
+}
+
+// Save saves the customer and its addresses into the database.
+func (r *CustomersPostgres) Save(ctx context.Context, c *internal.Customer) (err error) {
+	defer observe(ctx, "customers.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the email is free
+		err = checkCustomerEmail(ctx, tx, customerPostgresQueries, *c)
+		if err != nil {
+			return err
+		}
+
+		// execute the query, returning the generated id
+		(*c).CreatedAt = customerDatetime(time.Now())
+		(*c).UpdatedAt = (*c).CreatedAt
+		err = tx.QueryRowContext(ctx, InsertCustomerPostgresQuery,
+			(*c).FirstName, (*c).LastName, (*c).Condition, customerEmail(*c), (*c).Phone, (*c).CreatedAt, (*c).UpdatedAt,
+		).Scan(&(*c).Id)
+		if err != nil {
+			return customerWriteError(*c, err)
+		}
+
+		// insert the addresses
+		err = insertCustomerAddresses(ctx, tx, customerPostgresQueries, *c)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityCustomers, (*c).Id, nil, *c)
+	})
+	return
+}
+
+// Update replaces the attributes and the addresses of the customer c.Id.
+func (r *CustomersPostgres) Update(ctx context.Context, c *internal.Customer) (err error) {
+	defer observe(ctx, "customers.Update", time.Now(), &err)
+
+	// update the record, its addresses and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateCustomerAudited(ctx, tx, customerPostgresQueries, c)
+	})
+	return
+}
+
+// GetTopCustomers returns the 5 customers with the highest invoiced amount, converted to currency.
+func (c *CustomersPostgres) GetTopCustomers(ctx context.Context, currency internal.Currency) (topCustomers []internal.TopCustomer, err error) {
+	defer observe(ctx, "customers.GetTopCustomers", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, c.db, MissingExchangeRatesPostgresQuery, currency, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.QueryContext(ctx, GetTopCustomersPostgresQuery, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	topCustomers = []internal.TopCustomer{}
+	for rows.Next() {
+		tc := internal.TopCustomer{Currency: currency}
+		err := rows.Scan(&tc.Id, &tc.FirstName, &tc.LastName, &tc.Net, &tc.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		topCustomers = append(topCustomers, tc)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return topCustomers, nil
+}
+
+// Search returns the page of q of the customers matching its text, ranked by internal.MatchScore,
+// as postgres has no full-text index of the customers.
+func (r *CustomersPostgres) Search(ctx context.Context, q internal.SearchQuery) (m internal.CustomerMatches, err error) {
+	defer observe(ctx, "customers.Search", time.Now(), &err)
+
+	c, err := r.FindAll(ctx)
+	if err != nil {
+		return
+	}
+	m = searchCustomers(c, q)
+	return
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+// NewCustomersSQLite creates new sqlite repository for customer entity.
+func NewCustomersSQLite(db *sql.DB) *CustomersSQLite {
+	return &CustomersSQLite{db}
+}
+
+// CustomersSQLite is the SQLite repository implementation for customer entity.
+type CustomersSQLite struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+const (
+	GetTopCustomersSQLiteQuery = `SELECT c."id", c."first_name", c."last_name", SUM(` + ConvertedInvoiceSubtotalSQLite + `) AS net, SUM(` + ConvertedInvoiceTotalSQLite + `) AS amount FROM customers AS c INNER JOIN invoices AS i ON c."id" = i."customer_id" GROUP BY c."id" ORDER BY amount DESC LIMIT 5`
+
+	SelectCustomersSQLiteQuery           = `SELECT "id", "first_name", "last_name", "condition", COALESCE("email", ''), "phone", "created_at", "updated_at" FROM customers ORDER BY "id"`
+	SelectCustomerSQLiteQuery            = `SELECT "id", "first_name", "last_name", "condition", COALESCE("email", ''), "phone", "created_at", "updated_at" FROM customers WHERE "id" = ?`
+	ExistsCustomerEmailSQLiteQuery       = `SELECT COUNT(*) FROM customers WHERE "email" = ? AND "id" <> ?`
+	InsertCustomerSQLiteQuery            = `INSERT INTO customers ("first_name", "last_name", "condition", "email", "phone", "created_at", "updated_at") VALUES (?, ?, ?, ?, ?, ?, ?)`
+	UpdateCustomerSQLiteQuery            = `UPDATE customers SET "first_name" = ?, "last_name" = ?, "condition" = ?, "email" = ?, "phone" = ?, "updated_at" = ? WHERE "id" = ?`
+	SelectCustomerAddressesSQLiteQuery   = `SELECT "customer_id", "kind", "line1", "line2", "city", "state", "postal_code", "country" FROM customer_addresses ORDER BY "customer_id", "kind"`
+	SelectAddressesOfCustomerSQLiteQuery = `SELECT "customer_id", "kind", "line1", "line2", "city", "state", "postal_code", "country" FROM customer_addresses WHERE "customer_id" = ? ORDER BY "kind"`
+	InsertCustomerAddressSQLiteQuery     = `INSERT INTO customer_addresses ("customer_id", "kind", "line1", "line2", "city", "state", "postal_code", "country") VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	DeleteCustomerAddressesSQLiteQuery   = `DELETE FROM customer_addresses WHERE "customer_id" = ?`
+)
+
+// customerSQLiteQueries are the queries of the customers and their addresses.
+var customerSQLiteQueries = customerQueries{
+	customers:         SelectCustomersSQLiteQuery,
+	customer:          SelectCustomerSQLiteQuery,
+	emailExists:       ExistsCustomerEmailSQLiteQuery,
+	update:            UpdateCustomerSQLiteQuery,
+	addresses:         SelectCustomerAddressesSQLiteQuery,
+	customerAddresses: SelectAddressesOfCustomerSQLiteQuery,
+	insertAddress:     InsertCustomerAddressSQLiteQuery,
+	deleteAddresses:   DeleteCustomerAddressesSQLiteQuery,
+	audit:             InsertAuditRecordSQLiteQuery,
+}
+
+// FindAll returns all customers from the database, with their addresses.
+func (r *CustomersSQLite) FindAll(ctx context.Context) (c []internal.Customer, err error) {
+	defer observe(ctx, "customers.FindAll", time.Now(), &err)
+
+	return queryCustomers(ctx, r.db, customerSQLiteQueries)
+}
+
+// Save saves the customer and its addresses into the database.
+func (r *CustomersSQLite) Save(ctx context.Context, c *internal.Customer) (err error) {
+	defer observe(ctx, "customers.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the email is free
+		err = checkCustomerEmail(ctx, tx, customerSQLiteQueries, *c)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		(*c).CreatedAt = customerDatetime(time.Now())
+		(*c).UpdatedAt = (*c).CreatedAt
+		res, err := tx.ExecContext(ctx, InsertCustomerSQLiteQuery,
+			(*c).FirstName, (*c).LastName, (*c).Condition, customerEmail(*c), (*c).Phone, (*c).CreatedAt, (*c).UpdatedAt,
+		)
+		if err != nil {
+			return customerWriteError(*c, err)
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*c).Id = int(id)
+
+		// insert the addresses
+		err = insertCustomerAddresses(ctx, tx, customerSQLiteQueries, *c)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordSQLiteQuery, AuditEntityCustomers, (*c).Id, nil, *c)
+	})
+	return
+}
+
+// Update replaces the attributes and the addresses of the customer c.Id.
+func (r *CustomersSQLite) Update(ctx context.Context, c *internal.Customer) (err error) {
+	defer observe(ctx, "customers.Update", time.Now(), &err)
+
+	// update the record, its addresses and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateCustomerAudited(ctx, tx, customerSQLiteQueries, c)
+	})
+	return
+}
+
+// GetTopCustomers returns the 5 customers with the highest invoiced amount, converted to currency.
+func (c *CustomersSQLite) GetTopCustomers(ctx context.Context, currency internal.Currency) (topCustomers []internal.TopCustomer, err error) {
+	defer observe(ctx, "customers.GetTopCustomers", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, c.db, MissingExchangeRatesSQLiteQuery, currency, currency, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.QueryContext(ctx, GetTopCustomersSQLiteQuery, currency, currency, currency, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	topCustomers = []internal.TopCustomer{}
+	for rows.Next() {
+		tc := internal.TopCustomer{Currency: currency}
+		err := rows.Scan(&tc.Id, &tc.FirstName, &tc.LastName, &tc.Net, &tc.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		topCustomers = append(topCustomers, tc)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return topCustomers, nil
+}
+
+// Search returns the page of q of the customers matching its text, ranked by internal.MatchScore,
+// as sqlite has no full-text index of the customers.
+func (r *CustomersSQLite) Search(ctx context.Context, q internal.SearchQuery) (m internal.CustomerMatches, err error) {
+	defer observe(ctx, "customers.Search", time.Now(), &err)
+
+	c, err := r.FindAll(ctx)
+	if err != nil {
+		return
+	}
+	m = searchCustomers(c, q)
+	return
+}
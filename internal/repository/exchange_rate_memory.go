@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewExchangeRatesMemory creates new memory repository for exchange rate entity.
+func NewExchangeRatesMemory(db *MemoryDB) *ExchangeRatesMemory {
+	return &ExchangeRatesMemory{db}
+}
+
+// ExchangeRatesMemory is the memory repository implementation for exchange rate entity.
+type ExchangeRatesMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all exchange rates from the database.
+func (r *ExchangeRatesMemory) FindAll(ctx context.Context) (e []internal.ExchangeRate, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, id := range sortedKeys(r.db.exchangeRates) {
+		e = append(e, r.db.exchangeRates[id])
+	}
+
+	return
+}
+
+// Save saves the exchange rate into the database.
+func (r *ExchangeRatesMemory) Save(ctx context.Context, e *internal.ExchangeRate) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// check the rate is not effective from the same date already
+	for _, er := range r.db.exchangeRates {
+		if er.From == (*e).From && er.To == (*e).To && er.EffectiveDate == (*e).EffectiveDate {
+			return errExchangeRateExists(*e)
+		}
+	}
+
+	// set the id
+	r.db.lastExchangeRateId++
+	(*e).Id = r.db.lastExchangeRateId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityExchangeRates, (*e).Id, nil, *e)
+	if err != nil {
+		r.db.lastExchangeRateId--
+		return
+	}
+
+	// insert the exchange rate
+	r.db.exchangeRates[(*e).Id] = *e
+
+	return
+}
+
+// rate returns the rate converting from into to effective at datetime: the rate with the latest
+// effective date not after it, compared as text like sqlite does. The caller must hold the lock.
+func (db *MemoryDB) rate(from, to internal.Currency, datetime string) (rate internal.Rate, ok bool) {
+	if from == to {
+		return internal.MustParseRate("1"), true
+	}
+
+	var effective string
+	for _, er := range db.exchangeRates {
+		if er.From != from || er.To != to || er.EffectiveDate > datetime || er.EffectiveDate < effective {
+			continue
+		}
+		rate, effective = er.Rate, er.EffectiveDate
+	}
+	return rate, effective != ""
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	// InvoiceExchangeRate selects the rate converting the invoice i to the currency of the placeholder,
+	// the rate with the latest effective date not after the invoice datetime.
+	InvoiceExchangeRate = "(SELECT r.`rate` FROM exchange_rates AS r WHERE r.`from_currency` = i.`currency` AND r.`to_currency` = ? AND r.`effective_date` <= i.`datetime` ORDER BY r.`effective_date` DESC LIMIT 1)"
+	// ConvertedInvoiceTotal is the total of the invoice i converted to the currency of both placeholders.
+	ConvertedInvoiceTotal = "CASE WHEN i.`currency` = ? THEN i.`total` ELSE i.`total` * " + InvoiceExchangeRate + " END"
+	// ConvertedInvoiceSubtotal is the subtotal of the invoice i converted to the currency of both placeholders.
+	ConvertedInvoiceSubtotal = "CASE WHEN i.`currency` = ? THEN i.`subtotal` ELSE i.`subtotal` * " + InvoiceExchangeRate + " END"
+	// MissingExchangeRatesQuery selects the invoices that cannot be converted to the currency of both placeholders.
+	MissingExchangeRatesQuery = "SELECT i.`id`, i.`currency` FROM invoices AS i WHERE i.`currency` <> ? AND NOT EXISTS (SELECT 1 FROM exchange_rates AS r WHERE r.`from_currency` = i.`currency` AND r.`to_currency` = ? AND r.`effective_date` <= i.`datetime`) ORDER BY i.`id` LIMIT 1"
+	ExistsExchangeRateQuery   = "SELECT COUNT(*) FROM exchange_rates WHERE `from_currency` = ? AND `to_currency` = ? AND `effective_date` = ?"
+)
+
+// NewExchangeRatesMySQL creates new mysql repository for exchange rate entity.
+func NewExchangeRatesMySQL(db *sql.DB) *ExchangeRatesMySQL {
+	return &ExchangeRatesMySQL{db}
+}
+
+// ExchangeRatesMySQL is the MySQL repository implementation for exchange rate entity.
+type ExchangeRatesMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all exchange rates from the database.
+func (r *ExchangeRatesMySQL) FindAll(ctx context.Context) (e []internal.ExchangeRate, err error) {
+	defer observe(ctx, "exchange_rates.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, "SELECT `id`, `from_currency`, `to_currency`, `rate`, `effective_date` FROM exchange_rates ORDER BY `id`")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var er internal.ExchangeRate
+		// scan the row into the exchange rate
+		err := rows.Scan(&er.Id, &er.From, &er.To, &er.Rate, &er.EffectiveDate)
+		if err != nil {
+			return nil, err
+		}
+		// append the exchange rate to the slice
+		e = append(e, er)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the exchange rate into the database.
+func (r *ExchangeRatesMySQL) Save(ctx context.Context, e *internal.ExchangeRate) (err error) {
+	defer observe(ctx, "exchange_rates.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the rate is not effective from the same date already
+		var n int
+		err = tx.QueryRowContext(ctx, ExistsExchangeRateQuery, (*e).From, (*e).To, (*e).EffectiveDate).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return errExchangeRateExists(*e)
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO exchange_rates (`from_currency`, `to_currency`, `rate`, `effective_date`) VALUES (?, ?, ?, ?)",
+			(*e).From, (*e).To, (*e).Rate, (*e).EffectiveDate,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*e).Id = int(id)
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityExchangeRates, (*e).Id, nil, *e)
+	})
+	return
+}
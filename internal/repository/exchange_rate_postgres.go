@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	// InvoiceExchangeRatePostgres selects the rate converting the invoice i to the currency of the placeholder,
+	// the rate with the latest effective date not after the invoice datetime.
+	InvoiceExchangeRatePostgres = `(SELECT r."rate" FROM exchange_rates AS r WHERE r."from_currency" = i."currency" AND r."to_currency" = $1 AND r."effective_date" <= i."datetime" ORDER BY r."effective_date" DESC LIMIT 1)`
+	// ConvertedInvoiceTotalPostgres is the total of the invoice i converted to the currency of the placeholder.
+	ConvertedInvoiceTotalPostgres = `CASE WHEN i."currency" = $1 THEN i."total" ELSE i."total" * ` + InvoiceExchangeRatePostgres + ` END`
+	// ConvertedInvoiceSubtotalPostgres is the subtotal of the invoice i converted to the currency of the placeholder.
+	ConvertedInvoiceSubtotalPostgres = `CASE WHEN i."currency" = $1 THEN i."subtotal" ELSE i."subtotal" * ` + InvoiceExchangeRatePostgres + ` END`
+	// MissingExchangeRatesPostgresQuery selects the invoices that cannot be converted to the currency of the placeholder.
+	MissingExchangeRatesPostgresQuery = `SELECT i."id", i."currency" FROM invoices AS i WHERE i."currency" <> $1 AND NOT EXISTS (SELECT 1 FROM exchange_rates AS r WHERE r."from_currency" = i."currency" AND r."to_currency" = $1 AND r."effective_date" <= i."datetime") ORDER BY i."id" LIMIT 1`
+	ExistsExchangeRatePostgresQuery   = `SELECT COUNT(*) FROM exchange_rates WHERE "from_currency" = $1 AND "to_currency" = $2 AND "effective_date" = $3`
+)
+
+// NewExchangeRatesPostgres creates new postgres repository for exchange rate entity.
+func NewExchangeRatesPostgres(db *sql.DB) *ExchangeRatesPostgres {
+	return &ExchangeRatesPostgres{db}
+}
+
+// ExchangeRatesPostgres is the Postgres repository implementation for exchange rate entity.
+type ExchangeRatesPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all exchange rates from the database.
+func (r *ExchangeRatesPostgres) FindAll(ctx context.Context) (e []internal.ExchangeRate, err error) {
+	defer observe(ctx, "exchange_rates.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, `SELECT "id", "from_currency", "to_currency", "rate", to_char("effective_date", 'YYYY-MM-DD') FROM exchange_rates ORDER BY "id"`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var er internal.ExchangeRate
+		// scan the row into the exchange rate
+		err := rows.Scan(&er.Id, &er.From, &er.To, &er.Rate, &er.EffectiveDate)
+		if err != nil {
+			return nil, err
+		}
+		// append the exchange rate to the slice
+		e = append(e, er)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the exchange rate into the database.
+func (r *ExchangeRatesPostgres) Save(ctx context.Context, e *internal.ExchangeRate) (err error) {
+	defer observe(ctx, "exchange_rates.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the rate is not effective from the same date already
+		var n int
+		err = tx.QueryRowContext(ctx, ExistsExchangeRatePostgresQuery, (*e).From, (*e).To, (*e).EffectiveDate).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return errExchangeRateExists(*e)
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO exchange_rates ("from_currency", "to_currency", "rate", "effective_date") VALUES ($1, $2, $3, $4) RETURNING "id"`,
+			(*e).From, (*e).To, (*e).Rate, (*e).EffectiveDate,
+		).Scan(&(*e).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityExchangeRates, (*e).Id, nil, *e)
+	})
+	return
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"app/internal"
+)
+
+// invoiceTotalQueries are the queries of a sql database that recalculate the amounts of the invoices from their sales.
+// The arguments of each query are listed in the order of its placeholders.
+type invoiceTotalQueries struct {
+	// invoices selects the invoices, as queryInvoices expects them.
+	invoices string
+	// amounts sets the discount and the subtotal of every invoice from its sales.
+	amounts string
+	// saleTaxes selects the invoice id, quantity, unit price, discount and tax rate of every sale.
+	saleTaxes string
+	// tax sets the tax of an invoice: tax, id.
+	tax string
+	// total sets the total of every invoice from its subtotal and tax.
+	total string
+	// audit inserts an audit record, as writeAudit expects it.
+	audit string
+}
+
+// updateInvoicesTotalAudited recalculates the amounts of every invoice within tx, auditing every invoice whose amounts changed.
+// The tax is summed exactly and rounded half to even by queryInvoiceTaxes, as the sql ROUND rounds half away from zero.
+func updateInvoicesTotalAudited(ctx context.Context, tx *sql.Tx, iq invoiceTotalQueries) (err error) {
+	before, err := queryInvoices(ctx, tx, iq.invoices)
+	if err != nil {
+		return
+	}
+
+	// - discount and subtotal
+	_, err = tx.ExecContext(ctx, iq.amounts)
+	if err != nil {
+		return
+	}
+
+	// - tax, of the invoices whose tax changed
+	taxes, err := queryInvoiceTaxes(ctx, tx, iq.saleTaxes)
+	if err != nil {
+		return
+	}
+	for _, id := range sortedKeys(before) {
+		if taxes[id] == before[id].Tax {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, iq.tax, taxes[id], id)
+		if err != nil {
+			return
+		}
+	}
+
+	// - total
+	_, err = tx.ExecContext(ctx, iq.total)
+	if err != nil {
+		return
+	}
+
+	after, err := queryInvoices(ctx, tx, iq.invoices)
+	if err != nil {
+		return
+	}
+
+	for _, id := range sortedKeys(after) {
+		if before[id] == after[id] {
+			continue
+		}
+		err = writeAudit(ctx, tx, iq.audit, AuditEntityInvoices, id, before[id], after[id])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// queryInvoiceTaxes returns the tax of the invoices with sales, by id: the sum of the quantity * unit price
+// net of the discount of their sales at their tax rate, rounded to the cent half to even as the memory repository does.
+func queryInvoiceTaxes(ctx context.Context, q querier, query string) (taxes map[int]internal.Money, err error) {
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	sums := make(map[int]*internal.Converted)
+	for rows.Next() {
+		var (
+			invoiceId int
+			quantity  int
+			unitPrice internal.Money
+			discount  internal.Money
+			taxRate   internal.Rate
+		)
+		err = rows.Scan(&invoiceId, &quantity, &unitPrice, &discount, &taxRate)
+		if err != nil {
+			return
+		}
+		if sums[invoiceId] == nil {
+			sums[invoiceId] = new(internal.Converted)
+		}
+		sums[invoiceId].Add(unitPrice.Mul(quantity)-discount, taxRate)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	taxes = make(map[int]internal.Money, len(sums))
+	for id, sum := range sums {
+		taxes[id] = sum.Money()
+	}
+	return
+}
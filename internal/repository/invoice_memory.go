@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewInvoicesMemory creates new memory repository for invoice entity.
+func NewInvoicesMemory(db *MemoryDB) *InvoicesMemory {
+	return &InvoicesMemory{db}
+}
+
+// InvoicesMemory is the memory repository implementation for invoice entity.
+type InvoicesMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all invoices from the database.
+func (r *InvoicesMemory) FindAll(ctx context.Context) (i []internal.Invoice, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, id := range sortedKeys(r.db.invoices) {
+		i = append(i, r.db.invoices[id])
+	}
+
+	return
+}
+
+// Save saves the invoice into the database.
+func (r *InvoicesMemory) Save(ctx context.Context, i *internal.Invoice) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// check the customer exists
+	if _, ok := r.db.customers[(*i).CustomerId]; !ok {
+		return ErrForeignKeyViolation
+	}
+
+	// default the currency and the subtotal, as the columns do
+	defaultInvoice(i)
+
+	// set the id
+	r.db.lastInvoiceId++
+	(*i).Id = r.db.lastInvoiceId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityInvoices, (*i).Id, nil, *i)
+	if err != nil {
+		r.db.lastInvoiceId--
+		return
+	}
+
+	// insert the invoice
+	r.db.invoices[(*i).Id] = *i
+
+	return
+}
+
+// UpdateInvoicesTotal recalculates the discount, subtotal, tax and total of every invoice from its sales,
+// auditing the invoices whose amounts changed.
+func (r *InvoicesMemory) UpdateInvoicesTotal(ctx context.Context) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// sum the discounts, quantity * unit price net of the discount, and its tax, per invoice
+	discounts := make(map[int]internal.Money)
+	subtotals := make(map[int]internal.Money)
+	taxes := make(map[int]*internal.Converted)
+	for _, sa := range r.db.sales {
+		net := sa.UnitPrice.Mul(sa.Quantity) - sa.Discount
+		discounts[sa.InvoiceId] += sa.Discount
+		subtotals[sa.InvoiceId] += net
+		if taxes[sa.InvoiceId] == nil {
+			taxes[sa.InvoiceId] = new(internal.Converted)
+		}
+		taxes[sa.InvoiceId].Add(net, sa.TaxRate)
+	}
+
+	// update and audit the invoices whose amounts changed
+	for _, id := range sortedKeys(r.db.invoices) {
+		before := r.db.invoices[id]
+		after := before
+		after.Discount = discounts[id]
+		after.Subtotal = subtotals[id]
+		after.Tax = 0
+		if taxes[id] != nil {
+			after.Tax = taxes[id].Money()
+		}
+		after.Total = after.Subtotal + after.Tax
+		if after == before {
+			continue
+		}
+
+		if err := r.db.audit(ctx, AuditEntityInvoices, id, before, after); err != nil {
+			return err
+		}
+		r.db.invoices[id] = after
+	}
+
+	return nil
+}
+
+// GetInvoicesTotalByCustomerCondition returns the invoiced total grouped by customer condition, converted to currency.
+func (r *InvoicesMemory) GetInvoicesTotalByCustomerCondition(ctx context.Context, currency internal.Currency) ([]internal.InvoiceTotalByCustomerCondition, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	// group by condition, keeping the order in which each condition is first found
+	var conditions []int
+	nets := make(map[int]*internal.Converted)
+	totals := make(map[int]*internal.Converted)
+	for _, id := range sortedKeys(r.db.invoices) {
+		iv := r.db.invoices[id]
+		cs, ok := r.db.customers[iv.CustomerId]
+		if !ok {
+			continue
+		}
+
+		rate, ok := r.db.rate(iv.Currency, currency, iv.Datetime)
+		if !ok {
+			return nil, errExchangeRateNotFound(iv.Id, iv.Currency, currency)
+		}
+		condition := int(cs.Condition)
+		if totals[condition] == nil {
+			conditions = append(conditions, condition)
+			nets[condition] = new(internal.Converted)
+			totals[condition] = new(internal.Converted)
+		}
+		nets[condition].Add(iv.Subtotal, rate)
+		totals[condition].Add(iv.Total, rate)
+	}
+
+	invoicesTotalByCustomerCondition := make([]internal.InvoiceTotalByCustomerCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		invoicesTotalByCustomerCondition = append(invoicesTotalByCustomerCondition, internal.InvoiceTotalByCustomerCondition{
+			Condition: condition,
+			Net:       nets[condition].Money(),
+			Total:     totals[condition].Money(),
+			Currency:  currency,
+		})
+	}
+
+	return invoicesTotalByCustomerCondition, nil
+}
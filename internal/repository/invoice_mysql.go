@@ -1,16 +1,32 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"app/internal"
 )
 
 const (
-	UpdateInvoicesTotalQuery                 = "UPDATE invoices AS i SET i.`total` = (SELECT SUM(s.`quantity` * p.`price`) FROM sales AS s INNER JOIN products AS p ON s.`product_id` = p.`id` WHERE i.`id` = s.`invoice_id`)"
-	GetInvoicesTotalByCustomerConditionQuery = "SELECT c.`condition`, SUM(i.`total`) FROM (customers as c INNER JOIN invoices as i ON c.`id` = i.`customer_id`) GROUP BY c.`condition`"
+	SelectInvoicesQuery                      = "SELECT `id`, `datetime`, `discount`, `subtotal`, `tax`, `total`, `customer_id`, `currency` FROM invoices"
+	UpdateInvoicesAmountsQuery               = "UPDATE invoices AS i SET i.`discount` = COALESCE((SELECT SUM(s.`discount`) FROM sales AS s WHERE i.`id` = s.`invoice_id`), 0), i.`subtotal` = COALESCE((SELECT SUM(s.`quantity` * s.`unit_price` - s.`discount`) FROM sales AS s WHERE i.`id` = s.`invoice_id`), 0)"
+	SelectSaleTaxesQuery                     = "SELECT `invoice_id`, `quantity`, `unit_price`, `discount`, `tax_rate` FROM sales"
+	UpdateInvoiceTaxQuery                    = "UPDATE invoices SET `tax` = ? WHERE `id` = ?"
+	UpdateInvoicesTotalQuery                 = "UPDATE invoices SET `total` = `subtotal` + `tax`"
+	GetInvoicesTotalByCustomerConditionQuery = "SELECT c.`condition`, SUM(" + ConvertedInvoiceSubtotal + "), SUM(" + ConvertedInvoiceTotal + ") FROM (customers as c INNER JOIN invoices as i ON c.`id` = i.`customer_id`) GROUP BY c.`condition`"
 )
 
+// invoiceTotalMySQLQueries are the queries that recalculate the amounts of the invoices from their sales.
+var invoiceTotalMySQLQueries = invoiceTotalQueries{
+	invoices:  SelectInvoicesQuery,
+	amounts:   UpdateInvoicesAmountsQuery,
+	saleTaxes: SelectSaleTaxesQuery,
+	tax:       UpdateInvoiceTaxQuery,
+	total:     UpdateInvoicesTotalQuery,
+	audit:     InsertAuditRecordQuery,
+}
+
 // NewInvoicesMySQL creates new mysql repository for invoice entity.
 func NewInvoicesMySQL(db *sql.DB) *InvoicesMySQL {
 	return &InvoicesMySQL{db}
@@ -23,9 +39,11 @@ type InvoicesMySQL struct {
 }
 
 // FindAll returns all invoices from the database.
-func (r *InvoicesMySQL) FindAll() (i []internal.Invoice, err error) {
+func (r *InvoicesMySQL) FindAll(ctx context.Context) (i []internal.Invoice, err error) {
+	defer observe(ctx, "invoices.FindAll", time.Now(), &err)
+
 	// execute the query
-	rows, err := r.db.Query("SELECT `id`, `datetime`, `total`, `customer_id` FROM invoices")
+	rows, err := r.db.QueryContext(ctx, SelectInvoicesQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +53,7 @@ func (r *InvoicesMySQL) FindAll() (i []internal.Invoice, err error) {
 	for rows.Next() {
 		var iv internal.Invoice
 		// scan the row into the invoice
-		err := rows.Scan(&iv.Id, &iv.Datetime, &iv.Total, &iv.CustomerId)
+		err := rows.Scan(&iv.Id, &iv.Datetime, &iv.Discount, &iv.Subtotal, &iv.Tax, &iv.Total, &iv.CustomerId, &iv.Currency)
 		if err != nil {
 			return nil, err
 		}
@@ -51,51 +69,77 @@ func (r *InvoicesMySQL) FindAll() (i []internal.Invoice, err error) {
 }
 
 // Save saves the invoice into the database.
-func (r *InvoicesMySQL) Save(i *internal.Invoice) (err error) {
-	// execute the query
-	res, err := r.db.Exec(
-		"INSERT INTO invoices (`datetime`, `total`, `customer_id`) VALUES (?, ?, ?)",
-		(*i).Datetime, (*i).Total, (*i).CustomerId,
-	)
-	if err != nil {
-		return err
-	}
+func (r *InvoicesMySQL) Save(ctx context.Context, i *internal.Invoice) (err error) {
+	defer observe(ctx, "invoices.Save", time.Now(), &err)
+
+	// default the currency and the subtotal, as the columns do
+	defaultInvoice(i)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO invoices (`datetime`, `discount`, `subtotal`, `tax`, `total`, `customer_id`, `currency`) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			(*i).Datetime, (*i).Discount, (*i).Subtotal, (*i).Tax, (*i).Total, (*i).CustomerId, (*i).Currency,
+		)
+		if err != nil {
+			return err
+		}
 
-	// get the last inserted id
-	id, err := res.LastInsertId()
-	if err != nil {
-		return err
-	}
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
 
-	// set the id
-	(*i).Id = int(id)
+		// set the id
+		(*i).Id = int(id)
 
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityInvoices, (*i).Id, nil, *i)
+	})
 	return
 }
 
-func (r *InvoicesMySQL) UpdateInvoicesTotal() error {
-	_, err := r.db.Exec(UpdateInvoicesTotalQuery)
+// UpdateInvoicesTotal recalculates the total of every invoice from its sales,
+// auditing the invoices whose total changed in the same transaction.
+func (r *InvoicesMySQL) UpdateInvoicesTotal(ctx context.Context) (err error) {
+	defer observe(ctx, "invoices.UpdateInvoicesTotal", time.Now(), &err)
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateInvoicesTotalAudited(ctx, tx, invoiceTotalMySQLQueries)
+	})
+	return
+}
+
+// GetInvoicesTotalByCustomerCondition returns the invoiced total grouped by customer condition, converted to currency.
+func (r *InvoicesMySQL) GetInvoicesTotalByCustomerCondition(ctx context.Context, currency internal.Currency) (invoicesTotalByCustomerCondition []internal.InvoiceTotalByCustomerCondition, err error) {
+	defer observe(ctx, "invoices.GetInvoicesTotalByCustomerCondition", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, r.db, MissingExchangeRatesQuery, currency, currency, currency)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
-}
 
-func (r *InvoicesMySQL) GetInvoicesTotalByCustomerCondition() ([]internal.InvoiceTotalByCustomerCondition, error) {
-	rows, err := r.db.Query(GetInvoicesTotalByCustomerConditionQuery)
+	rows, err := r.db.QueryContext(ctx, GetInvoicesTotalByCustomerConditionQuery, currency, currency, currency, currency)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	invoicesTotalByCustomerCondition := make([]internal.InvoiceTotalByCustomerCondition, 0)
+	invoicesTotalByCustomerCondition = make([]internal.InvoiceTotalByCustomerCondition, 0)
 	for rows.Next() {
-		var invoiceTotalByCustomerCondition internal.InvoiceTotalByCustomerCondition
-		err := rows.Scan(&invoiceTotalByCustomerCondition.Condition, &invoiceTotalByCustomerCondition.Total)
+		invoiceTotalByCustomerCondition := internal.InvoiceTotalByCustomerCondition{Currency: currency}
+		err := rows.Scan(&invoiceTotalByCustomerCondition.Condition, &invoiceTotalByCustomerCondition.Net, &invoiceTotalByCustomerCondition.Total)
 		if err != nil {
 			return nil, err
 		}
 		invoicesTotalByCustomerCondition = append(invoicesTotalByCustomerCondition, invoiceTotalByCustomerCondition)
 	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
 
 	return invoicesTotalByCustomerCondition, nil
 }
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectInvoicesPostgresQuery                      = `SELECT "id", to_char("datetime", 'YYYY-MM-DD HH24:MI:SS'), "discount", "subtotal", "tax", "total", "customer_id", "currency" FROM invoices`
+	UpdateInvoicesAmountsPostgresQuery               = `UPDATE invoices AS i SET "discount" = COALESCE((SELECT SUM(s."discount") FROM sales AS s WHERE s."invoice_id" = i."id"), 0), "subtotal" = COALESCE((SELECT SUM(s."quantity" * s."unit_price" - s."discount") FROM sales AS s WHERE s."invoice_id" = i."id"), 0)`
+	SelectSaleTaxesPostgresQuery                     = `SELECT "invoice_id", "quantity", "unit_price", "discount", "tax_rate" FROM sales`
+	UpdateInvoiceTaxPostgresQuery                    = `UPDATE invoices SET "tax" = $1 WHERE "id" = $2`
+	UpdateInvoicesTotalPostgresQuery                 = `UPDATE invoices SET "total" = "subtotal" + "tax"`
+	GetInvoicesTotalByCustomerConditionPostgresQuery = `SELECT c."condition", SUM(` + ConvertedInvoiceSubtotalPostgres + `), SUM(` + ConvertedInvoiceTotalPostgres + `) FROM (customers as c INNER JOIN invoices as i ON c."id" = i."customer_id") GROUP BY c."condition"`
+)
+
+// invoiceTotalPostgresQueries are the queries that recalculate the amounts of the invoices from their sales.
+var invoiceTotalPostgresQueries = invoiceTotalQueries{
+	invoices:  SelectInvoicesPostgresQuery,
+	amounts:   UpdateInvoicesAmountsPostgresQuery,
+	saleTaxes: SelectSaleTaxesPostgresQuery,
+	tax:       UpdateInvoiceTaxPostgresQuery,
+	total:     UpdateInvoicesTotalPostgresQuery,
+	audit:     InsertAuditRecordPostgresQuery,
+}
+
+// NewInvoicesPostgres creates new postgres repository for invoice entity.
+func NewInvoicesPostgres(db *sql.DB) *InvoicesPostgres {
+	return &InvoicesPostgres{db}
+}
+
+// InvoicesPostgres is the Postgres repository implementation for invoice entity.
+type InvoicesPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all invoices from the database.
+func (r *InvoicesPostgres) FindAll(ctx context.Context) (i []internal.Invoice, err error) {
+	defer observe(ctx, "invoices.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, SelectInvoicesPostgresQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var iv internal.Invoice
+		// scan the row into the invoice
+		err := rows.Scan(&iv.Id, &iv.Datetime, &iv.Discount, &iv.Subtotal, &iv.Tax, &iv.Total, &iv.CustomerId, &iv.Currency)
+		if err != nil {
+			return nil, err
+		}
+		// append the invoice to the slice
+		i = append(i, iv)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the invoice into the database.
+func (r *InvoicesPostgres) Save(ctx context.Context, i *internal.Invoice) (err error) {
+	defer observe(ctx, "invoices.Save", time.Now(), &err)
+
+	// default the currency and the subtotal, as the columns do
+	defaultInvoice(i)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO invoices ("datetime", "discount", "subtotal", "tax", "total", "customer_id", "currency") VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING "id"`,
+			(*i).Datetime, (*i).Discount, (*i).Subtotal, (*i).Tax, (*i).Total, (*i).CustomerId, (*i).Currency,
+		).Scan(&(*i).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityInvoices, (*i).Id, nil, *i)
+	})
+	return
+}
+
+// UpdateInvoicesTotal recalculates the total of every invoice from its sales,
+// auditing the invoices whose total changed in the same transaction.
+func (r *InvoicesPostgres) UpdateInvoicesTotal(ctx context.Context) (err error) {
+	defer observe(ctx, "invoices.UpdateInvoicesTotal", time.Now(), &err)
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateInvoicesTotalAudited(ctx, tx, invoiceTotalPostgresQueries)
+	})
+	return
+}
+
+// GetInvoicesTotalByCustomerCondition returns the invoiced total grouped by customer condition, converted to currency.
+func (r *InvoicesPostgres) GetInvoicesTotalByCustomerCondition(ctx context.Context, currency internal.Currency) (invoicesTotalByCustomerCondition []internal.InvoiceTotalByCustomerCondition, err error) {
+	defer observe(ctx, "invoices.GetInvoicesTotalByCustomerCondition", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, r.db, MissingExchangeRatesPostgresQuery, currency, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, GetInvoicesTotalByCustomerConditionPostgresQuery, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoicesTotalByCustomerCondition = make([]internal.InvoiceTotalByCustomerCondition, 0)
+	for rows.Next() {
+		invoiceTotalByCustomerCondition := internal.InvoiceTotalByCustomerCondition{Currency: currency}
+		err := rows.Scan(&invoiceTotalByCustomerCondition.Condition, &invoiceTotalByCustomerCondition.Net, &invoiceTotalByCustomerCondition.Total)
+		if err != nil {
+			return nil, err
+		}
+		invoicesTotalByCustomerCondition = append(invoicesTotalByCustomerCondition, invoiceTotalByCustomerCondition)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return invoicesTotalByCustomerCondition, nil
+}
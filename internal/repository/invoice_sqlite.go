@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectInvoicesSQLiteQuery                      = `SELECT "id", "datetime", "discount", "subtotal", "tax", "total", "customer_id", "currency" FROM invoices`
+	UpdateInvoicesAmountsSQLiteQuery               = `UPDATE invoices SET "discount" = COALESCE(ROUND((SELECT SUM(s."discount") FROM sales AS s WHERE s."invoice_id" = invoices."id"), 2), 0), "subtotal" = COALESCE(ROUND((SELECT SUM(s."quantity" * s."unit_price" - s."discount") FROM sales AS s WHERE s."invoice_id" = invoices."id"), 2), 0)`
+	SelectSaleTaxesSQLiteQuery                     = `SELECT "invoice_id", "quantity", "unit_price", "discount", "tax_rate" FROM sales`
+	UpdateInvoiceTaxSQLiteQuery                    = `UPDATE invoices SET "tax" = ? WHERE "id" = ?`
+	UpdateInvoicesTotalSQLiteQuery                 = `UPDATE invoices SET "total" = "subtotal" + "tax"`
+	GetInvoicesTotalByCustomerConditionSQLiteQuery = `SELECT c."condition", SUM(` + ConvertedInvoiceSubtotalSQLite + `), SUM(` + ConvertedInvoiceTotalSQLite + `) FROM (customers as c INNER JOIN invoices as i ON c."id" = i."customer_id") GROUP BY c."condition"`
+)
+
+// invoiceTotalSQLiteQueries are the queries that recalculate the amounts of the invoices from their sales.
+var invoiceTotalSQLiteQueries = invoiceTotalQueries{
+	invoices:  SelectInvoicesSQLiteQuery,
+	amounts:   UpdateInvoicesAmountsSQLiteQuery,
+	saleTaxes: SelectSaleTaxesSQLiteQuery,
+	tax:       UpdateInvoiceTaxSQLiteQuery,
+	total:     UpdateInvoicesTotalSQLiteQuery,
+	audit:     InsertAuditRecordSQLiteQuery,
+}
+
+// NewInvoicesSQLite creates new sqlite repository for invoice entity.
+func NewInvoicesSQLite(db *sql.DB) *InvoicesSQLite {
+	return &InvoicesSQLite{db}
+}
+
+// InvoicesSQLite is the SQLite repository implementation for invoice entity.
+type InvoicesSQLite struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all invoices from the database.
+func (r *InvoicesSQLite) FindAll(ctx context.Context) (i []internal.Invoice, err error) {
+	defer observe(ctx, "invoices.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, SelectInvoicesSQLiteQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var iv internal.Invoice
+		// scan the row into the invoice
+		err := rows.Scan(&iv.Id, &iv.Datetime, &iv.Discount, &iv.Subtotal, &iv.Tax, &iv.Total, &iv.CustomerId, &iv.Currency)
+		if err != nil {
+			return nil, err
+		}
+		// append the invoice to the slice
+		i = append(i, iv)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the invoice into the database.
+func (r *InvoicesSQLite) Save(ctx context.Context, i *internal.Invoice) (err error) {
+	defer observe(ctx, "invoices.Save", time.Now(), &err)
+
+	// default the currency and the subtotal, as the columns do
+	defaultInvoice(i)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO invoices ("datetime", "discount", "subtotal", "tax", "total", "customer_id", "currency") VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			(*i).Datetime, (*i).Discount, (*i).Subtotal, (*i).Tax, (*i).Total, (*i).CustomerId, (*i).Currency,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*i).Id = int(id)
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordSQLiteQuery, AuditEntityInvoices, (*i).Id, nil, *i)
+	})
+	return
+}
+
+// UpdateInvoicesTotal recalculates the total of every invoice from its sales,
+// auditing the invoices whose total changed in the same transaction.
+func (r *InvoicesSQLite) UpdateInvoicesTotal(ctx context.Context) (err error) {
+	defer observe(ctx, "invoices.UpdateInvoicesTotal", time.Now(), &err)
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateInvoicesTotalAudited(ctx, tx, invoiceTotalSQLiteQueries)
+	})
+	return
+}
+
+// GetInvoicesTotalByCustomerCondition returns the invoiced total grouped by customer condition, converted to currency.
+func (r *InvoicesSQLite) GetInvoicesTotalByCustomerCondition(ctx context.Context, currency internal.Currency) (invoicesTotalByCustomerCondition []internal.InvoiceTotalByCustomerCondition, err error) {
+	defer observe(ctx, "invoices.GetInvoicesTotalByCustomerCondition", time.Now(), &err)
+
+	err = checkExchangeRates(ctx, r.db, MissingExchangeRatesSQLiteQuery, currency, currency, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, GetInvoicesTotalByCustomerConditionSQLiteQuery, currency, currency, currency, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoicesTotalByCustomerCondition = make([]internal.InvoiceTotalByCustomerCondition, 0)
+	for rows.Next() {
+		invoiceTotalByCustomerCondition := internal.InvoiceTotalByCustomerCondition{Currency: currency}
+		err := rows.Scan(&invoiceTotalByCustomerCondition.Condition, &invoiceTotalByCustomerCondition.Net, &invoiceTotalByCustomerCondition.Total)
+		if err != nil {
+			return nil, err
+		}
+		invoicesTotalByCustomerCondition = append(invoicesTotalByCustomerCondition, invoiceTotalByCustomerCondition)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return invoicesTotalByCustomerCondition, nil
+}
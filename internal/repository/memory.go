@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"app/internal"
+)
+
+var (
+	// ErrForeignKeyViolation is used when a record references another record that does not exist.
+	ErrForeignKeyViolation = errors.New("foreign key constraint fails")
+)
+
+// NewMemoryDB creates a new empty in-memory database.
+func NewMemoryDB() *MemoryDB {
+	return &MemoryDB{
+		customers:      make(map[int]internal.Customer),
+		products:       make(map[int]internal.Product),
+		invoices:       make(map[int]internal.Invoice),
+		sales:          make(map[int]internal.Sale),
+		exchangeRates:  make(map[int]internal.ExchangeRate),
+		productPrices:  make(map[int]internal.ProductPrice),
+		taxRates:       make(map[int]internal.TaxRate),
+		promotions:     make(map[int]internal.Promotion),
+		stocks:         make(map[stockKey]int),
+		stockMovements: make(map[int]internal.StockMovement),
+		stockAlerts:    make(map[int]internal.StockAlert),
+		webhooks:       make(map[int]internal.WebhookDelivery),
+		categories:     make(map[int]internal.Category),
+	}
+}
+
+// MemoryDB is the storage shared by the memory repositories.
+// It plays the role of *sql.DB so that relations between entities can be resolved.
+type MemoryDB struct {
+	// mu guards every table.
+	mu sync.RWMutex
+	// customers is the customers table.
+	customers map[int]internal.Customer
+	// products is the products table.
+	products map[int]internal.Product
+	// invoices is the invoices table.
+	invoices map[int]internal.Invoice
+	// sales is the sales table.
+	sales map[int]internal.Sale
+	// lastCustomerId is the auto increment of the customers table.
+	lastCustomerId int
+	// lastProductId is the auto increment of the products table.
+	lastProductId int
+	// lastInvoiceId is the auto increment of the invoices table.
+	lastInvoiceId int
+	// auditRecords is the audit table, in insertion order.
+	auditRecords []internal.AuditRecord
+	// lastSaleId is the auto increment of the sales table.
+	lastSaleId int
+	// lastAuditId is the auto increment of the audit table.
+	lastAuditId int
+	// exchangeRates is the exchange rates table.
+	exchangeRates map[int]internal.ExchangeRate
+	// lastExchangeRateId is the auto increment of the exchange rates table.
+	lastExchangeRateId int
+	// productPrices is the product prices table.
+	productPrices map[int]internal.ProductPrice
+	// lastProductPriceId is the auto increment of the product prices table.
+	lastProductPriceId int
+	// taxRates is the tax rates table.
+	taxRates map[int]internal.TaxRate
+	// lastTaxRateId is the auto increment of the tax rates table.
+	lastTaxRateId int
+	// promotions is the promotions table.
+	promotions map[int]internal.Promotion
+	// lastPromotionId is the auto increment of the promotions table.
+	lastPromotionId int
+	// stocks is the stocks table, the units of a product held in a warehouse.
+	stocks map[stockKey]int
+	// stockMovements is the stock movements table.
+	stockMovements map[int]internal.StockMovement
+	// lastStockMovementId is the auto increment of the stock movements table.
+	lastStockMovementId int
+	// stockAlerts is the stock alerts table.
+	stockAlerts map[int]internal.StockAlert
+	// lastStockAlertId is the auto increment of the stock alerts table.
+	lastStockAlertId int
+	// webhooks is the webhook deliveries table.
+	webhooks map[int]internal.WebhookDelivery
+	// lastWebhookId is the auto increment of the webhook deliveries table.
+	lastWebhookId int
+	// categories is the categories table.
+	categories map[int]internal.Category
+	// lastCategoryId is the auto increment of the categories table.
+	lastCategoryId int
+}
+
+// sortedKeys returns the keys of a table in ascending order, which is the insertion order.
+func sortedKeys[T any](table map[int]T) (keys []int) {
+	keys = make([]int, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return
+}
+
+// insertProductPrice inserts the price of p, valid from validFrom. The caller must hold the lock.
+func (db *MemoryDB) insertProductPrice(p internal.Product, validFrom string) {
+	db.lastProductPriceId++
+	db.productPrices[db.lastProductPriceId] = internal.ProductPrice{
+		Id:        db.lastProductPriceId,
+		ProductId: p.Id,
+		Price:     p.Price,
+		Currency:  p.Currency,
+		ValidFrom: validFrom,
+	}
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrSchemaVersionMismatch is used when the schema is not at the version of the last migration.
+var ErrSchemaVersionMismatch = errors.New("schema version mismatch")
+
+// Migration is a versioned change of the database schema.
+type Migration struct {
+	// Version is the version the schema is at once the migration is applied. Versions start at 1.
+	Version int
+	// Description is a short description of the change.
+	Description string
+	// Statements are the statements of the migration, executed in order.
+	Statements []string
+}
+
+const (
+	// CreateSchemaMigrationsQuery creates the table that keeps track of the applied migrations.
+	CreateSchemaMigrationsQuery = "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY)"
+	// GetSchemaVersionQuery returns the version of the last applied migration.
+	GetSchemaVersionQuery = "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"
+)
+
+// Migrate applies, in order, the migrations whose version is greater than the current schema version.
+// Each migration runs in its own transaction.
+func Migrate(db *sql.DB, migrations []Migration) (err error) {
+	// ensure the migrations table
+	_, err = db.Exec(CreateSchemaMigrationsQuery)
+	if err != nil {
+		return
+	}
+
+	// current version
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+
+		err = migrate(db, m)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, err)
+		}
+	}
+
+	return
+}
+
+// SchemaVersion returns the version of the last migration applied to db.
+func SchemaVersion(db *sql.DB) (version int, err error) {
+	err = db.QueryRow(GetSchemaVersionQuery).Scan(&version)
+	return
+}
+
+// CheckSchemaVersion checks that the schema of db is at the version of the last of migrations.
+func CheckSchemaVersion(ctx context.Context, db *sql.DB, migrations []Migration) (err error) {
+	var want int
+	if len(migrations) > 0 {
+		want = migrations[len(migrations)-1].Version
+	}
+
+	var version int
+	err = db.QueryRowContext(ctx, GetSchemaVersionQuery).Scan(&version)
+	if err != nil {
+		return
+	}
+
+	if version != want {
+		err = fmt.Errorf("%w: got %d, want %d", ErrSchemaVersionMismatch, version, want)
+	}
+	return
+}
+
+// migrate applies a single migration.
+func migrate(db *sql.DB, m Migration) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for _, stmt := range m.Statements {
+		_, err = tx.Exec(stmt)
+		if err != nil {
+			return
+		}
+	}
+
+	// the version is an int so it is safe to format it into the query, which keeps it placeholder agnostic
+	_, err = tx.Exec(fmt.Sprintf("INSERT INTO schema_migrations (version) VALUES (%d)", m.Version))
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
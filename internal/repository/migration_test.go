@@ -0,0 +1,109 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Migrate function
+func TestMigrate(t *testing.T) {
+	t.Run("applies pending migrations once", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, err := repository.NewSQLiteDB(path)
+		require.NoError(t, err)
+		defer db.Close()
+		migrations := append(repository.SQLiteMigrations, repository.Migration{
+			Version:     len(repository.SQLiteMigrations) + 1,
+			Description: "add a table",
+			Statements:  []string{"CREATE TABLE extra (id INTEGER)"},
+		})
+
+		// act
+		errFirst := repository.Migrate(db, migrations)
+		errSecond := repository.Migrate(db, migrations)
+		version, err := repository.SchemaVersion(db)
+
+		// assert
+		require.NoError(t, errFirst)
+		require.NoError(t, errSecond)
+		require.NoError(t, err)
+		require.Equal(t, len(migrations), version)
+	})
+
+	t.Run("failed migration is rolled back", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, err := repository.NewSQLiteDB(path)
+		require.NoError(t, err)
+		defer db.Close()
+		migrations := append(repository.SQLiteMigrations, repository.Migration{
+			Version:     len(repository.SQLiteMigrations) + 1,
+			Description: "broken",
+			Statements:  []string{"CREATE TABLE extra (id INTEGER)", "NOT SQL"},
+		})
+
+		// act
+		errMigrate := repository.Migrate(db, migrations)
+		version, err := repository.SchemaVersion(db)
+
+		// assert
+		require.Error(t, errMigrate)
+		require.NoError(t, err)
+		require.Equal(t, len(repository.SQLiteMigrations), version)
+		_, err = db.Exec("SELECT id FROM extra")
+		require.Error(t, err)
+	})
+
+	t.Run("schema version check", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, err := repository.NewSQLiteDB(path)
+		require.NoError(t, err)
+		defer db.Close()
+		ahead := append(repository.SQLiteMigrations, repository.Migration{Version: len(repository.SQLiteMigrations) + 1})
+
+		// act
+		errCurrent := repository.CheckSchemaVersion(context.Background(), db, repository.SQLiteMigrations)
+		errAhead := repository.CheckSchemaVersion(context.Background(), db, ahead)
+
+		// assert
+		require.NoError(t, errCurrent)
+		require.ErrorIs(t, errAhead, repository.ErrSchemaVersionMismatch)
+	})
+
+	t.Run("existing customers get a condition and the datetime of their first invoice", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, err := sql.Open("sqlite", path)
+		require.NoError(t, err)
+		defer db.Close()
+		before := repository.SQLiteMigrations[:10]
+		require.NoError(t, repository.Migrate(db, before))
+		_, err = db.Exec(`INSERT INTO customers ("first_name", "last_name", "condition") VALUES ('John', 'Doe', NULL), ('Jane', 'Doe', 1)`)
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO invoices ("datetime", "customer_id") VALUES ('2022-05-15 10:00:00', 1), ('2021-01-01 08:30:00', 1)`)
+		require.NoError(t, err)
+
+		// act
+		errMigrate := repository.Migrate(db, repository.SQLiteMigrations)
+		c, err := repository.NewCustomersSQLite(db).FindAll(context.Background())
+
+		// assert
+		require.NoError(t, errMigrate)
+		require.NoError(t, err)
+		require.Len(t, c, 2)
+		require.Equal(t, internal.CustomerConditionInactive, c[0].Condition)
+		require.Equal(t, "2021-01-01 08:30:00", c[0].CreatedAt)
+		require.Equal(t, c[0].CreatedAt, c[0].UpdatedAt)
+		require.Empty(t, c[0].Email)
+		require.Equal(t, internal.CustomerConditionActive, c[1].Condition)
+		require.NotEmpty(t, c[1].CreatedAt)
+	})
+}
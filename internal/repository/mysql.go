@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+var (
+	// MySQLMigrations are the migrations of the mysql schema.
+	// MySQL commits each DDL statement implicitly, so a migration that fails halfway is not rolled back:
+	// its version is not recorded, and the statements already applied have to be reverted by hand.
+	MySQLMigrations = []Migration{
+		{
+			Version:     1,
+			Description: "create customers, invoices, products and sales",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS `customers` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`first_name` varchar(45) DEFAULT NULL, " +
+					"`last_name` varchar(45) DEFAULT NULL, " +
+					"`condition` tinyint(1) DEFAULT NULL, " +
+					"PRIMARY KEY (`id`))",
+				"CREATE TABLE IF NOT EXISTS `invoices` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`datetime` datetime DEFAULT NULL, " +
+					"`customer_id` int DEFAULT NULL, " +
+					"`total` float DEFAULT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_invoices_customer_id` (`customer_id`), " +
+					"CONSTRAINT `fk_invoices_customer_id` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"CREATE TABLE IF NOT EXISTS `products` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`description` varchar(100) DEFAULT NULL, " +
+					"`price` float DEFAULT NULL, " +
+					"PRIMARY KEY (`id`))",
+				"CREATE TABLE IF NOT EXISTS `sales` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`quantity` int DEFAULT NULL, " +
+					"`invoice_id` int DEFAULT NULL, " +
+					"`product_id` int DEFAULT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_sales_invoice_id` (`invoice_id`), " +
+					"KEY `idx_sales_product_id` (`product_id`), " +
+					"CONSTRAINT `fk_sales_invoice_id` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`) ON DELETE CASCADE ON UPDATE CASCADE, " +
+					"CONSTRAINT `fk_sales_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+			},
+		},
+		{
+			Version:     2,
+			Description: "create audit_records",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS `audit_records` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`actor` varchar(100) NOT NULL, " +
+					"`timestamp` datetime(6) NOT NULL, " +
+					"`entity` varchar(45) NOT NULL, " +
+					"`entity_id` int NOT NULL, " +
+					"`action` varchar(10) NOT NULL, " +
+					"`before` text DEFAULT NULL, " +
+					"`after` text DEFAULT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_audit_records_entity` (`entity`, `entity_id`), " +
+					"KEY `idx_audit_records_timestamp` (`timestamp`))",
+			},
+		},
+		{
+			Version:     3,
+			Description: "store amounts of money as DECIMAL(12,2)",
+			Statements: []string{
+				"ALTER TABLE `invoices` MODIFY `total` decimal(12,2) DEFAULT NULL",
+				"ALTER TABLE `products` MODIFY `price` decimal(12,2) DEFAULT NULL",
+			},
+		},
+		{
+			Version:     4,
+			Description: "add the currency of products and invoices and create exchange_rates",
+			Statements: []string{
+				"ALTER TABLE `products` ADD COLUMN `currency` char(3) NOT NULL DEFAULT 'USD'",
+				"ALTER TABLE `invoices` ADD COLUMN `currency` char(3) NOT NULL DEFAULT 'USD'",
+				"CREATE TABLE IF NOT EXISTS `exchange_rates` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`from_currency` char(3) NOT NULL, " +
+					"`to_currency` char(3) NOT NULL, " +
+					"`rate` decimal(18,6) NOT NULL, " +
+					"`effective_date` date NOT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"UNIQUE KEY `uq_exchange_rates_pair_date` (`from_currency`, `to_currency`, `effective_date`))",
+			},
+		},
+		{
+			// the prices of the existing products are valid from the migration, and the existing sales at their current price
+			Version:     5,
+			Description: "create product_prices and store the unit price of sales",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS `product_prices` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`product_id` int NOT NULL, " +
+					"`price` decimal(12,2) DEFAULT NULL, " +
+					"`currency` char(3) NOT NULL DEFAULT 'USD', " +
+					"`valid_from` datetime NOT NULL, " +
+					"`valid_to` datetime DEFAULT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_product_prices_product_id` (`product_id`), " +
+					"CONSTRAINT `fk_product_prices_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"INSERT INTO `product_prices` (`product_id`, `price`, `currency`, `valid_from`) SELECT `id`, `price`, `currency`, UTC_TIMESTAMP() FROM `products`",
+				"ALTER TABLE `sales` ADD COLUMN `unit_price` decimal(12,2) DEFAULT NULL",
+				"UPDATE `sales` AS s INNER JOIN `products` AS p ON s.`product_id` = p.`id` SET s.`unit_price` = p.`price`",
+			},
+		},
+		{
+			// the existing totals are kept as untaxed subtotals, and the existing sales as untaxed
+			Version:     6,
+			Description: "add tax categories, tax rates and the taxes of sales and invoices",
+			Statements: []string{
+				"ALTER TABLE `products` ADD COLUMN `tax_category` varchar(45) NOT NULL DEFAULT 'standard'",
+				"CREATE TABLE IF NOT EXISTS `tax_rates` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`category` varchar(45) NOT NULL DEFAULT '', " +
+					"`customer_condition` tinyint DEFAULT NULL, " +
+					"`rate` decimal(18,6) NOT NULL, " +
+					"PRIMARY KEY (`id`))",
+				"ALTER TABLE `sales` ADD COLUMN `tax_rate` decimal(18,6) NOT NULL DEFAULT 0",
+				"ALTER TABLE `invoices` ADD COLUMN `subtotal` decimal(12,2) DEFAULT NULL, ADD COLUMN `tax` decimal(12,2) DEFAULT NULL",
+				"UPDATE `invoices` SET `subtotal` = `total`, `tax` = 0",
+			},
+		},
+		{
+			Version:     7,
+			Description: "create promotions and store the discounts of sales and invoices",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS `promotions` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`name` varchar(100) NOT NULL, " +
+					"`kind` varchar(20) NOT NULL, " +
+					"`product_id` int DEFAULT NULL, " +
+					"`customer_condition` tinyint DEFAULT NULL, " +
+					"`percent` decimal(18,6) NOT NULL DEFAULT 0, " +
+					"`buy_quantity` int NOT NULL DEFAULT 0, " +
+					"`get_quantity` int NOT NULL DEFAULT 0, " +
+					"`valid_from` date NOT NULL, " +
+					"`valid_to` date DEFAULT NULL, " +
+					"`stackable` boolean NOT NULL DEFAULT FALSE, " +
+					"PRIMARY KEY (`id`), " +
+					"CONSTRAINT `fk_promotions_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"CREATE TABLE IF NOT EXISTS `sale_discounts` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`sale_id` int NOT NULL, " +
+					"`promotion_id` int NOT NULL, " +
+					"`amount` decimal(12,2) NOT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_sale_discounts_sale_id` (`sale_id`), " +
+					"CONSTRAINT `fk_sale_discounts_sale_id` FOREIGN KEY (`sale_id`) REFERENCES `sales` (`id`) ON DELETE CASCADE ON UPDATE CASCADE, " +
+					"CONSTRAINT `fk_sale_discounts_promotion_id` FOREIGN KEY (`promotion_id`) REFERENCES `promotions` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"ALTER TABLE `sales` ADD COLUMN `discount` decimal(12,2) NOT NULL DEFAULT 0",
+				"ALTER TABLE `invoices` ADD COLUMN `discount` decimal(12,2) NOT NULL DEFAULT 0",
+			},
+		},
+		{
+			// the stock of the existing products is not tracked until their first receipt or adjustment
+			Version:     8,
+			Description: "create stocks and stock_movements and store the warehouse of sales",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS `stocks` (" +
+					"`product_id` int NOT NULL, " +
+					"`warehouse` varchar(45) NOT NULL, " +
+					"`quantity` int NOT NULL, " +
+					"PRIMARY KEY (`product_id`, `warehouse`), " +
+					"CONSTRAINT `chk_stocks_quantity` CHECK (`quantity` >= 0), " +
+					"CONSTRAINT `fk_stocks_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"CREATE TABLE IF NOT EXISTS `stock_movements` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`product_id` int NOT NULL, " +
+					"`warehouse` varchar(45) NOT NULL, " +
+					"`kind` varchar(20) NOT NULL, " +
+					"`quantity` int NOT NULL, " +
+					"`sale_id` int DEFAULT NULL, " +
+					"`datetime` datetime NOT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_stock_movements_product_id` (`product_id`), " +
+					"CONSTRAINT `fk_stock_movements_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE CASCADE, " +
+					"CONSTRAINT `fk_stock_movements_sale_id` FOREIGN KEY (`sale_id`) REFERENCES `sales` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"ALTER TABLE `sales` ADD COLUMN `warehouse` varchar(45) NOT NULL DEFAULT 'main'",
+			},
+		},
+		{
+			Version:     9,
+			Description: "create stock_alerts and webhook_deliveries",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS `stock_alerts` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`date` date NOT NULL, " +
+					"`product_id` int NOT NULL, " +
+					"`stock` int NOT NULL, " +
+					"`sold` int NOT NULL, " +
+					"`daily_sales` decimal(12,2) NOT NULL, " +
+					"`days_of_cover` decimal(12,2) NOT NULL, " +
+					"`quantity` int NOT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"UNIQUE KEY `uq_stock_alerts_product_id_date` (`product_id`, `date`), " +
+					"CONSTRAINT `fk_stock_alerts_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"CREATE TABLE IF NOT EXISTS `webhook_deliveries` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`event` varchar(45) NOT NULL, " +
+					"`payload` text NOT NULL, " +
+					"`attempts` int NOT NULL DEFAULT 0, " +
+					"`delivered` boolean NOT NULL DEFAULT FALSE, " +
+					"`last_error` varchar(255) NOT NULL DEFAULT '', " +
+					"`created_at` datetime NOT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_webhook_deliveries_delivered` (`delivered`, `id`))",
+			},
+		},
+		{
+			// the existing products have no sku nor category, are sold by unit and are not archived
+			Version:     10,
+			Description: "create categories and store the sku, category, unit and archived flag of products",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS `categories` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`name` varchar(45) NOT NULL, " +
+					"`parent_id` int DEFAULT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"KEY `idx_categories_parent_id` (`parent_id`), " +
+					"CONSTRAINT `fk_categories_parent_id` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+				"ALTER TABLE `products` " +
+					"ADD COLUMN `sku` varchar(45) DEFAULT NULL, " +
+					"ADD COLUMN `category_id` int DEFAULT NULL, " +
+					"ADD COLUMN `unit` varchar(20) NOT NULL DEFAULT 'unit', " +
+					"ADD COLUMN `archived` boolean NOT NULL DEFAULT FALSE, " +
+					"ADD UNIQUE KEY `uq_products_sku` (`sku`), " +
+					"ADD KEY `idx_products_category_id` (`category_id`), " +
+					"ADD CONSTRAINT `fk_products_category_id` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`) ON DELETE SET NULL ON UPDATE CASCADE",
+			},
+		},
+		{
+			Version:     11,
+			Description: "add the full-text indexes of the customer and product searches",
+			Statements: []string{
+				"ALTER TABLE `customers` ADD FULLTEXT KEY `ft_customers_name` (`first_name`, `last_name`)",
+				"ALTER TABLE `products` ADD FULLTEXT KEY `ft_products_description` (`description`)",
+			},
+		},
+		{
+			// the existing customers without a condition are inactive, and were created and updated when first invoiced,
+			// or now if never
+			Version:     12,
+			Description: "store the email, phone, addresses and creation and update datetimes of customers",
+			Statements: []string{
+				"ALTER TABLE `customers` " +
+					"ADD COLUMN `email` varchar(100) DEFAULT NULL, " +
+					"ADD COLUMN `phone` varchar(20) NOT NULL DEFAULT '', " +
+					"ADD COLUMN `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+					"ADD COLUMN `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+					"ADD UNIQUE KEY `uq_customers_email` (`email`)",
+				"UPDATE `customers` SET `condition` = 0 WHERE `condition` IS NULL",
+				"UPDATE `customers` AS c " +
+					"INNER JOIN (SELECT `customer_id`, MIN(`datetime`) AS `first` FROM `invoices` GROUP BY `customer_id`) AS i ON c.`id` = i.`customer_id` " +
+					"SET c.`created_at` = i.`first`, c.`updated_at` = i.`first` " +
+					"WHERE i.`first` IS NOT NULL",
+				"ALTER TABLE `customers` " +
+					"MODIFY COLUMN `condition` tinyint(1) NOT NULL DEFAULT 1, " +
+					"ADD CONSTRAINT `chk_customers_condition` CHECK (`condition` IN (0, 1))",
+				"CREATE TABLE IF NOT EXISTS `customer_addresses` (" +
+					"`id` int NOT NULL AUTO_INCREMENT, " +
+					"`customer_id` int NOT NULL, " +
+					"`kind` varchar(20) NOT NULL, " +
+					"`line1` varchar(100) NOT NULL, " +
+					"`line2` varchar(100) NOT NULL DEFAULT '', " +
+					"`city` varchar(45) NOT NULL, " +
+					"`state` varchar(45) NOT NULL DEFAULT '', " +
+					"`postal_code` varchar(20) NOT NULL DEFAULT '', " +
+					"`country` char(2) NOT NULL, " +
+					"PRIMARY KEY (`id`), " +
+					"UNIQUE KEY `uq_customer_addresses_customer_id_kind` (`customer_id`, `kind`), " +
+					"CONSTRAINT `fk_customer_addresses_customer_id` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE CASCADE ON UPDATE CASCADE)",
+			},
+		},
+		{
+			// the root categories have no parent, so the names are unique by the parent or 0, a functional key part
+			// that needs mysql 8.0.13 or later
+			Version:     13,
+			Description: "make the names of the categories unique under their parent",
+			Statements: []string{
+				"ALTER TABLE `categories` ADD UNIQUE KEY `uq_categories_parent_id_name` ((COALESCE(`parent_id`, 0)), `name`)",
+			},
+		},
+		{
+			// the former update of the totals left the amounts of the invoices without sales NULL
+			Version:     14,
+			Description: "set to 0 the NULL amounts of the invoices without sales",
+			Statements: []string{
+				"UPDATE `invoices` SET `subtotal` = COALESCE(`subtotal`, 0), `tax` = COALESCE(`tax`, 0), `total` = COALESCE(`total`, 0) " +
+					"WHERE `subtotal` IS NULL OR `tax` IS NULL OR `total` IS NULL",
+			},
+		},
+	}
+)
+
+// NewMySQLDB opens the mysql database described by cfg and migrates its schema if needed.
+// The database itself must exist.
+func NewMySQLDB(cfg *mysql.Config) (db *sql.DB, err error) {
+	// open
+	db, err = sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return
+	}
+
+	// schema
+	err = Migrate(db, MySQLMigrations)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return
+}
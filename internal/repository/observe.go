@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"app/internal"
+	"app/platform/logger"
+	"app/platform/metrics"
+	"app/platform/trace"
+)
+
+// SlowQueryThreshold is the duration from which a query is logged as slow.
+var SlowQueryThreshold = 200 * time.Millisecond
+
+// queryDuration is the duration of the repository methods, by method and status.
+var queryDuration = metrics.Default.Histogram("repository_query_duration_seconds",
+	"Duration of the repository methods in seconds.", metrics.DefBuckets, "query", "status")
+
+// expectedErrors are the errors the repositories return on purpose, for the callers to handle:
+// the records not found, the conflicts and the rejected changes. They are not failures of the storage.
+var expectedErrors = []error{
+	internal.ErrCustomerNotFound,
+	internal.ErrProductNotFound,
+	internal.ErrCategoryNotFound,
+	internal.ErrExchangeRateNotFound,
+	internal.ErrCustomerEmailExists,
+	internal.ErrProductSkuExists,
+	internal.ErrCategoryExists,
+	internal.ErrExchangeRateExists,
+	internal.ErrTaxRateExists,
+	internal.ErrStockAlertExists,
+	internal.ErrProductArchived,
+	internal.ErrStockInsufficient,
+	internal.ErrCurrencyMismatch,
+	ErrForeignKeyViolation,
+}
+
+// expected returns whether err is one of expectedErrors.
+func expected(err error) bool {
+	for _, e := range expectedErrors {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// observe records the outcome of the repository method name, started at start.
+// The duration is recorded in the query duration metric, and as a span if ctx carries one.
+// Errors and slow queries are logged with the logger carried by ctx: the expected errors at debug level,
+// the other errors at error level.
+// It is meant to be deferred: defer observe(ctx, "customers.FindAll", time.Now(), &err).
+func observe(ctx context.Context, name string, start time.Time, err *error) {
+	elapsed := time.Since(start)
+	l := logger.FromContext(ctx)
+
+	status := "ok"
+	if *err != nil {
+		status = "error"
+	}
+	queryDuration.With(name, status).Observe(elapsed.Seconds())
+	trace.Record(ctx, "repository."+name, start, *err, trace.String("db.statement.name", name))
+
+	if *err != nil {
+		level := slog.LevelError
+		if expected(*err) {
+			level = slog.LevelDebug
+		}
+		l.LogAttrs(ctx, level, "query failed",
+			slog.String("query", name),
+			slog.Duration("duration", elapsed),
+			slog.String("error", (*err).Error()),
+		)
+		return
+	}
+
+	if elapsed >= SlowQueryThreshold {
+		l.LogAttrs(ctx, slog.LevelWarn, "slow query",
+			slog.String("query", name),
+			slog.Duration("duration", elapsed),
+		)
+	}
+}
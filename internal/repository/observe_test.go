@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/platform/logger"
+	"app/platform/trace"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the logging of the sql repositories
+func TestRepositoryLogging(t *testing.T) {
+	t.Run("query errors are logged with the context logger", func(t *testing.T) {
+		// arrange
+		db, err := repository.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec("DROP TABLE sales")
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		ctx := logger.WithContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)).With("request_id", "abc"))
+
+		// act
+		_, err = repository.NewSalesSQLite(db).FindAll(ctx)
+
+		// assert
+		require.Error(t, err)
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		require.Equal(t, "ERROR", record["level"])
+		require.Equal(t, "query failed", record["msg"])
+		require.Equal(t, "sales.FindAll", record["query"])
+		require.Equal(t, "abc", record["request_id"])
+		require.Contains(t, record["error"], "no such table")
+	})
+
+	t.Run("expected errors are logged at debug level", func(t *testing.T) {
+		// arrange
+		db, err := repository.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer db.Close()
+
+		var buf bytes.Buffer
+		ctx := logger.WithContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		c := internal.Customer{Id: 1, CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe", Condition: 1}}
+
+		// act
+		err = repository.NewCustomersSQLite(db).Update(ctx, &c)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrCustomerNotFound)
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		require.Equal(t, "DEBUG", record["level"])
+		require.Equal(t, "customers.Update", record["query"])
+	})
+
+	t.Run("queries are traced as children of the context span", func(t *testing.T) {
+		// arrange
+		db, err := repository.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer db.Close()
+		rec := trace.NewRecorder()
+		ctx, root := trace.NewTracer(rec).Start(context.Background(), "root")
+
+		// act
+		_, err = repository.NewCustomersSQLite(db).GetTopCustomers(ctx, internal.CurrencyDefault)
+		root.End(nil)
+
+		// assert
+		require.NoError(t, err)
+		spans := rec.Spans()
+		require.Len(t, spans, 2)
+		require.Equal(t, "repository.customers.GetTopCustomers", spans[0].Name)
+		require.Equal(t, root.SpanContext().SpanID, spans[0].Parent)
+		require.Equal(t, []trace.Attr{trace.String("db.statement.name", "customers.GetTopCustomers")}, spans[0].Attributes)
+	})
+}
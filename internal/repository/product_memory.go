@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"app/internal"
+)
+
+// NewProductsMemory creates new memory repository for product entity.
+func NewProductsMemory(db *MemoryDB) *ProductsMemory {
+	return &ProductsMemory{db}
+}
+
+// ProductsMemory is the memory repository implementation for product entity.
+type ProductsMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all products from the database.
+func (r *ProductsMemory) FindAll(ctx context.Context) (p []internal.Product, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, id := range sortedKeys(r.db.products) {
+		p = append(p, r.db.products[id])
+	}
+
+	return
+}
+
+// Save saves the product into the database.
+func (r *ProductsMemory) Save(ctx context.Context, p *internal.Product) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// default the currency, the tax category and the unit, as the columns do
+	defaultProduct(p)
+
+	// check the category exists and the sku is free
+	err = r.db.checkProductCatalog(*p)
+	if err != nil {
+		return
+	}
+
+	// set the id
+	r.db.lastProductId++
+	(*p).Id = r.db.lastProductId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityProducts, (*p).Id, nil, *p)
+	if err != nil {
+		r.db.lastProductId--
+		return
+	}
+
+	// insert the product and its price, and track its stock
+	r.db.products[(*p).Id] = *p
+	r.db.insertProductPrice(*p, priceDatetime(time.Now()))
+	if !internal.StockUntracked(ctx) {
+		r.db.stocks[stockKey{(*p).Id, internal.WarehouseDefault}] = 0
+	}
+
+	return
+}
+
+// FindPrices returns the price history of the product id, oldest first.
+func (r *ProductsMemory) FindPrices(ctx context.Context, id int) (pp []internal.ProductPrice, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, ok := r.db.products[id]; !ok {
+		return nil, errProductNotFound(id)
+	}
+
+	pp = []internal.ProductPrice{}
+	for _, ppId := range sortedKeys(r.db.productPrices) {
+		if pr := r.db.productPrices[ppId]; pr.ProductId == id {
+			pp = append(pp, pr)
+		}
+	}
+
+	return
+}
+
+// UpdatePrice changes the price of the product p.Id to p.Price, closing its current price in the history.
+func (r *ProductsMemory) UpdatePrice(ctx context.Context, p *internal.Product) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	before, ok := r.db.products[(*p).Id]
+	if !ok {
+		return errProductNotFound((*p).Id)
+	}
+	after := before
+	after.Price = (*p).Price
+	*p = after
+	if after.Price == before.Price {
+		return
+	}
+
+	// audit the change, under the same lock as the update
+	err = r.db.audit(ctx, AuditEntityProducts, after.Id, before, after)
+	if err != nil {
+		return
+	}
+
+	// update the product and its price history
+	r.db.products[after.Id] = after
+	now := priceDatetime(time.Now())
+	for ppId, pr := range r.db.productPrices {
+		if pr.ProductId == after.Id && pr.ValidTo == "" {
+			pr.ValidTo = now
+			r.db.productPrices[ppId] = pr
+		}
+	}
+	r.db.insertProductPrice(after, now)
+
+	return
+}
+
+// UpdateArchived archives the product p.Id, or restores it, as p.Archived says.
+func (r *ProductsMemory) UpdateArchived(ctx context.Context, p *internal.Product) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	before, ok := r.db.products[(*p).Id]
+	if !ok {
+		return errProductNotFound((*p).Id)
+	}
+	after := before
+	after.Archived = (*p).Archived
+	*p = after
+	if after.Archived == before.Archived {
+		return
+	}
+
+	// audit the change, under the same lock as the update
+	err = r.db.audit(ctx, AuditEntityProducts, after.Id, before, after)
+	if err != nil {
+		return
+	}
+
+	// update the product
+	r.db.products[after.Id] = after
+
+	return
+}
+
+// GetTopProducts returns the 5 products with the highest sold quantity.
+func (r *ProductsMemory) GetTopProducts(ctx context.Context) ([]internal.TopProduct, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	// group sales by product
+	sold := make(map[int]int)
+	for _, sa := range r.db.sales {
+		sold[sa.ProductId] += sa.Quantity
+	}
+
+	topProducts := make([]internal.TopProduct, 0, len(sold))
+	for id, total := range sold {
+		pr, ok := r.db.products[id]
+		if !ok {
+			continue
+		}
+		topProducts = append(topProducts, internal.TopProduct{
+			Id:          pr.Id,
+			Description: pr.Description,
+			Total:       total,
+		})
+	}
+
+	// order by sold desc, ties by id
+	sort.Slice(topProducts, func(i, j int) bool {
+		if topProducts[i].Total != topProducts[j].Total {
+			return topProducts[i].Total > topProducts[j].Total
+		}
+		return topProducts[i].Id < topProducts[j].Id
+	})
+	if len(topProducts) > 5 {
+		topProducts = topProducts[:5]
+	}
+
+	return topProducts, nil
+}
+
+// Search returns the page of q of the products matching its text, ranked by internal.MatchScore.
+func (r *ProductsMemory) Search(ctx context.Context, q internal.SearchQuery) (m internal.ProductMatches, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	p := make([]internal.Product, 0, len(r.db.products))
+	for _, id := range sortedKeys(r.db.products) {
+		p = append(p, r.db.products[id])
+	}
+	m = searchProducts(p, q)
+	return
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"app/internal"
 )
@@ -18,13 +21,54 @@ type ProductsMySQL struct {
 }
 
 const (
-	TopProductsQuery = "SELECT p.`id`, p.`description`, SUM(s.`quantity`) as sold FROM products as p INNER JOIN sales as s ON p.`id` = s.`product_id` GROUP BY p.`id` ORDER BY sold DESC LIMIT 5"
+	TopProductsQuery         = "SELECT p.`id`, p.`description`, SUM(s.`quantity`) as sold FROM products as p INNER JOIN sales as s ON p.`id` = s.`product_id` GROUP BY p.`id` ORDER BY sold DESC LIMIT 5"
+	ExistsProductQuery       = "SELECT COUNT(*) FROM products WHERE `id` = ?"
+	SelectProductQuery       = "SELECT `description`, `price`, `currency`, `tax_category`, COALESCE(`sku`, ''), COALESCE(`category_id`, 0), `unit`, `archived` FROM products WHERE `id` = ?"
+	UpdateProductPriceQuery  = "UPDATE products SET `price` = ? WHERE `id` = ?"
+	ArchiveProductQuery      = "UPDATE products SET `archived` = ? WHERE `id` = ?"
+	ExistsProductSkuQuery    = "SELECT COUNT(*) FROM products WHERE `sku` = ?"
+	CloseProductPriceQuery   = "UPDATE product_prices SET `valid_to` = ? WHERE `product_id` = ? AND `valid_to` IS NULL"
+	InsertProductPriceQuery  = "INSERT INTO product_prices (`product_id`, `price`, `currency`, `valid_from`) VALUES (?, ?, ?, ?)"
+	SelectProductPricesQuery = "SELECT `id`, `product_id`, `price`, `currency`, `valid_from`, `valid_to` FROM product_prices WHERE `product_id` = ? ORDER BY `id`"
 )
 
+const (
+	// SoundsLikeProductCondition is the condition that the term, its only placeholder, sounds like a word of the description.
+	SoundsLikeProductCondition = soundsLikeWordPrefix + "`description`" + soundsLikeWordSuffix
+	// SearchProductsQuery selects a page of the products matching the condition that replaces the second %s verb,
+	// scored by the expression that replaces the first.
+	SearchProductsQuery = "SELECT `id`, `description`, `price`, `currency`, `tax_category`, COALESCE(`sku`, ''), COALESCE(`category_id`, 0), `unit`, `archived`, %s AS score FROM products WHERE %s ORDER BY score DESC, `id` LIMIT ? OFFSET ?"
+	// CountProductsQuery counts the products matching the condition that replaces the %s verb.
+	CountProductsQuery = "SELECT COUNT(*) FROM products WHERE %s"
+)
+
+// productsFullTextSearch is the search of the products in the full-text index of their description.
+var productsFullTextSearch = fullTextSearch{columns: "`description`", soundsLike: SoundsLikeProductCondition}
+
+// productPriceMySQLQueries are the queries of the price history and the archival of the products.
+var productPriceMySQLQueries = productPriceQueries{
+	exists:  ExistsProductQuery,
+	product: SelectProductQuery,
+	update:  UpdateProductPriceQuery,
+	archive: ArchiveProductQuery,
+	close:   CloseProductPriceQuery,
+	insert:  InsertProductPriceQuery,
+	prices:  SelectProductPricesQuery,
+	audit:   InsertAuditRecordQuery,
+}
+
+// catalogMySQLQueries are the queries that check the category and the SKU of the products.
+var catalogMySQLQueries = catalogQueries{
+	categoryExists: ExistsCategoryQuery,
+	skuExists:      ExistsProductSkuQuery,
+}
+
 // FindAll returns all products from the database.
-func (r *ProductsMySQL) FindAll() (p []internal.Product, err error) {
+func (r *ProductsMySQL) FindAll(ctx context.Context) (p []internal.Product, err error) {
+	defer observe(ctx, "products.FindAll", time.Now(), &err)
+
 	// execute the query
-	rows, err := r.db.Query("SELECT `id`, `description`, `price` FROM products")
+	rows, err := r.db.QueryContext(ctx, "SELECT `id`, `description`, `price`, `currency`, `tax_category`, COALESCE(`sku`, ''), COALESCE(`category_id`, 0), `unit`, `archived` FROM products")
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +78,7 @@ func (r *ProductsMySQL) FindAll() (p []internal.Product, err error) {
 	for rows.Next() {
 		var pr internal.Product
 		// scan the row into the product
-		err := rows.Scan(&pr.Id, &pr.Description, &pr.Price)
+		err := rows.Scan(&pr.Id, &pr.Description, &pr.Price, &pr.Currency, &pr.TaxCategory, &pr.Sku, &pr.CategoryId, &pr.Unit, &pr.Archived)
 		if err != nil {
 			return nil, err
 		}
@@ -50,35 +94,66 @@ func (r *ProductsMySQL) FindAll() (p []internal.Product, err error) {
 }
 
 // Save saves the product into the database.
-func (r *ProductsMySQL) Save(p *internal.Product) (err error) {
-	// execute the query
-	res, err := r.db.Exec(
-		"INSERT INTO products (`description`, `price`) VALUES (?, ?)",
-		(*p).Description, (*p).Price,
-	)
-	if err != nil {
-		return err
-	}
+func (r *ProductsMySQL) Save(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.Save", time.Now(), &err)
 
-	// get the last inserted id
-	id, err := res.LastInsertId()
-	if err != nil {
-		return err
-	}
+	// default the currency, the tax category and the unit, as the columns do
+	defaultProduct(p)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the category exists and the sku is free
+		err = checkProductCatalog(ctx, tx, catalogMySQLQueries, *p)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO products (`description`, `price`, `currency`, `tax_category`, `sku`, `category_id`, `unit`, `archived`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			(*p).Description, (*p).Price, (*p).Currency, (*p).TaxCategory, productSku(*p), productCategoryId(*p), (*p).Unit, (*p).Archived,
+		)
+		if err != nil {
+			return productWriteError(*p, err)
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
 
-	// set the id
-	(*p).Id = int(id)
+		// set the id
+		(*p).Id = int(id)
 
+		// record the price in the history
+		err = insertProductPrice(ctx, tx, productPriceMySQLQueries, *p, priceDatetime(time.Now()))
+		if err != nil {
+			return err
+		}
+
+		// track its stock
+		err = trackStock(ctx, tx, stockMySQLQueries, (*p).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityProducts, (*p).Id, nil, *p)
+	})
 	return
 }
 
-func (r *ProductsMySQL) GetTopProducts() ([]internal.TopProduct, error) {
-	rows, err := r.db.Query(TopProductsQuery)
+func (r *ProductsMySQL) GetTopProducts(ctx context.Context) (topProducts []internal.TopProduct, err error) {
+	defer observe(ctx, "products.GetTopProducts", time.Now(), &err)
+
+	rows, err := r.db.QueryContext(ctx, TopProductsQuery)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	topProducts := []internal.TopProduct{}
+	topProducts = []internal.TopProduct{}
 	for rows.Next() {
 		var tp internal.TopProduct
 
@@ -89,6 +164,78 @@ func (r *ProductsMySQL) GetTopProducts() ([]internal.TopProduct, error) {
 
 		topProducts = append(topProducts, tp)
 	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
 
 	return topProducts, nil
 }
+
+// FindPrices returns the price history of the product id, oldest first.
+func (r *ProductsMySQL) FindPrices(ctx context.Context, id int) (pp []internal.ProductPrice, err error) {
+	defer observe(ctx, "products.FindPrices", time.Now(), &err)
+
+	pp, err = queryProductPrices(ctx, r.db, productPriceMySQLQueries, id)
+	return
+}
+
+// UpdatePrice changes the price of the product p.Id to p.Price, closing its current price in the history.
+func (r *ProductsMySQL) UpdatePrice(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.UpdatePrice", time.Now(), &err)
+
+	// update the price, its history and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateProductPriceAudited(ctx, tx, productPriceMySQLQueries, p)
+	})
+	return
+}
+
+// UpdateArchived archives the product p.Id, or restores it, as p.Archived says.
+func (r *ProductsMySQL) UpdateArchived(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.UpdateArchived", time.Now(), &err)
+
+	// update the flag and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateProductArchivedAudited(ctx, tx, productPriceMySQLQueries, p)
+	})
+	return
+}
+
+// Search returns the page of q of the products matching its text, ranked by the relevance of the full-text index.
+func (r *ProductsMySQL) Search(ctx context.Context, q internal.SearchQuery) (m internal.ProductMatches, err error) {
+	defer observe(ctx, "products.Search", time.Now(), &err)
+
+	m.Matches = []internal.ProductMatch{}
+	terms := q.Terms()
+	if len(terms) == 0 {
+		return
+	}
+	cond, condArgs := productsFullTextSearch.condition(terms)
+	score, scoreArgs := productsFullTextSearch.score(terms)
+
+	// count the products found
+	err = r.db.QueryRowContext(ctx, fmt.Sprintf(CountProductsQuery, cond), condArgs...).Scan(&m.Total)
+	if err != nil {
+		return
+	}
+
+	// execute the query of the page
+	args := append(append(scoreArgs, condArgs...), q.Limit, q.Offset)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(SearchProductsQuery, score, cond), args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pm internal.ProductMatch
+		err = rows.Scan(&pm.Id, &pm.Description, &pm.Price, &pm.Currency, &pm.TaxCategory, &pm.Sku, &pm.CategoryId, &pm.Unit, &pm.Archived, &pm.Score)
+		if err != nil {
+			return
+		}
+		m.Matches = append(m.Matches, pm)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+// NewProductsPostgres creates new postgres repository for product entity.
+func NewProductsPostgres(db *sql.DB) *ProductsPostgres {
+	return &ProductsPostgres{db}
+}
+
+// ProductsPostgres is the Postgres repository implementation for product entity.
+type ProductsPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+const (
+	TopProductsPostgresQuery         = `SELECT p."id", p."description", SUM(s."quantity") as sold FROM products as p INNER JOIN sales as s ON p."id" = s."product_id" GROUP BY p."id" ORDER BY sold DESC LIMIT 5`
+	ExistsProductPostgresQuery       = `SELECT COUNT(*) FROM products WHERE "id" = $1`
+	SelectProductPostgresQuery       = `SELECT "description", "price", "currency", "tax_category", COALESCE("sku", ''), COALESCE("category_id", 0), "unit", "archived" FROM products WHERE "id" = $1`
+	UpdateProductPricePostgresQuery  = `UPDATE products SET "price" = $1 WHERE "id" = $2`
+	ArchiveProductPostgresQuery      = `UPDATE products SET "archived" = $1 WHERE "id" = $2`
+	ExistsProductSkuPostgresQuery    = `SELECT COUNT(*) FROM products WHERE "sku" = $1`
+	CloseProductPricePostgresQuery   = `UPDATE product_prices SET "valid_to" = $1 WHERE "product_id" = $2 AND "valid_to" IS NULL`
+	InsertProductPricePostgresQuery  = `INSERT INTO product_prices ("product_id", "price", "currency", "valid_from") VALUES ($1, $2, $3, $4)`
+	SelectProductPricesPostgresQuery = `SELECT "id", "product_id", "price", "currency", to_char("valid_from", 'YYYY-MM-DD HH24:MI:SS'), to_char("valid_to", 'YYYY-MM-DD HH24:MI:SS') FROM product_prices WHERE "product_id" = $1 ORDER BY "id"`
+)
+
+// productPricePostgresQueries are the queries of the price history and the archival of the products.
+var productPricePostgresQueries = productPriceQueries{
+	exists:  ExistsProductPostgresQuery,
+	product: SelectProductPostgresQuery,
+	update:  UpdateProductPricePostgresQuery,
+	archive: ArchiveProductPostgresQuery,
+	close:   CloseProductPricePostgresQuery,
+	insert:  InsertProductPricePostgresQuery,
+	prices:  SelectProductPricesPostgresQuery,
+	audit:   InsertAuditRecordPostgresQuery,
+}
+
+// catalogPostgresQueries are the queries that check the category and the SKU of the products.
+var catalogPostgresQueries = catalogQueries{
+	categoryExists: ExistsCategoryPostgresQuery,
+	skuExists:      ExistsProductSkuPostgresQuery,
+}
+
+// FindAll returns all products from the database.
+func (r *ProductsPostgres) FindAll(ctx context.Context) (p []internal.Product, err error) {
+	defer observe(ctx, "products.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, `SELECT "id", "description", "price", "currency", "tax_category", COALESCE("sku", ''), COALESCE("category_id", 0), "unit", "archived" FROM products`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var pr internal.Product
+		// scan the row into the product
+		err := rows.Scan(&pr.Id, &pr.Description, &pr.Price, &pr.Currency, &pr.TaxCategory, &pr.Sku, &pr.CategoryId, &pr.Unit, &pr.Archived)
+		if err != nil {
+			return nil, err
+		}
+		// append the product to the slice
+		p = append(p, pr)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the product into the database.
+func (r *ProductsPostgres) Save(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.Save", time.Now(), &err)
+
+	// default the currency, the tax category and the unit, as the columns do
+	defaultProduct(p)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the category exists and the sku is free
+		err = checkProductCatalog(ctx, tx, catalogPostgresQueries, *p)
+		if err != nil {
+			return err
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO products ("description", "price", "currency", "tax_category", "sku", "category_id", "unit", "archived") VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING "id"`,
+			(*p).Description, (*p).Price, (*p).Currency, (*p).TaxCategory, productSku(*p), productCategoryId(*p), (*p).Unit, (*p).Archived,
+		).Scan(&(*p).Id)
+		if err != nil {
+			return productWriteError(*p, err)
+		}
+
+		// record the price in the history
+		err = insertProductPrice(ctx, tx, productPricePostgresQueries, *p, priceDatetime(time.Now()))
+		if err != nil {
+			return err
+		}
+
+		// track its stock
+		err = trackStock(ctx, tx, stockPostgresQueries, (*p).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityProducts, (*p).Id, nil, *p)
+	})
+	return
+}
+
+func (r *ProductsPostgres) GetTopProducts(ctx context.Context) (topProducts []internal.TopProduct, err error) {
+	defer observe(ctx, "products.GetTopProducts", time.Now(), &err)
+
+	rows, err := r.db.QueryContext(ctx, TopProductsPostgresQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	topProducts = []internal.TopProduct{}
+	for rows.Next() {
+		var tp internal.TopProduct
+
+		err := rows.Scan(&tp.Id, &tp.Description, &tp.Total)
+		if err != nil {
+			return nil, err
+		}
+
+		topProducts = append(topProducts, tp)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return topProducts, nil
+}
+
+// FindPrices returns the price history of the product id, oldest first.
+func (r *ProductsPostgres) FindPrices(ctx context.Context, id int) (pp []internal.ProductPrice, err error) {
+	defer observe(ctx, "products.FindPrices", time.Now(), &err)
+
+	pp, err = queryProductPrices(ctx, r.db, productPricePostgresQueries, id)
+	return
+}
+
+// UpdatePrice changes the price of the product p.Id to p.Price, closing its current price in the history.
+func (r *ProductsPostgres) UpdatePrice(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.UpdatePrice", time.Now(), &err)
+
+	// update the price, its history and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateProductPriceAudited(ctx, tx, productPricePostgresQueries, p)
+	})
+	return
+}
+
+// UpdateArchived archives the product p.Id, or restores it, as p.Archived says.
+func (r *ProductsPostgres) UpdateArchived(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.UpdateArchived", time.Now(), &err)
+
+	// update the flag and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateProductArchivedAudited(ctx, tx, productPricePostgresQueries, p)
+	})
+	return
+}
+
+// Search returns the page of q of the products matching its text, ranked by internal.MatchScore,
+// as postgres has no full-text index of the products.
+func (r *ProductsPostgres) Search(ctx context.Context, q internal.SearchQuery) (m internal.ProductMatches, err error) {
+	defer observe(ctx, "products.Search", time.Now(), &err)
+
+	p, err := r.FindAll(ctx)
+	if err != nil {
+		return
+	}
+	m = searchProducts(p, q)
+	return
+}
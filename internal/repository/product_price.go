@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/internal"
+)
+
+// productPriceQueries are the queries of a sql database on the price history and the archival of the products.
+// The arguments of each query are listed in the order of its placeholders.
+type productPriceQueries struct {
+	// exists counts the products of an id: id.
+	exists string
+	// product selects the attributes of a product, as scanProductAttributes expects them: id.
+	product string
+	// update sets the price of a product: price, id.
+	update string
+	// archive sets the archived flag of a product: archived, id.
+	archive string
+	// close sets the end of the current price of a product: valid_to, product_id.
+	close string
+	// insert inserts a current price: product_id, price, currency, valid_from.
+	insert string
+	// prices selects the id, product_id, price, currency, valid_from and valid_to of the prices of a product, by id: product_id.
+	prices string
+	// audit inserts an audit record, as writeAudit expects it.
+	audit string
+}
+
+// priceDatetime returns t in the layout of the validity datetimes of the prices, the one of the invoice datetimes.
+func priceDatetime(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
+
+// errProductNotFound returns the error of the product id that does not exist.
+func errProductNotFound(id int) error {
+	return fmt.Errorf("%w: %d", internal.ErrProductNotFound, id)
+}
+
+// scanProductAttributes scans the description, price, currency, tax category, SKU, category, unit and archived
+// flag of a product from row into p, the SKU and the category read as "" and 0 when NULL.
+func scanProductAttributes(row *sql.Row, p *internal.Product) error {
+	return row.Scan(&(*p).Description, &(*p).Price, &(*p).Currency, &(*p).TaxCategory, &(*p).Sku, &(*p).CategoryId, &(*p).Unit, &(*p).Archived)
+}
+
+// insertProductPrice inserts the price of p, valid from validFrom, within tx.
+func insertProductPrice(ctx context.Context, tx execer, q productPriceQueries, p internal.Product, validFrom string) (err error) {
+	_, err = tx.ExecContext(ctx, q.insert, p.Id, p.Price, p.Currency, validFrom)
+	return
+}
+
+// updateProductPriceAudited changes the price of the product p.Id to p.Price within tx, closing its current price,
+// and audits the change. It fills the other attributes of p. An unchanged price is not recorded.
+func updateProductPriceAudited(ctx context.Context, tx *sql.Tx, q productPriceQueries, p *internal.Product) (err error) {
+	// - current product
+	before := internal.Product{Id: (*p).Id}
+	err = scanProductAttributes(tx.QueryRowContext(ctx, q.product, before.Id), &before)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errProductNotFound(before.Id)
+	}
+	if err != nil {
+		return
+	}
+	after := before
+	after.Price = (*p).Price
+	*p = after
+	if after.Price == before.Price {
+		return
+	}
+
+	// - price and history
+	_, err = tx.ExecContext(ctx, q.update, after.Price, after.Id)
+	if err != nil {
+		return
+	}
+	now := priceDatetime(time.Now())
+	_, err = tx.ExecContext(ctx, q.close, now, after.Id)
+	if err != nil {
+		return
+	}
+	err = insertProductPrice(ctx, tx, q, after, now)
+	if err != nil {
+		return
+	}
+
+	// - audit
+	return writeAudit(ctx, tx, q.audit, AuditEntityProducts, after.Id, before, after)
+}
+
+// updateProductArchivedAudited archives the product p.Id within tx, or restores it, as p.Archived says, and audits
+// the change. It fills the other attributes of p. An unchanged flag is not recorded.
+func updateProductArchivedAudited(ctx context.Context, tx *sql.Tx, q productPriceQueries, p *internal.Product) (err error) {
+	// - current product
+	before := internal.Product{Id: (*p).Id}
+	err = scanProductAttributes(tx.QueryRowContext(ctx, q.product, before.Id), &before)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errProductNotFound(before.Id)
+	}
+	if err != nil {
+		return
+	}
+	after := before
+	after.Archived = (*p).Archived
+	*p = after
+	if after.Archived == before.Archived {
+		return
+	}
+
+	// - flag
+	_, err = tx.ExecContext(ctx, q.archive, after.Archived, after.Id)
+	if err != nil {
+		return
+	}
+
+	// - audit
+	return writeAudit(ctx, tx, q.audit, AuditEntityProducts, after.Id, before, after)
+}
+
+// queryProductPrices returns the price history of the product id, oldest first.
+func queryProductPrices(ctx context.Context, db *sql.DB, q productPriceQueries, id int) (pp []internal.ProductPrice, err error) {
+	var n int
+	err = db.QueryRowContext(ctx, q.exists, id).Scan(&n)
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		return nil, errProductNotFound(id)
+	}
+
+	rows, err := db.QueryContext(ctx, q.prices, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	pp = []internal.ProductPrice{}
+	for rows.Next() {
+		var (
+			pr      internal.ProductPrice
+			validTo sql.NullString
+		)
+		err = rows.Scan(&pr.Id, &pr.ProductId, &pr.Price, &pr.Currency, &pr.ValidFrom, &validTo)
+		if err != nil {
+			return
+		}
+		pr.ValidTo = validTo.String
+		pp = append(pp, pr)
+	}
+	err = rows.Err()
+	return
+}
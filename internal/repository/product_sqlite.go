@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+// NewProductsSQLite creates new sqlite repository for product entity.
+func NewProductsSQLite(db *sql.DB) *ProductsSQLite {
+	return &ProductsSQLite{db}
+}
+
+// ProductsSQLite is the SQLite repository implementation for product entity.
+type ProductsSQLite struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+const (
+	TopProductsSQLiteQuery         = `SELECT p."id", p."description", SUM(s."quantity") as sold FROM products as p INNER JOIN sales as s ON p."id" = s."product_id" GROUP BY p."id" ORDER BY sold DESC LIMIT 5`
+	ExistsProductSQLiteQuery       = `SELECT COUNT(*) FROM products WHERE "id" = ?`
+	SelectProductSQLiteQuery       = `SELECT "description", "price", "currency", "tax_category", COALESCE("sku", ''), COALESCE("category_id", 0), "unit", "archived" FROM products WHERE "id" = ?`
+	UpdateProductPriceSQLiteQuery  = `UPDATE products SET "price" = ? WHERE "id" = ?`
+	ArchiveProductSQLiteQuery      = `UPDATE products SET "archived" = ? WHERE "id" = ?`
+	ExistsProductSkuSQLiteQuery    = `SELECT COUNT(*) FROM products WHERE "sku" = ?`
+	CloseProductPriceSQLiteQuery   = `UPDATE product_prices SET "valid_to" = ? WHERE "product_id" = ? AND "valid_to" IS NULL`
+	InsertProductPriceSQLiteQuery  = `INSERT INTO product_prices ("product_id", "price", "currency", "valid_from") VALUES (?, ?, ?, ?)`
+	SelectProductPricesSQLiteQuery = `SELECT "id", "product_id", "price", "currency", "valid_from", "valid_to" FROM product_prices WHERE "product_id" = ? ORDER BY "id"`
+)
+
+// productPriceSQLiteQueries are the queries of the price history and the archival of the products.
+var productPriceSQLiteQueries = productPriceQueries{
+	exists:  ExistsProductSQLiteQuery,
+	product: SelectProductSQLiteQuery,
+	update:  UpdateProductPriceSQLiteQuery,
+	archive: ArchiveProductSQLiteQuery,
+	close:   CloseProductPriceSQLiteQuery,
+	insert:  InsertProductPriceSQLiteQuery,
+	prices:  SelectProductPricesSQLiteQuery,
+	audit:   InsertAuditRecordSQLiteQuery,
+}
+
+// catalogSQLiteQueries are the queries that check the category and the SKU of the products.
+var catalogSQLiteQueries = catalogQueries{
+	categoryExists: ExistsCategorySQLiteQuery,
+	skuExists:      ExistsProductSkuSQLiteQuery,
+}
+
+// FindAll returns all products from the database.
+func (r *ProductsSQLite) FindAll(ctx context.Context) (p []internal.Product, err error) {
+	defer observe(ctx, "products.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, `SELECT "id", "description", "price", "currency", "tax_category", COALESCE("sku", ''), COALESCE("category_id", 0), "unit", "archived" FROM products`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var pr internal.Product
+		// scan the row into the product
+		err := rows.Scan(&pr.Id, &pr.Description, &pr.Price, &pr.Currency, &pr.TaxCategory, &pr.Sku, &pr.CategoryId, &pr.Unit, &pr.Archived)
+		if err != nil {
+			return nil, err
+		}
+		// append the product to the slice
+		p = append(p, pr)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the product into the database.
+func (r *ProductsSQLite) Save(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.Save", time.Now(), &err)
+
+	// default the currency, the tax category and the unit, as the columns do
+	defaultProduct(p)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the category exists and the sku is free
+		err = checkProductCatalog(ctx, tx, catalogSQLiteQueries, *p)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO products ("description", "price", "currency", "tax_category", "sku", "category_id", "unit", "archived") VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			(*p).Description, (*p).Price, (*p).Currency, (*p).TaxCategory, productSku(*p), productCategoryId(*p), (*p).Unit, (*p).Archived,
+		)
+		if err != nil {
+			return productWriteError(*p, err)
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*p).Id = int(id)
+
+		// record the price in the history
+		err = insertProductPrice(ctx, tx, productPriceSQLiteQueries, *p, priceDatetime(time.Now()))
+		if err != nil {
+			return err
+		}
+
+		// track its stock
+		err = trackStock(ctx, tx, stockSQLiteQueries, (*p).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordSQLiteQuery, AuditEntityProducts, (*p).Id, nil, *p)
+	})
+	return
+}
+
+func (r *ProductsSQLite) GetTopProducts(ctx context.Context) (topProducts []internal.TopProduct, err error) {
+	defer observe(ctx, "products.GetTopProducts", time.Now(), &err)
+
+	rows, err := r.db.QueryContext(ctx, TopProductsSQLiteQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	topProducts = []internal.TopProduct{}
+	for rows.Next() {
+		var tp internal.TopProduct
+
+		err := rows.Scan(&tp.Id, &tp.Description, &tp.Total)
+		if err != nil {
+			return nil, err
+		}
+
+		topProducts = append(topProducts, tp)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return topProducts, nil
+}
+
+// FindPrices returns the price history of the product id, oldest first.
+func (r *ProductsSQLite) FindPrices(ctx context.Context, id int) (pp []internal.ProductPrice, err error) {
+	defer observe(ctx, "products.FindPrices", time.Now(), &err)
+
+	pp, err = queryProductPrices(ctx, r.db, productPriceSQLiteQueries, id)
+	return
+}
+
+// UpdatePrice changes the price of the product p.Id to p.Price, closing its current price in the history.
+func (r *ProductsSQLite) UpdatePrice(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.UpdatePrice", time.Now(), &err)
+
+	// update the price, its history and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateProductPriceAudited(ctx, tx, productPriceSQLiteQueries, p)
+	})
+	return
+}
+
+// UpdateArchived archives the product p.Id, or restores it, as p.Archived says.
+func (r *ProductsSQLite) UpdateArchived(ctx context.Context, p *internal.Product) (err error) {
+	defer observe(ctx, "products.UpdateArchived", time.Now(), &err)
+
+	// update the flag and the audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateProductArchivedAudited(ctx, tx, productPriceSQLiteQueries, p)
+	})
+	return
+}
+
+// Search returns the page of q of the products matching its text, ranked by internal.MatchScore,
+// as sqlite has no full-text index of the products.
+func (r *ProductsSQLite) Search(ctx context.Context, q internal.SearchQuery) (m internal.ProductMatches, err error) {
+	defer observe(ctx, "products.Search", time.Now(), &err)
+
+	p, err := r.FindAll(ctx)
+	if err != nil {
+		return
+	}
+	m = searchProducts(p, q)
+	return
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"app/internal"
+)
+
+// queryPromotions returns the promotions selected by query.
+// The columns of query are id, name, kind, product_id, customer_condition, percent, buy_quantity, get_quantity,
+// valid_from, valid_to and stackable.
+func queryPromotions(ctx context.Context, q querier, query string) (p internal.Promotions, err error) {
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pr                   internal.Promotion
+			productId, condition sql.NullInt64
+			validTo              sql.NullString
+		)
+		err = rows.Scan(&pr.Id, &pr.Name, &pr.Kind, &productId, &condition, &pr.Percent, &pr.Buy, &pr.Get, &pr.ValidFrom, &validTo, &pr.Stackable)
+		if err != nil {
+			return
+		}
+		if productId.Valid {
+			id := int(productId.Int64)
+			pr.ProductId = &id
+		}
+		if condition.Valid {
+			c := int(condition.Int64)
+			pr.Condition = &c
+		}
+		pr.ValidTo = validTo.String
+		p = append(p, pr)
+	}
+	err = rows.Err()
+	return
+}
+
+// checkPromotionProduct checks, within tx, that the product of p exists, if it has one.
+// query counts the products of an id.
+func checkPromotionProduct(ctx context.Context, tx *sql.Tx, query string, p internal.Promotion) (err error) {
+	if p.ProductId == nil {
+		return
+	}
+
+	var n int
+	err = tx.QueryRowContext(ctx, query, *p.ProductId).Scan(&n)
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		return errProductNotFound(*p.ProductId)
+	}
+	return
+}
+
+// promotionValidTo returns the end of the validity of p as stored, NULL if it has no end.
+func promotionValidTo(p internal.Promotion) sql.NullString {
+	return sql.NullString{String: p.ValidTo, Valid: p.ValidTo != ""}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewPromotionsMemory creates new memory repository for promotion entity.
+func NewPromotionsMemory(db *MemoryDB) *PromotionsMemory {
+	return &PromotionsMemory{db}
+}
+
+// PromotionsMemory is the memory repository implementation for promotion entity.
+type PromotionsMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all promotions from the database.
+func (r *PromotionsMemory) FindAll(ctx context.Context) (p []internal.Promotion, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	p = r.db.promotionsSorted()
+
+	return
+}
+
+// Save saves the promotion into the database.
+func (r *PromotionsMemory) Save(ctx context.Context, p *internal.Promotion) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// check the product exists
+	if (*p).ProductId != nil {
+		if _, ok := r.db.products[*(*p).ProductId]; !ok {
+			return errProductNotFound(*(*p).ProductId)
+		}
+	}
+
+	// set the id
+	r.db.lastPromotionId++
+	(*p).Id = r.db.lastPromotionId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityPromotions, (*p).Id, nil, *p)
+	if err != nil {
+		r.db.lastPromotionId--
+		return
+	}
+
+	// insert the promotion
+	r.db.promotions[(*p).Id] = *p
+
+	return
+}
+
+// promotionsSorted returns the promotions by id. The caller must hold the lock.
+func (db *MemoryDB) promotionsSorted() (p internal.Promotions) {
+	for _, id := range sortedKeys(db.promotions) {
+		p = append(p, db.promotions[id])
+	}
+	return
+}
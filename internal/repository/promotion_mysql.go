@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectPromotionsQuery = "SELECT `id`, `name`, `kind`, `product_id`, `customer_condition`, `percent`, `buy_quantity`, `get_quantity`, `valid_from`, `valid_to`, `stackable` FROM promotions ORDER BY `id`"
+)
+
+// NewPromotionsMySQL creates new mysql repository for promotion entity.
+func NewPromotionsMySQL(db *sql.DB) *PromotionsMySQL {
+	return &PromotionsMySQL{db}
+}
+
+// PromotionsMySQL is the MySQL repository implementation for promotion entity.
+type PromotionsMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all promotions from the database.
+func (r *PromotionsMySQL) FindAll(ctx context.Context) (p []internal.Promotion, err error) {
+	defer observe(ctx, "promotions.FindAll", time.Now(), &err)
+
+	p, err = queryPromotions(ctx, r.db, SelectPromotionsQuery)
+	return
+}
+
+// Save saves the promotion into the database.
+func (r *PromotionsMySQL) Save(ctx context.Context, p *internal.Promotion) (err error) {
+	defer observe(ctx, "promotions.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product exists
+		err = checkPromotionProduct(ctx, tx, ExistsProductQuery, *p)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO promotions (`name`, `kind`, `product_id`, `customer_condition`, `percent`, `buy_quantity`, `get_quantity`, `valid_from`, `valid_to`, `stackable`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			(*p).Name, (*p).Kind, (*p).ProductId, (*p).Condition, (*p).Percent, (*p).Buy, (*p).Get, (*p).ValidFrom, promotionValidTo(*p), (*p).Stackable,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*p).Id = int(id)
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityPromotions, (*p).Id, nil, *p)
+	})
+	return
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectPromotionsPostgresQuery = `SELECT "id", "name", "kind", "product_id", "customer_condition", "percent", "buy_quantity", "get_quantity", to_char("valid_from", 'YYYY-MM-DD'), to_char("valid_to", 'YYYY-MM-DD'), "stackable" FROM promotions ORDER BY "id"`
+)
+
+// NewPromotionsPostgres creates new postgres repository for promotion entity.
+func NewPromotionsPostgres(db *sql.DB) *PromotionsPostgres {
+	return &PromotionsPostgres{db}
+}
+
+// PromotionsPostgres is the Postgres repository implementation for promotion entity.
+type PromotionsPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all promotions from the database.
+func (r *PromotionsPostgres) FindAll(ctx context.Context) (p []internal.Promotion, err error) {
+	defer observe(ctx, "promotions.FindAll", time.Now(), &err)
+
+	p, err = queryPromotions(ctx, r.db, SelectPromotionsPostgresQuery)
+	return
+}
+
+// Save saves the promotion into the database.
+func (r *PromotionsPostgres) Save(ctx context.Context, p *internal.Promotion) (err error) {
+	defer observe(ctx, "promotions.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product exists
+		err = checkPromotionProduct(ctx, tx, ExistsProductPostgresQuery, *p)
+		if err != nil {
+			return err
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO promotions ("name", "kind", "product_id", "customer_condition", "percent", "buy_quantity", "get_quantity", "valid_from", "valid_to", "stackable") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING "id"`,
+			(*p).Name, (*p).Kind, (*p).ProductId, (*p).Condition, (*p).Percent, (*p).Buy, (*p).Get, (*p).ValidFrom, promotionValidTo(*p), (*p).Stackable,
+		).Scan(&(*p).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityPromotions, (*p).Id, nil, *p)
+	})
+	return
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectPromotionsSQLiteQuery = `SELECT "id", "name", "kind", "product_id", "customer_condition", "percent", "buy_quantity", "get_quantity", "valid_from", "valid_to", "stackable" FROM promotions ORDER BY "id"`
+)
+
+// NewPromotionsSQLite creates new sqlite repository for promotion entity.
+func NewPromotionsSQLite(db *sql.DB) *PromotionsSQLite {
+	return &PromotionsSQLite{db}
+}
+
+// PromotionsSQLite is the SQLite repository implementation for promotion entity.
+type PromotionsSQLite struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all promotions from the database.
+func (r *PromotionsSQLite) FindAll(ctx context.Context) (p []internal.Promotion, err error) {
+	defer observe(ctx, "promotions.FindAll", time.Now(), &err)
+
+	p, err = queryPromotions(ctx, r.db, SelectPromotionsSQLiteQuery)
+	return
+}
+
+// Save saves the promotion into the database.
+func (r *PromotionsSQLite) Save(ctx context.Context, p *internal.Promotion) (err error) {
+	defer observe(ctx, "promotions.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product exists
+		err = checkPromotionProduct(ctx, tx, ExistsProductSQLiteQuery, *p)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO promotions ("name", "kind", "product_id", "customer_condition", "percent", "buy_quantity", "get_quantity", "valid_from", "valid_to", "stackable") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			(*p).Name, (*p).Kind, (*p).ProductId, (*p).Condition, (*p).Percent, (*p).Buy, (*p).Get, (*p).ValidFrom, promotionValidTo(*p), (*p).Stackable,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*p).Id = int(id)
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordSQLiteQuery, AuditEntityPromotions, (*p).Id, nil, *p)
+	})
+	return
+}
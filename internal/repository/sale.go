@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"app/internal"
+)
+
+// saleQueries are the queries of a sql database that price a sale, store its discounts and take its units from the stock.
+// The arguments of each query are listed in the order of its placeholders.
+type saleQueries struct {
+	// product selects the price, currency, tax category and archived flag of a product, and the currency, datetime
+	// and customer condition of an invoice: product id, invoice id.
+	product string
+	// taxRates selects the tax rates as queryTaxRates expects them.
+	taxRates string
+	// promotions selects the promotions as queryPromotions expects them.
+	promotions string
+	// insertDiscount inserts a discount of a sale: sale_id, promotion_id, amount.
+	insertDiscount string
+	// discounts selects the sale_id, promotion_id and amount of the discounts of the sales, by id.
+	discounts string
+	// stock are the queries that take the units of a sale from the stock.
+	stock stockQueries
+}
+
+// priceSale checks, within tx, that the product of s is not archived and is in the currency of its invoice, and
+// prices s as of now: its unit price is the current price of the product, its tax rate the one applicable to the
+// product and the customer of the invoice, and its discounts those of the promotions valid at the invoice datetime.
+// A missing product or invoice passes, so the insert reports the foreign key violation.
+func priceSale(ctx context.Context, tx *sql.Tx, q saleQueries, s *internal.Sale) (err error) {
+	var (
+		price            internal.Money
+		product, invoice internal.Currency
+		category         string
+		archived         bool
+		datetime         string
+		condition        int
+	)
+	err = tx.QueryRowContext(ctx, q.product, (*s).ProductId, (*s).InvoiceId).Scan(&price, &product, &category, &archived, &invoice, &datetime, &condition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return
+	}
+
+	if archived {
+		return errProductArchived((*s).ProductId)
+	}
+	if product != invoice {
+		return errCurrencyMismatch((*s).ProductId, product, (*s).InvoiceId, invoice)
+	}
+	(*s).UnitPrice = price
+
+	rates, err := queryTaxRates(ctx, tx, q.taxRates)
+	if err != nil {
+		return
+	}
+	(*s).TaxRate = rates.For(category, condition)
+
+	promotions, err := queryPromotions(ctx, tx, q.promotions)
+	if err != nil {
+		return
+	}
+	(*s).Discounts, (*s).Discount = promotions.Discounts((*s).ProductId, condition, (*s).Quantity, price, datetime)
+	return
+}
+
+// insertSaleDiscounts inserts the discounts of the saved sale s within tx.
+func insertSaleDiscounts(ctx context.Context, tx execer, q saleQueries, s internal.Sale) (err error) {
+	for _, d := range s.Discounts {
+		_, err = tx.ExecContext(ctx, q.insertDiscount, s.Id, d.PromotionId, d.Amount)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// attachSaleDiscounts sets the discounts of the sales s, as read from the database.
+func attachSaleDiscounts(ctx context.Context, db querier, q saleQueries, s []internal.Sale) (err error) {
+	rows, err := db.QueryContext(ctx, q.discounts)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	discounts := make(map[int][]internal.SaleDiscount)
+	for rows.Next() {
+		var (
+			saleId int
+			d      internal.SaleDiscount
+		)
+		err = rows.Scan(&saleId, &d.PromotionId, &d.Amount)
+		if err != nil {
+			return
+		}
+		discounts[saleId] = append(discounts[saleId], d)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	for ix := range s {
+		s[ix].Discounts = discounts[s[ix].Id]
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewSalesMemory creates new memory repository for sale entity.
+func NewSalesMemory(db *MemoryDB) *SalesMemory {
+	return &SalesMemory{db}
+}
+
+// SalesMemory is the memory repository implementation for sale entity.
+type SalesMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all sales from the database.
+func (r *SalesMemory) FindAll(ctx context.Context) (s []internal.Sale, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, id := range sortedKeys(r.db.sales) {
+		s = append(s, r.db.sales[id])
+	}
+
+	return
+}
+
+// Save saves the sale into the database.
+func (r *SalesMemory) Save(ctx context.Context, s *internal.Sale) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// default the warehouse, as the column does
+	(*s).Warehouse = defaultWarehouse((*s).Warehouse)
+
+	// check the product and the invoice exist
+	pr, ok := r.db.products[(*s).ProductId]
+	if !ok {
+		return ErrForeignKeyViolation
+	}
+	iv, ok := r.db.invoices[(*s).InvoiceId]
+	if !ok {
+		return ErrForeignKeyViolation
+	}
+
+	// check the product is not archived and is in the currency of the invoice
+	if pr.Archived {
+		return errProductArchived(pr.Id)
+	}
+	if pr.Currency != iv.Currency {
+		return errCurrencyMismatch(pr.Id, pr.Currency, iv.Id, iv.Currency)
+	}
+
+	// take the current price of the product, and the tax rate and discounts applicable to it and the customer
+	condition := int(r.db.customers[iv.CustomerId].Condition)
+	(*s).UnitPrice = pr.Price
+	(*s).TaxRate = r.db.taxRatesSorted().For(pr.TaxCategory, condition)
+	(*s).Discounts, (*s).Discount = r.db.promotionsSorted().Discounts(pr.Id, condition, (*s).Quantity, pr.Price, iv.Datetime)
+
+	// check the warehouse holds the units sold
+	tracked, err := r.db.checkStock(internal.StockMovement{StockMovementAttributes: internal.StockMovementAttributes{
+		ProductId: pr.Id, Warehouse: (*s).Warehouse, Kind: internal.StockMovementSale, Quantity: -(*s).Quantity,
+	}})
+	if err != nil {
+		return
+	}
+
+	// set the id
+	r.db.lastSaleId++
+	(*s).Id = r.db.lastSaleId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntitySales, (*s).Id, nil, *s)
+	if err != nil {
+		r.db.lastSaleId--
+		return
+	}
+
+	// insert the sale and take its units from the stock
+	r.db.sales[(*s).Id] = *s
+	r.db.takeSaleStock(*s, tracked)
+
+	return
+}
@@ -1,11 +1,29 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"app/internal"
 )
 
+const (
+	SaleProductQuery         = "SELECT p.`price`, p.`currency`, p.`tax_category`, p.`archived`, i.`currency`, i.`datetime`, c.`condition` FROM products AS p, invoices AS i, customers AS c WHERE p.`id` = ? AND i.`id` = ? AND c.`id` = i.`customer_id`"
+	InsertSaleDiscountQuery  = "INSERT INTO sale_discounts (`sale_id`, `promotion_id`, `amount`) VALUES (?, ?, ?)"
+	SelectSaleDiscountsQuery = "SELECT `sale_id`, `promotion_id`, `amount` FROM sale_discounts ORDER BY `id`"
+)
+
+// saleMySQLQueries are the queries that price the sales, store their discounts and take their units from the stock.
+var saleMySQLQueries = saleQueries{
+	product:        SaleProductQuery,
+	taxRates:       SelectTaxRatesQuery,
+	promotions:     SelectPromotionsQuery,
+	insertDiscount: InsertSaleDiscountQuery,
+	discounts:      SelectSaleDiscountsQuery,
+	stock:          stockMySQLQueries,
+}
+
 // NewSalesMySQL creates new mysql repository for sale entity.
 func NewSalesMySQL(db *sql.DB) *SalesMySQL {
 	return &SalesMySQL{db}
@@ -18,9 +36,11 @@ type SalesMySQL struct {
 }
 
 // FindAll returns all sales from the database.
-func (r *SalesMySQL) FindAll() (s []internal.Sale, err error) {
+func (r *SalesMySQL) FindAll(ctx context.Context) (s []internal.Sale, err error) {
+	defer observe(ctx, "sales.FindAll", time.Now(), &err)
+
 	// execute the query
-	rows, err := r.db.Query("SELECT `id`, `quantity`, `product_id`, `invoice_id` FROM sales")
+	rows, err := r.db.QueryContext(ctx, "SELECT `id`, `quantity`, `product_id`, `invoice_id`, `warehouse`, `unit_price`, `tax_rate`, `discount` FROM sales")
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +50,7 @@ func (r *SalesMySQL) FindAll() (s []internal.Sale, err error) {
 	for rows.Next() {
 		var sa internal.Sale
 		// scan the row into the sale
-		err := rows.Scan(&sa.Id, &sa.Quantity, &sa.ProductId, &sa.InvoiceId)
+		err := rows.Scan(&sa.Id, &sa.Quantity, &sa.ProductId, &sa.InvoiceId, &sa.Warehouse, &sa.UnitPrice, &sa.TaxRate, &sa.Discount)
 		if err != nil {
 			return nil, err
 		}
@@ -42,28 +62,62 @@ func (r *SalesMySQL) FindAll() (s []internal.Sale, err error) {
 		return
 	}
 
+	// attach the discounts of the sales
+	err = attachSaleDiscounts(ctx, r.db, saleMySQLQueries, s)
+	if err != nil {
+		return
+	}
+
 	return
 }
 
 // Save saves the sale into the database.
-func (r *SalesMySQL) Save(s *internal.Sale) (err error) {
-	// execute the query
-	res, err := r.db.Exec(
-		"INSERT INTO sales (`quantity`, `product_id`, `invoice_id`) VALUES (?, ?, ?)",
-		(*s).Quantity, (*s).ProductId, (*s).InvoiceId,
-	)
-	if err != nil {
-		return err
-	}
+func (r *SalesMySQL) Save(ctx context.Context, s *internal.Sale) (err error) {
+	defer observe(ctx, "sales.Save", time.Now(), &err)
 
-	// get the last inserted id
-	id, err := res.LastInsertId()
-	if err != nil {
-		return err
-	}
+	// default the warehouse, as the column does
+	(*s).Warehouse = defaultWarehouse((*s).Warehouse)
 
-	// set the id
-	(*s).Id = int(id)
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product is in the currency of the invoice and take its price, tax rate and discounts
+		err = priceSale(ctx, tx, saleMySQLQueries, s)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO sales (`quantity`, `product_id`, `invoice_id`, `warehouse`, `unit_price`, `tax_rate`, `discount`) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			(*s).Quantity, (*s).ProductId, (*s).InvoiceId, (*s).Warehouse, (*s).UnitPrice, (*s).TaxRate, (*s).Discount,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*s).Id = int(id)
+
+		// insert the discounts
+		err = insertSaleDiscounts(ctx, tx, saleMySQLQueries, *s)
+		if err != nil {
+			return err
+		}
+
+		// take the units from the stock of the warehouse
+		err = takeSaleStock(ctx, tx, saleMySQLQueries.stock, *s)
+		if err != nil {
+			return err
+		}
 
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntitySales, (*s).Id, nil, *s)
+	})
 	return
 }
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SaleProductPostgresQuery         = `SELECT p."price", p."currency", p."tax_category", p."archived", i."currency", to_char(i."datetime", 'YYYY-MM-DD HH24:MI:SS'), c."condition" FROM products AS p, invoices AS i, customers AS c WHERE p."id" = $1 AND i."id" = $2 AND c."id" = i."customer_id"`
+	InsertSaleDiscountPostgresQuery  = `INSERT INTO sale_discounts ("sale_id", "promotion_id", "amount") VALUES ($1, $2, $3)`
+	SelectSaleDiscountsPostgresQuery = `SELECT "sale_id", "promotion_id", "amount" FROM sale_discounts ORDER BY "id"`
+)
+
+// salePostgresQueries are the queries that price the sales, store their discounts and take their units from the stock.
+var salePostgresQueries = saleQueries{
+	product:        SaleProductPostgresQuery,
+	taxRates:       SelectTaxRatesPostgresQuery,
+	promotions:     SelectPromotionsPostgresQuery,
+	insertDiscount: InsertSaleDiscountPostgresQuery,
+	discounts:      SelectSaleDiscountsPostgresQuery,
+	stock:          stockPostgresQueries,
+}
+
+// NewSalesPostgres creates new postgres repository for sale entity.
+func NewSalesPostgres(db *sql.DB) *SalesPostgres {
+	return &SalesPostgres{db}
+}
+
+// SalesPostgres is the Postgres repository implementation for sale entity.
+type SalesPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all sales from the database.
+func (r *SalesPostgres) FindAll(ctx context.Context) (s []internal.Sale, err error) {
+	defer observe(ctx, "sales.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, `SELECT "id", "quantity", "product_id", "invoice_id", "warehouse", "unit_price", "tax_rate", "discount" FROM sales`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var sa internal.Sale
+		// scan the row into the sale
+		err := rows.Scan(&sa.Id, &sa.Quantity, &sa.ProductId, &sa.InvoiceId, &sa.Warehouse, &sa.UnitPrice, &sa.TaxRate, &sa.Discount)
+		if err != nil {
+			return nil, err
+		}
+		// append the sale to the slice
+		s = append(s, sa)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	// attach the discounts of the sales
+	err = attachSaleDiscounts(ctx, r.db, salePostgresQueries, s)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the sale into the database.
+func (r *SalesPostgres) Save(ctx context.Context, s *internal.Sale) (err error) {
+	defer observe(ctx, "sales.Save", time.Now(), &err)
+
+	// default the warehouse, as the column does
+	(*s).Warehouse = defaultWarehouse((*s).Warehouse)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product is in the currency of the invoice and take its price, tax rate and discounts
+		err = priceSale(ctx, tx, salePostgresQueries, s)
+		if err != nil {
+			return err
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO sales ("quantity", "product_id", "invoice_id", "warehouse", "unit_price", "tax_rate", "discount") VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING "id"`,
+			(*s).Quantity, (*s).ProductId, (*s).InvoiceId, (*s).Warehouse, (*s).UnitPrice, (*s).TaxRate, (*s).Discount,
+		).Scan(&(*s).Id)
+		if err != nil {
+			return err
+		}
+
+		// insert the discounts
+		err = insertSaleDiscounts(ctx, tx, salePostgresQueries, *s)
+		if err != nil {
+			return err
+		}
+
+		// take the units from the stock of the warehouse
+		err = takeSaleStock(ctx, tx, salePostgresQueries.stock, *s)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntitySales, (*s).Id, nil, *s)
+	})
+	return
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SaleProductSQLiteQuery         = `SELECT p."price", p."currency", p."tax_category", p."archived", i."currency", i."datetime", c."condition" FROM products AS p, invoices AS i, customers AS c WHERE p."id" = ? AND i."id" = ? AND c."id" = i."customer_id"`
+	InsertSaleDiscountSQLiteQuery  = `INSERT INTO sale_discounts ("sale_id", "promotion_id", "amount") VALUES (?, ?, ?)`
+	SelectSaleDiscountsSQLiteQuery = `SELECT "sale_id", "promotion_id", "amount" FROM sale_discounts ORDER BY "id"`
+)
+
+// saleSQLiteQueries are the queries that price the sales, store their discounts and take their units from the stock.
+var saleSQLiteQueries = saleQueries{
+	product:        SaleProductSQLiteQuery,
+	taxRates:       SelectTaxRatesSQLiteQuery,
+	promotions:     SelectPromotionsSQLiteQuery,
+	insertDiscount: InsertSaleDiscountSQLiteQuery,
+	discounts:      SelectSaleDiscountsSQLiteQuery,
+	stock:          stockSQLiteQueries,
+}
+
+// NewSalesSQLite creates new sqlite repository for sale entity.
+func NewSalesSQLite(db *sql.DB) *SalesSQLite {
+	return &SalesSQLite{db}
+}
+
+// SalesSQLite is the SQLite repository implementation for sale entity.
+type SalesSQLite struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all sales from the database.
+func (r *SalesSQLite) FindAll(ctx context.Context) (s []internal.Sale, err error) {
+	defer observe(ctx, "sales.FindAll", time.Now(), &err)
+
+	// execute the query
+	rows, err := r.db.QueryContext(ctx, `SELECT "id", "quantity", "product_id", "invoice_id", "warehouse", "unit_price", "tax_rate", "discount" FROM sales`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// iterate over the rows
+	for rows.Next() {
+		var sa internal.Sale
+		// scan the row into the sale
+		err := rows.Scan(&sa.Id, &sa.Quantity, &sa.ProductId, &sa.InvoiceId, &sa.Warehouse, &sa.UnitPrice, &sa.TaxRate, &sa.Discount)
+		if err != nil {
+			return nil, err
+		}
+		// append the sale to the slice
+		s = append(s, sa)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	// attach the discounts of the sales
+	err = attachSaleDiscounts(ctx, r.db, saleSQLiteQueries, s)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Save saves the sale into the database.
+func (r *SalesSQLite) Save(ctx context.Context, s *internal.Sale) (err error) {
+	defer observe(ctx, "sales.Save", time.Now(), &err)
+
+	// default the warehouse, as the column does
+	(*s).Warehouse = defaultWarehouse((*s).Warehouse)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product is in the currency of the invoice and take its price, tax rate and discounts
+		err = priceSale(ctx, tx, saleSQLiteQueries, s)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO sales ("quantity", "product_id", "invoice_id", "warehouse", "unit_price", "tax_rate", "discount") VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			(*s).Quantity, (*s).ProductId, (*s).InvoiceId, (*s).Warehouse, (*s).UnitPrice, (*s).TaxRate, (*s).Discount,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*s).Id = int(id)
+
+		// insert the discounts
+		err = insertSaleDiscounts(ctx, tx, saleSQLiteQueries, *s)
+		if err != nil {
+			return err
+		}
+
+		// take the units from the stock of the warehouse
+		err = takeSaleStock(ctx, tx, saleSQLiteQueries.stock, *s)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordSQLiteQuery, AuditEntitySales, (*s).Id, nil, *s)
+	})
+	return
+}
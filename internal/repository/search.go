@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+
+	"app/internal"
+)
+
+// searchCustomers ranks the customers c matching the text of q by internal.MatchScore, ties by id,
+// and returns the page of q. It is the search of the backends without a full-text index.
+func searchCustomers(c []internal.Customer, q internal.SearchQuery) (m internal.CustomerMatches) {
+	terms := q.Terms()
+
+	var matches []internal.CustomerMatch
+	for _, v := range c {
+		if score, ok := internal.MatchScore(terms, v.FirstName, v.LastName); ok {
+			matches = append(matches, internal.CustomerMatch{Customer: v, Score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Id < matches[j].Id
+	})
+
+	m.Total = len(matches)
+	m.Matches = append([]internal.CustomerMatch{}, searchPage(matches, q)...)
+	return
+}
+
+// searchProducts ranks the products p matching the text of q by internal.MatchScore, ties by id,
+// and returns the page of q. It is the search of the backends without a full-text index.
+func searchProducts(p []internal.Product, q internal.SearchQuery) (m internal.ProductMatches) {
+	terms := q.Terms()
+
+	var matches []internal.ProductMatch
+	for _, v := range p {
+		if score, ok := internal.MatchScore(terms, v.Description); ok {
+			matches = append(matches, internal.ProductMatch{Product: v, Score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Id < matches[j].Id
+	})
+
+	m.Total = len(matches)
+	m.Matches = append([]internal.ProductMatch{}, searchPage(matches, q)...)
+	return
+}
+
+// searchPage returns the items of the page of q.
+func searchPage[T any](items []T, q internal.SearchQuery) []T {
+	if q.Offset >= len(items) {
+		return nil
+	}
+	items = items[q.Offset:]
+	if q.Limit < len(items) {
+		items = items[:q.Limit]
+	}
+	return items
+}
+
+// The condition that the term, its only placeholder, sounds like a word of the text between soundsLikeWordPrefix
+// and soundsLikeWordSuffix: the text is split into words on anything but letters and digits, as internal.MatchScore
+// splits it, and every word is compared by its SOUNDEX. JSON_TABLE and REGEXP_REPLACE need mysql 8.0.4 or later.
+const (
+	soundsLikeWordPrefix = "EXISTS (SELECT 1 FROM JSON_TABLE(CONCAT('[\"', REPLACE(TRIM(REGEXP_REPLACE("
+	soundsLikeWordSuffix = ", '[^[:alnum:]]+', ' ')), ' ', '\",\"'), '\"]'), '$[*]' COLUMNS (`word` varchar(100) PATH '$')) AS w " +
+		"WHERE SOUNDEX(w.`word`) = SOUNDEX(?))"
+)
+
+// fullTextSearch is the search of the backends with a mysql full-text index. As internal.MatchScore, every term
+// must match a word: as its prefix in the full-text index, or, for the terms of 4 letters or more, by its sound,
+// so a typo still finds it.
+type fullTextSearch struct {
+	// columns are the columns of the full-text index.
+	columns string
+	// soundsLike is the condition that the term, its only placeholder, sounds like a word of the columns.
+	soundsLike string
+}
+
+// condition returns the condition that every one of the terms matches, and its arguments.
+func (s fullTextSearch) condition(terms []string) (cond string, args []any) {
+	conds := make([]string, len(terms))
+	for ix, t := range terms {
+		conds[ix] = "MATCH(" + s.columns + ") AGAINST (? IN BOOLEAN MODE)"
+		args = append(args, "+"+t+"*")
+		if len([]rune(t)) >= 4 {
+			conds[ix] = "(" + conds[ix] + " OR " + s.soundsLike + ")"
+			args = append(args, t)
+		}
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// score returns the score of the terms, and its arguments. A term found in the full-text index scores 1 plus
+// its relevance, and a term only found by its sound 0.5, so a typo ranks below the words spelled right.
+func (s fullTextSearch) score(terms []string) (score string, args []any) {
+	match := "MATCH(" + s.columns + ") AGAINST (? IN BOOLEAN MODE)"
+	scores := make([]string, len(terms))
+	for ix, t := range terms {
+		scores[ix] = "IF(" + match + ", 1 + " + match + ", 0.5)"
+		args = append(args, "+"+t+"*", "+"+t+"*")
+	}
+	return strings.Join(scores, " + "), args
+}
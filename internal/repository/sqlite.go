@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"database/sql"
+	"net/url"
+
+	_ "modernc.org/sqlite"
+)
+
+var (
+	// SQLiteMigrations are the migrations of the sqlite schema.
+	SQLiteMigrations = []Migration{
+		{
+			Version:     1,
+			Description: "create customers, invoices, products and sales",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS customers (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"first_name" VARCHAR(45) DEFAULT NULL,
+					"last_name" VARCHAR(45) DEFAULT NULL,
+					"condition" TINYINT DEFAULT NULL
+				)`,
+				`CREATE TABLE IF NOT EXISTS invoices (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"datetime" TEXT DEFAULT NULL,
+					"customer_id" INTEGER DEFAULT NULL REFERENCES customers ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"total" REAL DEFAULT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_invoices_customer_id ON invoices ("customer_id")`,
+				`CREATE TABLE IF NOT EXISTS products (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"description" VARCHAR(100) DEFAULT NULL,
+					"price" REAL DEFAULT NULL
+				)`,
+				`CREATE TABLE IF NOT EXISTS sales (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"quantity" INTEGER DEFAULT NULL,
+					"invoice_id" INTEGER DEFAULT NULL REFERENCES invoices ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"product_id" INTEGER DEFAULT NULL REFERENCES products ("id") ON DELETE CASCADE ON UPDATE CASCADE
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_sales_invoice_id ON sales ("invoice_id")`,
+				`CREATE INDEX IF NOT EXISTS idx_sales_product_id ON sales ("product_id")`,
+			},
+		},
+		{
+			Version:     2,
+			Description: "create audit_records",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS audit_records (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"actor" VARCHAR(100) NOT NULL,
+					"timestamp" TEXT NOT NULL,
+					"entity" VARCHAR(45) NOT NULL,
+					"entity_id" INTEGER NOT NULL,
+					"action" VARCHAR(10) NOT NULL,
+					"before" TEXT DEFAULT NULL,
+					"after" TEXT DEFAULT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_audit_records_entity ON audit_records ("entity", "entity_id")`,
+				`CREATE INDEX IF NOT EXISTS idx_audit_records_timestamp ON audit_records ("timestamp")`,
+			},
+		},
+		{
+			// sqlite has no decimal type: DECIMAL columns store numbers, which internal.Money rounds to the cent when scanned
+			Version:     3,
+			Description: "store amounts of money as DECIMAL(12,2)",
+			Statements: []string{
+				`ALTER TABLE invoices RENAME COLUMN "total" TO "total_real"`,
+				`ALTER TABLE invoices ADD COLUMN "total" DECIMAL(12,2) DEFAULT NULL`,
+				`UPDATE invoices SET "total" = ROUND("total_real", 2)`,
+				`ALTER TABLE invoices DROP COLUMN "total_real"`,
+				`ALTER TABLE products RENAME COLUMN "price" TO "price_real"`,
+				`ALTER TABLE products ADD COLUMN "price" DECIMAL(12,2) DEFAULT NULL`,
+				`UPDATE products SET "price" = ROUND("price_real", 2)`,
+				`ALTER TABLE products DROP COLUMN "price_real"`,
+			},
+		},
+		{
+			// dates are TEXT like the invoice datetimes, so they compare as text
+			Version:     4,
+			Description: "add the currency of products and invoices and create exchange_rates",
+			Statements: []string{
+				`ALTER TABLE products ADD COLUMN "currency" CHAR(3) NOT NULL DEFAULT 'USD'`,
+				`ALTER TABLE invoices ADD COLUMN "currency" CHAR(3) NOT NULL DEFAULT 'USD'`,
+				`CREATE TABLE IF NOT EXISTS exchange_rates (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"from_currency" CHAR(3) NOT NULL,
+					"to_currency" CHAR(3) NOT NULL,
+					"rate" DECIMAL(18,6) NOT NULL,
+					"effective_date" TEXT NOT NULL,
+					UNIQUE ("from_currency", "to_currency", "effective_date")
+				)`,
+			},
+		},
+		{
+			// the prices of the existing products are valid from the migration, and the existing sales at their current price
+			Version:     5,
+			Description: "create product_prices and store the unit price of sales",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS product_prices (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"product_id" INTEGER NOT NULL REFERENCES products ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"price" DECIMAL(12,2) DEFAULT NULL,
+					"currency" CHAR(3) NOT NULL DEFAULT 'USD',
+					"valid_from" TEXT NOT NULL,
+					"valid_to" TEXT DEFAULT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_product_prices_product_id ON product_prices ("product_id")`,
+				`INSERT INTO product_prices ("product_id", "price", "currency", "valid_from") SELECT "id", "price", "currency", strftime('%Y-%m-%d %H:%M:%S', 'now') FROM products`,
+				`ALTER TABLE sales ADD COLUMN "unit_price" DECIMAL(12,2) DEFAULT NULL`,
+				`UPDATE sales SET "unit_price" = (SELECT p."price" FROM products AS p WHERE p."id" = sales."product_id")`,
+			},
+		},
+		{
+			// the existing totals are kept as untaxed subtotals, and the existing sales as untaxed
+			Version:     6,
+			Description: "add tax categories, tax rates and the taxes of sales and invoices",
+			Statements: []string{
+				`ALTER TABLE products ADD COLUMN "tax_category" VARCHAR(45) NOT NULL DEFAULT 'standard'`,
+				`CREATE TABLE IF NOT EXISTS tax_rates (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"category" VARCHAR(45) NOT NULL DEFAULT '',
+					"customer_condition" TINYINT DEFAULT NULL,
+					"rate" DECIMAL(18,6) NOT NULL
+				)`,
+				`ALTER TABLE sales ADD COLUMN "tax_rate" DECIMAL(18,6) NOT NULL DEFAULT 0`,
+				`ALTER TABLE invoices ADD COLUMN "subtotal" DECIMAL(12,2) DEFAULT NULL`,
+				`ALTER TABLE invoices ADD COLUMN "tax" DECIMAL(12,2) DEFAULT NULL`,
+				`UPDATE invoices SET "subtotal" = "total", "tax" = 0`,
+			},
+		},
+		{
+			Version:     7,
+			Description: "create promotions and store the discounts of sales and invoices",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS promotions (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"name" VARCHAR(100) NOT NULL,
+					"kind" VARCHAR(20) NOT NULL,
+					"product_id" INTEGER DEFAULT NULL REFERENCES products ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"customer_condition" TINYINT DEFAULT NULL,
+					"percent" DECIMAL(18,6) NOT NULL DEFAULT 0,
+					"buy_quantity" INTEGER NOT NULL DEFAULT 0,
+					"get_quantity" INTEGER NOT NULL DEFAULT 0,
+					"valid_from" TEXT NOT NULL,
+					"valid_to" TEXT DEFAULT NULL,
+					"stackable" BOOLEAN NOT NULL DEFAULT FALSE
+				)`,
+				`CREATE TABLE IF NOT EXISTS sale_discounts (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"sale_id" INTEGER NOT NULL REFERENCES sales ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"promotion_id" INTEGER NOT NULL REFERENCES promotions ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"amount" DECIMAL(12,2) NOT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_sale_discounts_sale_id ON sale_discounts ("sale_id")`,
+				`ALTER TABLE sales ADD COLUMN "discount" DECIMAL(12,2) NOT NULL DEFAULT 0`,
+				`ALTER TABLE invoices ADD COLUMN "discount" DECIMAL(12,2) NOT NULL DEFAULT 0`,
+			},
+		},
+		{
+			// the stock of the existing products is not tracked until their first receipt or adjustment
+			Version:     8,
+			Description: "create stocks and stock_movements and store the warehouse of sales",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS stocks (
+					"product_id" INTEGER NOT NULL REFERENCES products ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"warehouse" VARCHAR(45) NOT NULL,
+					"quantity" INTEGER NOT NULL CHECK ("quantity" >= 0),
+					PRIMARY KEY ("product_id", "warehouse")
+				)`,
+				`CREATE TABLE IF NOT EXISTS stock_movements (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"product_id" INTEGER NOT NULL REFERENCES products ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"warehouse" VARCHAR(45) NOT NULL,
+					"kind" VARCHAR(20) NOT NULL,
+					"quantity" INTEGER NOT NULL,
+					"sale_id" INTEGER DEFAULT NULL REFERENCES sales ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"datetime" TEXT NOT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements ("product_id")`,
+				`ALTER TABLE sales ADD COLUMN "warehouse" VARCHAR(45) NOT NULL DEFAULT 'main'`,
+			},
+		},
+		{
+			Version:     9,
+			Description: "create stock_alerts and webhook_deliveries",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS stock_alerts (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"date" TEXT NOT NULL,
+					"product_id" INTEGER NOT NULL REFERENCES products ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"stock" INTEGER NOT NULL,
+					"sold" INTEGER NOT NULL,
+					"daily_sales" DECIMAL(12,2) NOT NULL,
+					"days_of_cover" DECIMAL(12,2) NOT NULL,
+					"quantity" INTEGER NOT NULL,
+					UNIQUE ("product_id", "date")
+				)`,
+				`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"event" VARCHAR(45) NOT NULL,
+					"payload" TEXT NOT NULL,
+					"attempts" INTEGER NOT NULL DEFAULT 0,
+					"delivered" BOOLEAN NOT NULL DEFAULT FALSE,
+					"last_error" VARCHAR(255) NOT NULL DEFAULT '',
+					"created_at" TEXT NOT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_delivered ON webhook_deliveries ("delivered", "id")`,
+			},
+		},
+		{
+			// the existing products have no sku nor category, are sold by unit and are not archived
+			Version:     10,
+			Description: "create categories and store the sku, category, unit and archived flag of products",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS categories (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"name" VARCHAR(45) NOT NULL,
+					"parent_id" INTEGER DEFAULT NULL REFERENCES categories ("id") ON DELETE CASCADE ON UPDATE CASCADE
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories ("parent_id")`,
+				`ALTER TABLE products ADD COLUMN "sku" VARCHAR(45) DEFAULT NULL`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products ("sku")`,
+				`ALTER TABLE products ADD COLUMN "category_id" INTEGER DEFAULT NULL REFERENCES categories ("id") ON DELETE SET NULL ON UPDATE CASCADE`,
+				`CREATE INDEX IF NOT EXISTS idx_products_category_id ON products ("category_id")`,
+				`ALTER TABLE products ADD COLUMN "unit" VARCHAR(20) NOT NULL DEFAULT 'unit'`,
+				`ALTER TABLE products ADD COLUMN "archived" BOOLEAN NOT NULL DEFAULT FALSE`,
+			},
+		},
+		{
+			// the existing customers without a condition are inactive, and were created and updated when first invoiced,
+			// or now if never; sqlite cannot change the column of the condition, so its default stays in the repositories
+			Version:     11,
+			Description: "store the email, phone, addresses and creation and update datetimes of customers",
+			Statements: []string{
+				`ALTER TABLE customers ADD COLUMN "email" VARCHAR(100) DEFAULT NULL`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email ON customers ("email")`,
+				`ALTER TABLE customers ADD COLUMN "phone" VARCHAR(20) NOT NULL DEFAULT ''`,
+				`ALTER TABLE customers ADD COLUMN "created_at" TEXT NOT NULL DEFAULT ''`,
+				`ALTER TABLE customers ADD COLUMN "updated_at" TEXT NOT NULL DEFAULT ''`,
+				`UPDATE customers SET "condition" = 0 WHERE "condition" IS NULL`,
+				`UPDATE customers SET "created_at" = COALESCE((SELECT MIN(i."datetime") FROM invoices AS i WHERE i."customer_id" = customers."id"), strftime('%Y-%m-%d %H:%M:%S', 'now'))`,
+				`UPDATE customers SET "updated_at" = "created_at"`,
+				`CREATE TABLE IF NOT EXISTS customer_addresses (
+					"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+					"customer_id" INTEGER NOT NULL REFERENCES customers ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+					"kind" VARCHAR(20) NOT NULL,
+					"line1" VARCHAR(100) NOT NULL,
+					"line2" VARCHAR(100) NOT NULL DEFAULT '',
+					"city" VARCHAR(45) NOT NULL,
+					"state" VARCHAR(45) NOT NULL DEFAULT '',
+					"postal_code" VARCHAR(20) NOT NULL DEFAULT '',
+					"country" CHAR(2) NOT NULL,
+					UNIQUE ("customer_id", "kind")
+				)`,
+			},
+		},
+		{
+			// the root categories have no parent, so the names are unique by the parent or 0
+			Version:     12,
+			Description: "make the names of the categories unique under their parent",
+			Statements: []string{
+				`CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_id_name ON categories (COALESCE("parent_id", 0), "name")`,
+			},
+		},
+	}
+)
+
+// NewSQLiteDB opens the sqlite database stored in path, creating it and migrating its schema if needed.
+// Foreign keys are enforced on every connection.
+func NewSQLiteDB(path string) (db *sql.DB, err error) {
+	// dsn
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	dsn := "file:" + path + "?" + q.Encode()
+
+	// open
+	db, err = sql.Open("sqlite", dsn)
+	if err != nil {
+		return
+	}
+	// - sqlite allows a single writer
+	db.SetMaxOpenConns(1)
+
+	// schema
+	err = Migrate(db, SQLiteMigrations)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return
+}
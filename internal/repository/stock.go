@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"app/internal"
+)
+
+// stockQueries are the queries of a sql database on the stock of the products.
+// The arguments of each query are listed in the order of its placeholders.
+type stockQueries struct {
+	// exists counts the products of an id: id.
+	exists string
+	// tracked counts the warehouses holding the stock of a product: product_id.
+	tracked string
+	// held counts the stock of a product in a warehouse: product_id, warehouse.
+	held string
+	// add adds to the stock of a product in a warehouse, unless it would go below 0: quantity, product_id, warehouse, quantity.
+	add string
+	// upsert adds to the stock of a product in a warehouse, inserting it when the warehouse holds none yet:
+	// product_id, warehouse, quantity.
+	upsert string
+	// insertMovement inserts a movement: product_id, warehouse, kind, quantity, sale_id, datetime.
+	insertMovement string
+	// stock selects the warehouse and quantity of the stock of a product, by warehouse: product_id.
+	stock string
+	// movements selects the id, product_id, warehouse, kind, quantity, sale_id and datetime of the movements
+	// of a product, by id: product_id.
+	movements string
+}
+
+// errStockInsufficient returns the error of the movement m that would take the stock of its warehouse below 0.
+func errStockInsufficient(m internal.StockMovement) error {
+	return fmt.Errorf("%w: product %d in warehouse %s cannot move %d units", internal.ErrStockInsufficient, m.ProductId, m.Warehouse, m.Quantity)
+}
+
+// errSaleQuantity returns the error of the sale movement m that does not take units.
+func errSaleQuantity(m internal.StockMovement) error {
+	return fmt.Errorf("%w: a sale of product %d must take units, not move %d", internal.ErrStockMovementInvalid, m.ProductId, m.Quantity)
+}
+
+// defaultWarehouse returns the warehouse w, internal.WarehouseDefault if empty.
+func defaultWarehouse(w string) string {
+	if w == "" {
+		return internal.WarehouseDefault
+	}
+	return w
+}
+
+// stockSaleId returns the sale of m as stored, NULL if it is not a sale.
+func stockSaleId(m internal.StockMovement) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(m.SaleId), Valid: m.SaleId != 0}
+}
+
+// moveStock adds the quantity of m, negative to take units, to the stock of its product in its warehouse within tx.
+// The units taken are checked not to take the stock below 0 in the same statement, which locks the stock row,
+// so concurrent sales cannot oversell it; the units added are upserted, so concurrent receipts into a warehouse
+// that held none do not collide. A sale of a product whose stock is not tracked leaves it untouched, with tracked false.
+// A sale that does not take units fails with internal.ErrStockMovementInvalid.
+func moveStock(ctx context.Context, tx *sql.Tx, q stockQueries, m internal.StockMovement) (tracked bool, err error) {
+	if m.Kind == internal.StockMovementSale && m.Quantity >= 0 {
+		return false, errSaleQuantity(m)
+	}
+	if m.Quantity == 0 {
+		return true, nil
+	}
+
+	// - units added, in one statement; not by a sale, which must not start tracking the stock
+	if m.Quantity > 0 && m.Kind != internal.StockMovementSale {
+		_, err = tx.ExecContext(ctx, q.upsert, m.ProductId, m.Warehouse, m.Quantity)
+		return true, err
+	}
+
+	// - the warehouse holds enough units
+	res, err := tx.ExecContext(ctx, q.add, m.Quantity, m.ProductId, m.Warehouse, m.Quantity)
+	if err != nil {
+		return
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n > 0 {
+		return true, nil
+	}
+
+	// - the warehouse holds too few units, or none was ever recorded
+	var held int
+	err = tx.QueryRowContext(ctx, q.held, m.ProductId, m.Warehouse).Scan(&held)
+	if err != nil {
+		return
+	}
+	if held > 0 {
+		return true, errStockInsufficient(m)
+	}
+	if m.Kind == internal.StockMovementSale {
+		var warehouses int
+		err = tx.QueryRowContext(ctx, q.tracked, m.ProductId).Scan(&warehouses)
+		if err != nil {
+			return
+		}
+		if warehouses == 0 {
+			return false, nil
+		}
+	}
+	if m.Quantity < 0 {
+		return true, errStockInsufficient(m)
+	}
+	_, err = tx.ExecContext(ctx, q.upsert, m.ProductId, m.Warehouse, m.Quantity)
+	return true, err
+}
+
+// trackStock starts tracking the stock of the new product id within tx, with no units in internal.WarehouseDefault,
+// unless ctx creates products untracked.
+func trackStock(ctx context.Context, tx *sql.Tx, q stockQueries, id int) (err error) {
+	if internal.StockUntracked(ctx) {
+		return
+	}
+	_, err = tx.ExecContext(ctx, q.upsert, id, internal.WarehouseDefault, 0)
+	return
+}
+
+// takeSaleStock takes the units of the saved sale s from the stock of its warehouse within tx, recording the movement.
+func takeSaleStock(ctx context.Context, tx *sql.Tx, q stockQueries, s internal.Sale) (err error) {
+	m := internal.StockMovement{StockMovementAttributes: internal.StockMovementAttributes{
+		ProductId: s.ProductId,
+		Warehouse: s.Warehouse,
+		Kind:      internal.StockMovementSale,
+		Quantity:  -s.Quantity,
+		SaleId:    s.Id,
+		Datetime:  priceDatetime(time.Now()),
+	}}
+	tracked, err := moveStock(ctx, tx, q, m)
+	if err != nil || !tracked {
+		return
+	}
+	_, err = tx.ExecContext(ctx, q.insertMovement, m.ProductId, m.Warehouse, m.Kind, m.Quantity, stockSaleId(m), m.Datetime)
+	return
+}
+
+// checkStockProduct checks that the product id exists.
+func checkStockProduct(ctx context.Context, q rowQuerier, query string, id int) (err error) {
+	var n int
+	err = q.QueryRowContext(ctx, query, id).Scan(&n)
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		return errProductNotFound(id)
+	}
+	return
+}
+
+// queryProductStock returns the stock of the product id.
+func queryProductStock(ctx context.Context, db *sql.DB, q stockQueries, id int) (s internal.ProductStock, err error) {
+	err = checkStockProduct(ctx, db, q.exists, id)
+	if err != nil {
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, q.stock, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	s = internal.ProductStock{ProductId: id, Warehouses: []internal.WarehouseStock{}}
+	for rows.Next() {
+		var w internal.WarehouseStock
+		err = rows.Scan(&w.Warehouse, &w.Quantity)
+		if err != nil {
+			return
+		}
+		s.Warehouses = append(s.Warehouses, w)
+		s.Quantity += w.Quantity
+	}
+	err = rows.Err()
+	s.Tracked = len(s.Warehouses) > 0
+	return
+}
+
+// queryStockMovements returns the stock movements of the product id, oldest first.
+func queryStockMovements(ctx context.Context, db *sql.DB, q stockQueries, id int) (m []internal.StockMovement, err error) {
+	err = checkStockProduct(ctx, db, q.exists, id)
+	if err != nil {
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, q.movements, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	m = []internal.StockMovement{}
+	for rows.Next() {
+		var (
+			mv     internal.StockMovement
+			saleId sql.NullInt64
+		)
+		err = rows.Scan(&mv.Id, &mv.ProductId, &mv.Warehouse, &mv.Kind, &mv.Quantity, &saleId, &mv.Datetime)
+		if err != nil {
+			return
+		}
+		mv.SaleId = int(saleId.Int64)
+		m = append(m, mv)
+	}
+	err = rows.Err()
+	return
+}
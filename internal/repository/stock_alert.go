@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal"
+)
+
+// errStockAlertExists returns the error of an alert of a product already alerted on its date.
+func errStockAlertExists(a internal.StockAlert) error {
+	return fmt.Errorf("%w: product %d on %s", internal.ErrStockAlertExists, a.ProductId, a.Date)
+}
+
+// queryStockSales returns the stock sales selected by query, with args.
+// The columns of query are product id, description, stock and sold.
+func queryStockSales(ctx context.Context, q querier, query string, args ...any) (s []internal.StockSales, err error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	s = []internal.StockSales{}
+	for rows.Next() {
+		var v internal.StockSales
+		err = rows.Scan(&v.ProductId, &v.Description, &v.Stock, &v.Sold)
+		if err != nil {
+			return
+		}
+		s = append(s, v)
+	}
+	err = rows.Err()
+	return
+}
+
+// queryStockAlerts returns the stock alerts selected by query.
+// The columns of query are id, date, product_id, description, stock, sold, daily_sales, days_of_cover and quantity.
+func queryStockAlerts(ctx context.Context, q querier, query string) (a []internal.StockAlert, err error) {
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	a = []internal.StockAlert{}
+	for rows.Next() {
+		var al internal.StockAlert
+		err = rows.Scan(&al.Id, &al.Date, &al.ProductId, &al.Description, &al.Stock, &al.Sold, &al.DailySales, &al.DaysOfCover, &al.Quantity)
+		if err != nil {
+			return
+		}
+		a = append(a, al)
+	}
+	err = rows.Err()
+	return
+}
+
+// queryWebhookDeliveries returns the webhook deliveries selected by query, with args.
+// The columns of query are id, event, payload, attempts, delivered and last_error.
+func queryWebhookDeliveries(ctx context.Context, q querier, query string, args ...any) (d []internal.WebhookDelivery, err error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	d = []internal.WebhookDelivery{}
+	for rows.Next() {
+		var wd internal.WebhookDelivery
+		err = rows.Scan(&wd.Id, &wd.Event, &wd.Payload, &wd.Attempts, &wd.Delivered, &wd.LastError)
+		if err != nil {
+			return
+		}
+		d = append(d, wd)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewStockAlertsMemory creates new memory repository for stock alert entity.
+func NewStockAlertsMemory(db *MemoryDB) *StockAlertsMemory {
+	return &StockAlertsMemory{db}
+}
+
+// StockAlertsMemory is the memory repository implementation for stock alert entity.
+type StockAlertsMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all stock alerts from the database, oldest first.
+func (r *StockAlertsMemory) FindAll(ctx context.Context) (a []internal.StockAlert, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	a = []internal.StockAlert{}
+	for _, id := range sortedKeys(r.db.stockAlerts) {
+		al := r.db.stockAlerts[id]
+		// - the description is the current one, as the join of the sql repositories
+		al.Description = r.db.products[al.ProductId].Description
+		a = append(a, al)
+	}
+
+	return
+}
+
+// Save saves the stock alert into the database, queueing the webhook delivery d if not nil.
+func (r *StockAlertsMemory) Save(ctx context.Context, a *internal.StockAlert, d *internal.WebhookDelivery) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// check the product exists and was not alerted on the same date already
+	if _, ok := r.db.products[(*a).ProductId]; !ok {
+		return ErrForeignKeyViolation
+	}
+	for _, al := range r.db.stockAlerts {
+		if al.ProductId == (*a).ProductId && al.Date == (*a).Date {
+			return errStockAlertExists(*a)
+		}
+	}
+
+	// set the id
+	r.db.lastStockAlertId++
+	(*a).Id = r.db.lastStockAlertId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityStockAlerts, (*a).Id, nil, *a)
+	if err != nil {
+		r.db.lastStockAlertId--
+		return
+	}
+
+	// insert the alert and queue its delivery
+	r.db.stockAlerts[(*a).Id] = *a
+	if d != nil {
+		r.db.lastWebhookId++
+		(*d).Id = r.db.lastWebhookId
+		r.db.webhooks[(*d).Id] = *d
+	}
+
+	return
+}
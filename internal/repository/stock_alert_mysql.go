@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectStockAlertsQuery = "SELECT a.`id`, a.`date`, a.`product_id`, p.`description`, a.`stock`, a.`sold`, a.`daily_sales`, a.`days_of_cover`, a.`quantity` FROM stock_alerts AS a INNER JOIN products AS p ON p.`id` = a.`product_id` ORDER BY a.`id`"
+	ExistsStockAlertQuery  = "SELECT COUNT(*) FROM stock_alerts WHERE `product_id` = ? AND `date` = ?"
+)
+
+// NewStockAlertsMySQL creates new mysql repository for stock alert entity.
+func NewStockAlertsMySQL(db *sql.DB) *StockAlertsMySQL {
+	return &StockAlertsMySQL{db}
+}
+
+// StockAlertsMySQL is the MySQL repository implementation for stock alert entity.
+type StockAlertsMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all stock alerts from the database, oldest first.
+func (r *StockAlertsMySQL) FindAll(ctx context.Context) (a []internal.StockAlert, err error) {
+	defer observe(ctx, "stock_alerts.FindAll", time.Now(), &err)
+
+	a, err = queryStockAlerts(ctx, r.db, SelectStockAlertsQuery)
+	return
+}
+
+// Save saves the stock alert into the database, queueing the webhook delivery d if not nil.
+func (r *StockAlertsMySQL) Save(ctx context.Context, a *internal.StockAlert, d *internal.WebhookDelivery) (err error) {
+	defer observe(ctx, "stock_alerts.Save", time.Now(), &err)
+
+	// insert the record, its delivery and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product was not alerted on the same date already
+		var n int
+		err = tx.QueryRowContext(ctx, ExistsStockAlertQuery, (*a).ProductId, (*a).Date).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return errStockAlertExists(*a)
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO stock_alerts (`date`, `product_id`, `stock`, `sold`, `daily_sales`, `days_of_cover`, `quantity`) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			(*a).Date, (*a).ProductId, (*a).Stock, (*a).Sold, (*a).DailySales, (*a).DaysOfCover, (*a).Quantity,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*a).Id = int(id)
+
+		// queue the delivery
+		if d != nil {
+			res, err = tx.ExecContext(ctx,
+				"INSERT INTO webhook_deliveries (`event`, `payload`, `created_at`) VALUES (?, ?, ?)",
+				(*d).Event, string((*d).Payload), priceDatetime(time.Now()),
+			)
+			if err != nil {
+				return err
+			}
+			id, err = res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			(*d).Id = int(id)
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityStockAlerts, (*a).Id, nil, *a)
+	})
+	return
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectStockAlertsPostgresQuery = `SELECT a."id", to_char(a."date", 'YYYY-MM-DD'), a."product_id", p."description", a."stock", a."sold", a."daily_sales", a."days_of_cover", a."quantity" FROM stock_alerts AS a INNER JOIN products AS p ON p."id" = a."product_id" ORDER BY a."id"`
+	ExistsStockAlertPostgresQuery  = `SELECT COUNT(*) FROM stock_alerts WHERE "product_id" = $1 AND "date" = $2`
+)
+
+// NewStockAlertsPostgres creates new postgres repository for stock alert entity.
+func NewStockAlertsPostgres(db *sql.DB) *StockAlertsPostgres {
+	return &StockAlertsPostgres{db}
+}
+
+// StockAlertsPostgres is the Postgres repository implementation for stock alert entity.
+type StockAlertsPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all stock alerts from the database, oldest first.
+func (r *StockAlertsPostgres) FindAll(ctx context.Context) (a []internal.StockAlert, err error) {
+	defer observe(ctx, "stock_alerts.FindAll", time.Now(), &err)
+
+	a, err = queryStockAlerts(ctx, r.db, SelectStockAlertsPostgresQuery)
+	return
+}
+
+// Save saves the stock alert into the database, queueing the webhook delivery d if not nil.
+func (r *StockAlertsPostgres) Save(ctx context.Context, a *internal.StockAlert, d *internal.WebhookDelivery) (err error) {
+	defer observe(ctx, "stock_alerts.Save", time.Now(), &err)
+
+	// insert the record, its delivery and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product was not alerted on the same date already
+		var n int
+		err = tx.QueryRowContext(ctx, ExistsStockAlertPostgresQuery, (*a).ProductId, (*a).Date).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return errStockAlertExists(*a)
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO stock_alerts ("date", "product_id", "stock", "sold", "daily_sales", "days_of_cover", "quantity") VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING "id"`,
+			(*a).Date, (*a).ProductId, (*a).Stock, (*a).Sold, (*a).DailySales, (*a).DaysOfCover, (*a).Quantity,
+		).Scan(&(*a).Id)
+		if err != nil {
+			return err
+		}
+
+		// queue the delivery
+		if d != nil {
+			err = tx.QueryRowContext(ctx,
+				`INSERT INTO webhook_deliveries ("event", "payload", "created_at") VALUES ($1, $2, $3) RETURNING "id"`,
+				(*d).Event, string((*d).Payload), priceDatetime(time.Now()),
+			).Scan(&(*d).Id)
+			if err != nil {
+				return err
+			}
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityStockAlerts, (*a).Id, nil, *a)
+	})
+	return
+}
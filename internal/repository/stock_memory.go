@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"app/internal"
+)
+
+// stockKey is the key of the stocks table, a product in a warehouse.
+type stockKey struct {
+	// productId is the product id of the stock.
+	productId int
+	// warehouse is the warehouse holding the stock.
+	warehouse string
+}
+
+// NewStocksMemory creates new memory repository for stock entity.
+func NewStocksMemory(db *MemoryDB) *StocksMemory {
+	return &StocksMemory{db}
+}
+
+// StocksMemory is the memory repository implementation for stock entity.
+type StocksMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindByProduct returns the stock of the product id.
+func (r *StocksMemory) FindByProduct(ctx context.Context, id int) (s internal.ProductStock, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, ok := r.db.products[id]; !ok {
+		return s, errProductNotFound(id)
+	}
+
+	s = internal.ProductStock{ProductId: id, Warehouses: []internal.WarehouseStock{}}
+	for k, q := range r.db.stocks {
+		if k.productId == id {
+			s.Warehouses = append(s.Warehouses, internal.WarehouseStock{Warehouse: k.warehouse, Quantity: q})
+			s.Quantity += q
+		}
+	}
+	sort.Slice(s.Warehouses, func(i, j int) bool { return s.Warehouses[i].Warehouse < s.Warehouses[j].Warehouse })
+	s.Tracked = len(s.Warehouses) > 0
+
+	return
+}
+
+// FindMovements returns the stock movements of the product id, oldest first.
+func (r *StocksMemory) FindMovements(ctx context.Context, id int) (m []internal.StockMovement, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, ok := r.db.products[id]; !ok {
+		return nil, errProductNotFound(id)
+	}
+
+	m = []internal.StockMovement{}
+	for _, mvId := range sortedKeys(r.db.stockMovements) {
+		if mv := r.db.stockMovements[mvId]; mv.ProductId == id {
+			m = append(m, mv)
+		}
+	}
+
+	return
+}
+
+// SaveMovement saves the receipt or adjustment into the database, changing the stock of its warehouse.
+func (r *StocksMemory) SaveMovement(ctx context.Context, m *internal.StockMovement) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// default the warehouse, as the column does, and date the movement
+	(*m).Warehouse = defaultWarehouse((*m).Warehouse)
+	(*m).Datetime = priceDatetime(time.Now())
+
+	// check the product exists and the warehouse holds the units taken
+	if _, ok := r.db.products[(*m).ProductId]; !ok {
+		return errProductNotFound((*m).ProductId)
+	}
+	_, err = r.db.checkStock(*m)
+	if err != nil {
+		return
+	}
+
+	// set the id
+	r.db.lastStockMovementId++
+	(*m).Id = r.db.lastStockMovementId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityStockMovements, (*m).Id, nil, *m)
+	if err != nil {
+		r.db.lastStockMovementId--
+		return
+	}
+
+	// change the stock and insert the movement
+	r.db.stocks[stockKey{(*m).ProductId, (*m).Warehouse}] += (*m).Quantity
+	r.db.stockMovements[(*m).Id] = *m
+
+	return
+}
+
+// FindStockSales returns the stock of the tracked products with the units sold on the invoices dated from since.
+func (r *StocksMemory) FindStockSales(ctx context.Context, since string) (s []internal.StockSales, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	// - stock of the tracked products
+	stock := make(map[int]int)
+	for k, q := range r.db.stocks {
+		stock[k.productId] += q
+	}
+
+	// - units sold on the invoices of the window
+	sold := make(map[int]int)
+	for _, sa := range r.db.sales {
+		if r.db.invoices[sa.InvoiceId].Datetime >= since {
+			sold[sa.ProductId] += sa.Quantity
+		}
+	}
+
+	s = []internal.StockSales{}
+	for _, id := range sortedKeys(stock) {
+		s = append(s, internal.StockSales{ProductId: id, Description: r.db.products[id].Description, Stock: stock[id], Sold: sold[id]})
+	}
+
+	return
+}
+
+// checkStock checks the movement m does not take the stock of its warehouse below 0, as moveStock does.
+// A sale of a product whose stock is not tracked passes with tracked false. The caller must hold the lock.
+func (db *MemoryDB) checkStock(m internal.StockMovement) (tracked bool, err error) {
+	if m.Kind == internal.StockMovementSale && m.Quantity >= 0 {
+		return false, errSaleQuantity(m)
+	}
+
+	held, ok := db.stocks[stockKey{m.ProductId, m.Warehouse}]
+	if ok || m.Kind != internal.StockMovementSale {
+		tracked = true
+	} else {
+		for k := range db.stocks {
+			if k.productId == m.ProductId {
+				tracked = true
+				break
+			}
+		}
+	}
+	if tracked && held+m.Quantity < 0 {
+		return tracked, errStockInsufficient(m)
+	}
+	return
+}
+
+// takeSaleStock takes the units of the saved sale s from the stock of its warehouse, recording the movement,
+// if the stock of its product is tracked. checkStock must have passed. The caller must hold the lock.
+func (db *MemoryDB) takeSaleStock(s internal.Sale, tracked bool) {
+	if !tracked {
+		return
+	}
+
+	db.stocks[stockKey{s.ProductId, s.Warehouse}] -= s.Quantity
+	db.lastStockMovementId++
+	db.stockMovements[db.lastStockMovementId] = internal.StockMovement{
+		Id: db.lastStockMovementId,
+		StockMovementAttributes: internal.StockMovementAttributes{
+			ProductId: s.ProductId,
+			Warehouse: s.Warehouse,
+			Kind:      internal.StockMovementSale,
+			Quantity:  -s.Quantity,
+			SaleId:    s.Id,
+			Datetime:  priceDatetime(time.Now()),
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	TrackedStockQuery         = "SELECT COUNT(*) FROM stocks WHERE `product_id` = ?"
+	HeldStockQuery            = "SELECT COUNT(*) FROM stocks WHERE `product_id` = ? AND `warehouse` = ?"
+	AddStockQuery             = "UPDATE stocks SET `quantity` = `quantity` + ? WHERE `product_id` = ? AND `warehouse` = ? AND `quantity` + ? >= 0"
+	UpsertStockQuery          = "INSERT INTO stocks (`product_id`, `warehouse`, `quantity`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `quantity` = `quantity` + VALUES(`quantity`)"
+	InsertStockMovementQuery  = "INSERT INTO stock_movements (`product_id`, `warehouse`, `kind`, `quantity`, `sale_id`, `datetime`) VALUES (?, ?, ?, ?, ?, ?)"
+	SelectStockQuery          = "SELECT `warehouse`, `quantity` FROM stocks WHERE `product_id` = ? ORDER BY `warehouse`"
+	SelectStockMovementsQuery = "SELECT `id`, `product_id`, `warehouse`, `kind`, `quantity`, `sale_id`, `datetime` FROM stock_movements WHERE `product_id` = ? ORDER BY `id`"
+	// StockSalesQuery selects the stock of the tracked products and the units sold on the invoices dated from the placeholder.
+	StockSalesQuery = "SELECT p.`id`, p.`description`, st.`quantity`, COALESCE(v.`sold`, 0) FROM products AS p INNER JOIN (SELECT `product_id`, SUM(`quantity`) AS `quantity` FROM stocks GROUP BY `product_id`) AS st ON st.`product_id` = p.`id` LEFT JOIN (SELECT s.`product_id`, SUM(s.`quantity`) AS `sold` FROM sales AS s INNER JOIN invoices AS i ON i.`id` = s.`invoice_id` WHERE i.`datetime` >= ? GROUP BY s.`product_id`) AS v ON v.`product_id` = p.`id` ORDER BY p.`id`"
+)
+
+// stockMySQLQueries are the queries of the stock of the products.
+var stockMySQLQueries = stockQueries{
+	exists:         ExistsProductQuery,
+	tracked:        TrackedStockQuery,
+	held:           HeldStockQuery,
+	add:            AddStockQuery,
+	upsert:         UpsertStockQuery,
+	insertMovement: InsertStockMovementQuery,
+	stock:          SelectStockQuery,
+	movements:      SelectStockMovementsQuery,
+}
+
+// NewStocksMySQL creates new mysql repository for stock entity.
+func NewStocksMySQL(db *sql.DB) *StocksMySQL {
+	return &StocksMySQL{db}
+}
+
+// StocksMySQL is the MySQL repository implementation for stock entity.
+type StocksMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindByProduct returns the stock of the product id.
+func (r *StocksMySQL) FindByProduct(ctx context.Context, id int) (s internal.ProductStock, err error) {
+	defer observe(ctx, "stocks.FindByProduct", time.Now(), &err)
+
+	s, err = queryProductStock(ctx, r.db, stockMySQLQueries, id)
+	return
+}
+
+// FindMovements returns the stock movements of the product id, oldest first.
+func (r *StocksMySQL) FindMovements(ctx context.Context, id int) (m []internal.StockMovement, err error) {
+	defer observe(ctx, "stocks.FindMovements", time.Now(), &err)
+
+	m, err = queryStockMovements(ctx, r.db, stockMySQLQueries, id)
+	return
+}
+
+// SaveMovement saves the receipt or adjustment into the database, changing the stock of its warehouse.
+func (r *StocksMySQL) SaveMovement(ctx context.Context, m *internal.StockMovement) (err error) {
+	defer observe(ctx, "stocks.SaveMovement", time.Now(), &err)
+
+	// default the warehouse, as the column does, and date the movement
+	(*m).Warehouse = defaultWarehouse((*m).Warehouse)
+	(*m).Datetime = priceDatetime(time.Now())
+
+	// change the stock, insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product exists
+		err = checkStockProduct(ctx, tx, ExistsProductQuery, (*m).ProductId)
+		if err != nil {
+			return err
+		}
+
+		// change the stock of the warehouse
+		_, err = moveStock(ctx, tx, stockMySQLQueries, *m)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx, InsertStockMovementQuery,
+			(*m).ProductId, (*m).Warehouse, (*m).Kind, (*m).Quantity, stockSaleId(*m), (*m).Datetime,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*m).Id = int(id)
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityStockMovements, (*m).Id, nil, *m)
+	})
+	return
+}
+
+// FindStockSales returns the stock of the tracked products with the units sold on the invoices dated from since.
+func (r *StocksMySQL) FindStockSales(ctx context.Context, since string) (s []internal.StockSales, err error) {
+	defer observe(ctx, "stocks.FindStockSales", time.Now(), &err)
+
+	s, err = queryStockSales(ctx, r.db, StockSalesQuery, since)
+	return
+}
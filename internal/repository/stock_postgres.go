@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	TrackedStockPostgresQuery         = `SELECT COUNT(*) FROM stocks WHERE "product_id" = $1`
+	HeldStockPostgresQuery            = `SELECT COUNT(*) FROM stocks WHERE "product_id" = $1 AND "warehouse" = $2`
+	AddStockPostgresQuery             = `UPDATE stocks SET "quantity" = "quantity" + $1 WHERE "product_id" = $2 AND "warehouse" = $3 AND "quantity" + $4 >= 0`
+	UpsertStockPostgresQuery          = `INSERT INTO stocks ("product_id", "warehouse", "quantity") VALUES ($1, $2, $3) ON CONFLICT ("product_id", "warehouse") DO UPDATE SET "quantity" = stocks."quantity" + EXCLUDED."quantity"`
+	InsertStockMovementPostgresQuery  = `INSERT INTO stock_movements ("product_id", "warehouse", "kind", "quantity", "sale_id", "datetime") VALUES ($1, $2, $3, $4, $5, $6)`
+	SelectStockPostgresQuery          = `SELECT "warehouse", "quantity" FROM stocks WHERE "product_id" = $1 ORDER BY "warehouse"`
+	SelectStockMovementsPostgresQuery = `SELECT "id", "product_id", "warehouse", "kind", "quantity", "sale_id", to_char("datetime", 'YYYY-MM-DD HH24:MI:SS') FROM stock_movements WHERE "product_id" = $1 ORDER BY "id"`
+	// StockSalesPostgresQuery selects the stock of the tracked products and the units sold on the invoices dated from the placeholder.
+	StockSalesPostgresQuery = `SELECT p."id", p."description", st."quantity", COALESCE(v."sold", 0) FROM products AS p INNER JOIN (SELECT "product_id", SUM("quantity") AS "quantity" FROM stocks GROUP BY "product_id") AS st ON st."product_id" = p."id" LEFT JOIN (SELECT s."product_id", SUM(s."quantity") AS "sold" FROM sales AS s INNER JOIN invoices AS i ON i."id" = s."invoice_id" WHERE i."datetime" >= $1 GROUP BY s."product_id") AS v ON v."product_id" = p."id" ORDER BY p."id"`
+)
+
+// stockPostgresQueries are the queries of the stock of the products.
+var stockPostgresQueries = stockQueries{
+	exists:         ExistsProductPostgresQuery,
+	tracked:        TrackedStockPostgresQuery,
+	held:           HeldStockPostgresQuery,
+	add:            AddStockPostgresQuery,
+	upsert:         UpsertStockPostgresQuery,
+	insertMovement: InsertStockMovementPostgresQuery,
+	stock:          SelectStockPostgresQuery,
+	movements:      SelectStockMovementsPostgresQuery,
+}
+
+// NewStocksPostgres creates new postgres repository for stock entity.
+func NewStocksPostgres(db *sql.DB) *StocksPostgres {
+	return &StocksPostgres{db}
+}
+
+// StocksPostgres is the Postgres repository implementation for stock entity.
+type StocksPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindByProduct returns the stock of the product id.
+func (r *StocksPostgres) FindByProduct(ctx context.Context, id int) (s internal.ProductStock, err error) {
+	defer observe(ctx, "stocks.FindByProduct", time.Now(), &err)
+
+	s, err = queryProductStock(ctx, r.db, stockPostgresQueries, id)
+	return
+}
+
+// FindMovements returns the stock movements of the product id, oldest first.
+func (r *StocksPostgres) FindMovements(ctx context.Context, id int) (m []internal.StockMovement, err error) {
+	defer observe(ctx, "stocks.FindMovements", time.Now(), &err)
+
+	m, err = queryStockMovements(ctx, r.db, stockPostgresQueries, id)
+	return
+}
+
+// SaveMovement saves the receipt or adjustment into the database, changing the stock of its warehouse.
+func (r *StocksPostgres) SaveMovement(ctx context.Context, m *internal.StockMovement) (err error) {
+	defer observe(ctx, "stocks.SaveMovement", time.Now(), &err)
+
+	// default the warehouse, as the column does, and date the movement
+	(*m).Warehouse = defaultWarehouse((*m).Warehouse)
+	(*m).Datetime = priceDatetime(time.Now())
+
+	// change the stock, insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check the product exists
+		err = checkStockProduct(ctx, tx, ExistsProductPostgresQuery, (*m).ProductId)
+		if err != nil {
+			return err
+		}
+
+		// change the stock of the warehouse
+		_, err = moveStock(ctx, tx, stockPostgresQueries, *m)
+		if err != nil {
+			return err
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO stock_movements ("product_id", "warehouse", "kind", "quantity", "sale_id", "datetime") VALUES ($1, $2, $3, $4, $5, $6) RETURNING "id"`,
+			(*m).ProductId, (*m).Warehouse, (*m).Kind, (*m).Quantity, stockSaleId(*m), (*m).Datetime,
+		).Scan(&(*m).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityStockMovements, (*m).Id, nil, *m)
+	})
+	return
+}
+
+// FindStockSales returns the stock of the tracked products with the units sold on the invoices dated from since.
+func (r *StocksPostgres) FindStockSales(ctx context.Context, since string) (s []internal.StockSales, err error) {
+	defer observe(ctx, "stocks.FindStockSales", time.Now(), &err)
+
+	s, err = queryStockSales(ctx, r.db, StockSalesPostgresQuery, since)
+	return
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"app/internal"
+)
+
+// errTaxRateExists returns the error of a tax rate of the same category and condition as an existing one.
+func errTaxRateExists(t internal.TaxRate) error {
+	condition := "every condition"
+	if t.Condition != nil {
+		condition = fmt.Sprintf("condition %d", *t.Condition)
+	}
+	category := "every category"
+	if t.Category != "" {
+		category = "category " + t.Category
+	}
+	return fmt.Errorf("%w: %s and %s", internal.ErrTaxRateExists, category, condition)
+}
+
+// checkTaxRateUnique checks that no rate of rates has the category and condition of t.
+func checkTaxRateUnique(rates internal.TaxRates, t internal.TaxRate) error {
+	for _, v := range rates {
+		if v.Same(t.TaxRateAttributes) {
+			return errTaxRateExists(t)
+		}
+	}
+	return nil
+}
+
+// defaultProduct defaults the attributes of a product saved without them, as the columns do:
+// the currency, the tax category and the unit.
+func defaultProduct(p *internal.Product) {
+	(*p).Currency = (*p).Currency.OrDefault()
+	if (*p).TaxCategory == "" {
+		(*p).TaxCategory = internal.TaxCategoryDefault
+	}
+	if (*p).Unit == "" {
+		(*p).Unit = internal.UnitDefault
+	}
+}
+
+// defaultInvoice defaults the attributes of an invoice saved without them, as the columns do:
+// the currency, and the subtotal of an invoice without taxes, which is its total.
+func defaultInvoice(i *internal.Invoice) {
+	(*i).Currency = (*i).Currency.OrDefault()
+	if (*i).Subtotal == 0 && (*i).Tax == 0 {
+		(*i).Subtotal = (*i).Total
+	}
+}
+
+// queryTaxRates returns the tax rates selected by query.
+// The columns of query are id, category, customer_condition and rate.
+func queryTaxRates(ctx context.Context, q querier, query string) (t internal.TaxRates, err error) {
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			tr        internal.TaxRate
+			condition sql.NullInt64
+		)
+		err = rows.Scan(&tr.Id, &tr.Category, &condition, &tr.Rate)
+		if err != nil {
+			return
+		}
+		if condition.Valid {
+			c := int(condition.Int64)
+			tr.Condition = &c
+		}
+		t = append(t, tr)
+	}
+	err = rows.Err()
+	return
+}
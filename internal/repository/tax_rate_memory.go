@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewTaxRatesMemory creates new memory repository for tax rate entity.
+func NewTaxRatesMemory(db *MemoryDB) *TaxRatesMemory {
+	return &TaxRatesMemory{db}
+}
+
+// TaxRatesMemory is the memory repository implementation for tax rate entity.
+type TaxRatesMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindAll returns all tax rates from the database.
+func (r *TaxRatesMemory) FindAll(ctx context.Context) (t []internal.TaxRate, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	t = r.db.taxRatesSorted()
+
+	return
+}
+
+// Save saves the tax rate into the database.
+func (r *TaxRatesMemory) Save(ctx context.Context, t *internal.TaxRate) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	// check no rate of the same category and condition exists
+	err = checkTaxRateUnique(r.db.taxRatesSorted(), *t)
+	if err != nil {
+		return
+	}
+
+	// set the id
+	r.db.lastTaxRateId++
+	(*t).Id = r.db.lastTaxRateId
+
+	// audit the creation, under the same lock as the insert
+	err = r.db.audit(ctx, AuditEntityTaxRates, (*t).Id, nil, *t)
+	if err != nil {
+		r.db.lastTaxRateId--
+		return
+	}
+
+	// insert the tax rate
+	r.db.taxRates[(*t).Id] = *t
+
+	return
+}
+
+// taxRatesSorted returns the tax rates by id. The caller must hold the lock.
+func (db *MemoryDB) taxRatesSorted() (t internal.TaxRates) {
+	for _, id := range sortedKeys(db.taxRates) {
+		t = append(t, db.taxRates[id])
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectTaxRatesQuery = "SELECT `id`, `category`, `customer_condition`, `rate` FROM tax_rates ORDER BY `id`"
+)
+
+// NewTaxRatesMySQL creates new mysql repository for tax rate entity.
+func NewTaxRatesMySQL(db *sql.DB) *TaxRatesMySQL {
+	return &TaxRatesMySQL{db}
+}
+
+// TaxRatesMySQL is the MySQL repository implementation for tax rate entity.
+type TaxRatesMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all tax rates from the database.
+func (r *TaxRatesMySQL) FindAll(ctx context.Context) (t []internal.TaxRate, err error) {
+	defer observe(ctx, "tax_rates.FindAll", time.Now(), &err)
+
+	t, err = queryTaxRates(ctx, r.db, SelectTaxRatesQuery)
+	return
+}
+
+// Save saves the tax rate into the database.
+func (r *TaxRatesMySQL) Save(ctx context.Context, t *internal.TaxRate) (err error) {
+	defer observe(ctx, "tax_rates.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check no rate of the same category and condition exists
+		rates, err := queryTaxRates(ctx, tx, SelectTaxRatesQuery)
+		if err != nil {
+			return err
+		}
+		err = checkTaxRateUnique(rates, *t)
+		if err != nil {
+			return err
+		}
+
+		// execute the query
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO tax_rates (`category`, `customer_condition`, `rate`) VALUES (?, ?, ?)",
+			(*t).Category, (*t).Condition, (*t).Rate,
+		)
+		if err != nil {
+			return err
+		}
+
+		// get the last inserted id
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// set the id
+		(*t).Id = int(id)
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordQuery, AuditEntityTaxRates, (*t).Id, nil, *t)
+	})
+	return
+}
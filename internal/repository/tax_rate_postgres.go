@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectTaxRatesPostgresQuery = `SELECT "id", "category", "customer_condition", "rate" FROM tax_rates ORDER BY "id"`
+)
+
+// NewTaxRatesPostgres creates new postgres repository for tax rate entity.
+func NewTaxRatesPostgres(db *sql.DB) *TaxRatesPostgres {
+	return &TaxRatesPostgres{db}
+}
+
+// TaxRatesPostgres is the Postgres repository implementation for tax rate entity.
+type TaxRatesPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindAll returns all tax rates from the database.
+func (r *TaxRatesPostgres) FindAll(ctx context.Context) (t []internal.TaxRate, err error) {
+	defer observe(ctx, "tax_rates.FindAll", time.Now(), &err)
+
+	t, err = queryTaxRates(ctx, r.db, SelectTaxRatesPostgresQuery)
+	return
+}
+
+// Save saves the tax rate into the database.
+func (r *TaxRatesPostgres) Save(ctx context.Context, t *internal.TaxRate) (err error) {
+	defer observe(ctx, "tax_rates.Save", time.Now(), &err)
+
+	// insert the record and its audit record in the same transaction
+	err = inTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		// check no rate of the same category and condition exists
+		rates, err := queryTaxRates(ctx, tx, SelectTaxRatesPostgresQuery)
+		if err != nil {
+			return err
+		}
+		err = checkTaxRateUnique(rates, *t)
+		if err != nil {
+			return err
+		}
+
+		// execute the query, returning the generated id
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO tax_rates ("category", "customer_condition", "rate") VALUES ($1, $2, $3) RETURNING "id"`,
+			(*t).Category, (*t).Condition, (*t).Rate,
+		).Scan(&(*t).Id)
+		if err != nil {
+			return err
+		}
+
+		// audit the creation
+		return writeAudit(ctx, tx, InsertAuditRecordPostgresQuery, AuditEntityTaxRates, (*t).Id, nil, *t)
+	})
+	return
+}
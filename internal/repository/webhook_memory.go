@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewWebhooksMemory creates new memory repository for webhook delivery entity.
+func NewWebhooksMemory(db *MemoryDB) *WebhooksMemory {
+	return &WebhooksMemory{db}
+}
+
+// WebhooksMemory is the memory repository implementation for webhook delivery entity.
+type WebhooksMemory struct {
+	// db is the in-memory database.
+	db *MemoryDB
+}
+
+// FindPending returns the pending deliveries from the database, oldest first.
+func (r *WebhooksMemory) FindPending(ctx context.Context, maxAttempts, limit int) (d []internal.WebhookDelivery, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	d = []internal.WebhookDelivery{}
+	for _, id := range sortedKeys(r.db.webhooks) {
+		if len(d) == limit {
+			break
+		}
+		if wd := r.db.webhooks[id]; !wd.Delivered && wd.Attempts < maxAttempts {
+			d = append(d, wd)
+		}
+	}
+
+	return
+}
+
+// Update saves the state of the delivery into the database.
+func (r *WebhooksMemory) Update(ctx context.Context, d internal.WebhookDelivery) (err error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	wd, ok := r.db.webhooks[d.Id]
+	if !ok {
+		return
+	}
+	wd.Attempts, wd.Delivered, wd.LastError = d.Attempts, d.Delivered, d.LastError
+	r.db.webhooks[d.Id] = wd
+
+	return
+}
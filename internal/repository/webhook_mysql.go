@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectPendingWebhookDeliveriesQuery = "SELECT `id`, `event`, `payload`, `attempts`, `delivered`, `last_error` FROM webhook_deliveries WHERE `delivered` = FALSE AND `attempts` < ? ORDER BY `id` LIMIT ?"
+	UpdateWebhookDeliveryQuery          = "UPDATE webhook_deliveries SET `attempts` = ?, `delivered` = ?, `last_error` = ? WHERE `id` = ?"
+)
+
+// NewWebhooksMySQL creates new mysql repository for webhook delivery entity.
+func NewWebhooksMySQL(db *sql.DB) *WebhooksMySQL {
+	return &WebhooksMySQL{db}
+}
+
+// WebhooksMySQL is the MySQL repository implementation for webhook delivery entity.
+type WebhooksMySQL struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindPending returns the pending deliveries from the database, oldest first.
+func (r *WebhooksMySQL) FindPending(ctx context.Context, maxAttempts, limit int) (d []internal.WebhookDelivery, err error) {
+	defer observe(ctx, "webhooks.FindPending", time.Now(), &err)
+
+	d, err = queryWebhookDeliveries(ctx, r.db, SelectPendingWebhookDeliveriesQuery, maxAttempts, limit)
+	return
+}
+
+// Update saves the state of the delivery into the database.
+func (r *WebhooksMySQL) Update(ctx context.Context, d internal.WebhookDelivery) (err error) {
+	defer observe(ctx, "webhooks.Update", time.Now(), &err)
+
+	_, err = r.db.ExecContext(ctx, UpdateWebhookDeliveryQuery, d.Attempts, d.Delivered, d.LastError, d.Id)
+	return
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectPendingWebhookDeliveriesPostgresQuery = `SELECT "id", "event", "payload", "attempts", "delivered", "last_error" FROM webhook_deliveries WHERE "delivered" = FALSE AND "attempts" < $1 ORDER BY "id" LIMIT $2`
+	UpdateWebhookDeliveryPostgresQuery          = `UPDATE webhook_deliveries SET "attempts" = $1, "delivered" = $2, "last_error" = $3 WHERE "id" = $4`
+)
+
+// NewWebhooksPostgres creates new postgres repository for webhook delivery entity.
+func NewWebhooksPostgres(db *sql.DB) *WebhooksPostgres {
+	return &WebhooksPostgres{db}
+}
+
+// WebhooksPostgres is the Postgres repository implementation for webhook delivery entity.
+type WebhooksPostgres struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindPending returns the pending deliveries from the database, oldest first.
+func (r *WebhooksPostgres) FindPending(ctx context.Context, maxAttempts, limit int) (d []internal.WebhookDelivery, err error) {
+	defer observe(ctx, "webhooks.FindPending", time.Now(), &err)
+
+	d, err = queryWebhookDeliveries(ctx, r.db, SelectPendingWebhookDeliveriesPostgresQuery, maxAttempts, limit)
+	return
+}
+
+// Update saves the state of the delivery into the database.
+func (r *WebhooksPostgres) Update(ctx context.Context, d internal.WebhookDelivery) (err error) {
+	defer observe(ctx, "webhooks.Update", time.Now(), &err)
+
+	_, err = r.db.ExecContext(ctx, UpdateWebhookDeliveryPostgresQuery, d.Attempts, d.Delivered, d.LastError, d.Id)
+	return
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"app/internal"
+)
+
+const (
+	SelectPendingWebhookDeliveriesSQLiteQuery = `SELECT "id", "event", "payload", "attempts", "delivered", "last_error" FROM webhook_deliveries WHERE "delivered" = FALSE AND "attempts" < ? ORDER BY "id" LIMIT ?`
+	UpdateWebhookDeliverySQLiteQuery          = `UPDATE webhook_deliveries SET "attempts" = ?, "delivered" = ?, "last_error" = ? WHERE "id" = ?`
+)
+
+// NewWebhooksSQLite creates new sqlite repository for webhook delivery entity.
+func NewWebhooksSQLite(db *sql.DB) *WebhooksSQLite {
+	return &WebhooksSQLite{db}
+}
+
+// WebhooksSQLite is the SQLite repository implementation for webhook delivery entity.
+type WebhooksSQLite struct {
+	// db is the database connection.
+	db *sql.DB
+}
+
+// FindPending returns the pending deliveries from the database, oldest first.
+func (r *WebhooksSQLite) FindPending(ctx context.Context, maxAttempts, limit int) (d []internal.WebhookDelivery, err error) {
+	defer observe(ctx, "webhooks.FindPending", time.Now(), &err)
+
+	d, err = queryWebhookDeliveries(ctx, r.db, SelectPendingWebhookDeliveriesSQLiteQuery, maxAttempts, limit)
+	return
+}
+
+// Update saves the state of the delivery into the database.
+func (r *WebhooksSQLite) Update(ctx context.Context, d internal.WebhookDelivery) (err error) {
+	defer observe(ctx, "webhooks.Update", time.Now(), &err)
+
+	_, err = r.db.ExecContext(ctx, UpdateWebhookDeliverySQLiteQuery, d.Attempts, d.Delivered, d.LastError, d.Id)
+	return
+}
@@ -8,6 +8,16 @@ type SaleAttributes struct {
 	ProductId int
 	// InvoiceId is the invoice id of the sale.
 	InvoiceId int
+	// Warehouse is the warehouse the units are taken from, WarehouseDefault if empty.
+	Warehouse string
+	// UnitPrice is the price of the product when the sale was saved, set by the repository.
+	UnitPrice Money
+	// TaxRate is the tax rate applicable to the sale when it was saved, set by the repository.
+	TaxRate Rate
+	// Discount is the amount the promotions take off the sale, set by the repository.
+	Discount Money
+	// Discounts are the discounts of the promotions applied to the sale, set by the repository.
+	Discounts []SaleDiscount
 }
 
 // Sale is the struct that represents a sale.
@@ -1,9 +1,14 @@
 package internal
 
+import "context"
+
 // RepositorySale is the interface that wraps the basic Sale methods.
 type RepositorySale interface {
 	// FindAll returns all sales.
-	FindAll() (s []Sale, err error)
-	// Save saves a sale.
-	Save(s *Sale) (err error)
+	FindAll(ctx context.Context) (s []Sale, err error)
+	// Save saves a sale at the current price of its product, setting its unit price.
+	// It fails with ErrProductArchived if the product is archived, with ErrCurrencyMismatch if the product is not
+	// in the currency of the invoice, and with ErrStockInsufficient if the stock of the product is tracked and its
+	// warehouse holds fewer units.
+	Save(ctx context.Context, s *Sale) (err error)
 }
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// SearchLimitDefault is the number of results of a search page when the query does not set it.
+	SearchLimitDefault = 20
+	// SearchLimitMax is the maximum number of results of a search page.
+	SearchLimitMax = 100
+)
+
+// SearchQuery is the struct that represents a full-text search and the page of its results.
+type SearchQuery struct {
+	// Text is the searched text. Its words match the words of the searched fields that start with them,
+	// or that differ by a typo.
+	Text string
+	// Limit is the maximum number of results.
+	Limit int
+	// Offset is the number of results skipped, the best ranked first.
+	Offset int
+}
+
+// Terms returns the words of the text, in lowercase. Anything but letters and digits separates them.
+func (q SearchQuery) Terms() []string {
+	return searchWords(q.Text)
+}
+
+// CustomerMatch is the struct that represents a customer found by a search.
+type CustomerMatch struct {
+	// Customer is the customer.
+	Customer
+	// Score is the relevance of the customer, higher the better. It only compares the results of the same search.
+	Score float64
+}
+
+// CustomerMatches is the struct that represents a page of the customers found by a search.
+type CustomerMatches struct {
+	// Total is the number of customers found, on every page.
+	Total int
+	// Matches is the page of the customers found, the best ranked first.
+	Matches []CustomerMatch
+}
+
+// ProductMatch is the struct that represents a product found by a search.
+type ProductMatch struct {
+	// Product is the product.
+	Product
+	// Score is the relevance of the product, higher the better. It only compares the results of the same search.
+	Score float64
+}
+
+// ProductMatches is the struct that represents a page of the products found by a search.
+type ProductMatches struct {
+	// Total is the number of products found, on every page.
+	Total int
+	// Matches is the page of the products found, the best ranked first.
+	Matches []ProductMatch
+}
+
+// MatchScore returns the score of the fields for the search terms, and whether every term matches a word of them.
+// A term scores 1 if it is a word of the fields, 0.75 if it starts one, and 0.5 if it is a typo away from one:
+// a letter missing, added, replaced or swapped with the next one, two for terms of 8 letters or more.
+// Terms shorter than 4 letters must start a word.
+func MatchScore(terms []string, fields ...string) (score float64, ok bool) {
+	var words []string
+	for _, f := range fields {
+		words = append(words, searchWords(f)...)
+	}
+
+	for _, t := range terms {
+		best := 0.0
+		for _, w := range words {
+			best = max(best, termScore(t, w))
+		}
+		if best == 0 {
+			return 0, false
+		}
+		score += best
+	}
+	return score, len(terms) > 0
+}
+
+// termScore returns the score of the word w for the term t, 0 if it does not match.
+func termScore(t, w string) float64 {
+	switch {
+	case t == w:
+		return 1
+	case strings.HasPrefix(w, t):
+		return 0.75
+	}
+
+	// - typos
+	tr, wr := []rune(t), []rune(w)
+	typos := 0
+	switch n := len(tr); {
+	case n >= 8:
+		typos = 2
+	case n >= 4:
+		typos = 1
+	}
+	if typos > 0 && editDistance(tr, wr, typos) <= typos {
+		return 0.5
+	}
+	return 0
+}
+
+// editDistance returns the number of letters to delete, insert, replace or swap with the next one to turn a into b,
+// the optimal string alignment distance. It returns bound+1 once the distance is known to exceed bound.
+func editDistance(a, b []rune, bound int) int {
+	if d := len(a) - len(b); d > bound || -d > bound {
+		return bound + 1
+	}
+
+	// - rows of the dynamic programming matrix: the previous two and the current one
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > bound {
+			return bound + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// searchWords splits s in lowercase words of letters and digits.
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,48 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for SearchQuery.Terms method
+func TestSearchQueryTerms(t *testing.T) {
+	require.Equal(t, []string{"john", "o", "neil", "42"}, internal.SearchQuery{Text: "  John O'Neil, 42* "}.Terms())
+	require.Empty(t, internal.SearchQuery{Text: " +-*() "}.Terms())
+}
+
+// Tests for MatchScore function
+func TestMatchScore(t *testing.T) {
+	testCases := []struct {
+		name        string
+		terms       []string
+		fields      []string
+		expectScore float64
+		expectOk    bool
+	}{
+		{name: "word", terms: []string{"john"}, fields: []string{"John", "Doe"}, expectScore: 1, expectOk: true},
+		{name: "prefix", terms: []string{"jo"}, fields: []string{"Johnny", "Doe"}, expectScore: 0.75, expectOk: true},
+		{name: "best word of every term", terms: []string{"john", "do"}, fields: []string{"Johnny John", "Doe"}, expectScore: 1.75, expectOk: true},
+		{name: "letter replaced", terms: []string{"jonn"}, fields: []string{"John"}, expectScore: 0.5, expectOk: true},
+		{name: "letter missing", terms: []string{"cofee"}, fields: []string{"Coffee beans"}, expectScore: 0.5, expectOk: true},
+		{name: "letters swapped", terms: []string{"smiht"}, fields: []string{"Smith"}, expectScore: 0.5, expectOk: true},
+		{name: "two typos in a long term", terms: []string{"chocolatte", "bar"}, fields: []string{"Chocolate bar"}, expectScore: 1.5, expectOk: true},
+		{name: "two typos in a short term", terms: []string{"jhonn"}, fields: []string{"John"}},
+		{name: "no typos in a term of 3 letters", terms: []string{"doe"}, fields: []string{"Dee"}},
+		{name: "every term must match", terms: []string{"john", "smith"}, fields: []string{"John", "Doe"}},
+		{name: "no terms", fields: []string{"John", "Doe"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			score, ok := internal.MatchScore(testCase.terms, testCase.fields...)
+
+			// assert
+			require.Equal(t, testCase.expectOk, ok)
+			require.Equal(t, testCase.expectScore, score)
+		})
+	}
+}
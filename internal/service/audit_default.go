@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewAuditDefault creates new default service for audit record entity.
+func NewAuditDefault(rp internal.RepositoryAudit) *AuditDefault {
+	return &AuditDefault{rp}
+}
+
+// AuditDefault is the default service implementation for audit record entity.
+type AuditDefault struct {
+	// rp is the repository for audit record entity.
+	rp internal.RepositoryAudit
+}
+
+// FindAll returns the audit records matching the filter, oldest first.
+func (s *AuditDefault) FindAll(ctx context.Context, f internal.AuditFilter) (a []internal.AuditRecord, err error) {
+	a, err = s.rp.FindAll(ctx, f)
+	return
+}
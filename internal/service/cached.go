@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"app/internal"
+	"app/platform/cache"
+)
+
+const (
+	// CacheKeyTopCustomers is the cache key prefix of the top customers report, followed by the reporting currency.
+	CacheKeyTopCustomers = "customers:top"
+	// CacheKeyTopProducts is the cache key of the top products report.
+	CacheKeyTopProducts = "products:top"
+	// CacheKeyInvoicesTotalByCustomerCondition is the cache key prefix of the invoices total by customer condition report,
+	// followed by the reporting currency.
+	CacheKeyInvoicesTotalByCustomerCondition = "invoices:total:condition"
+)
+
+// currencyKey returns the cache key of the report of prefix converted to currency.
+func currencyKey(prefix string, currency internal.Currency) string {
+	return prefix + ":" + string(currency)
+}
+
+// readThrough returns the value cached under key or, on a miss, loads it and caches it for ttl.
+func readThrough[T any](ctx context.Context, c cache.Cache, key string, ttl time.Duration, load func(context.Context) (T, error)) (v T, err error) {
+	// cache
+	if b, ok := c.Get(key); ok {
+		if json.Unmarshal(b, &v) == nil {
+			return
+		}
+	}
+
+	// load
+	v, err = load(ctx)
+	if err != nil {
+		return
+	}
+
+	// a value that can not be encoded is just not cached
+	if b, errMarshal := json.Marshal(v); errMarshal == nil {
+		c.Set(key, b, ttl)
+	}
+
+	return
+}
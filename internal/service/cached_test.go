@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"app/platform/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the caching services
+func TestCached(t *testing.T) {
+	t.Run("top customers - read through and invalidate on invoice save", func(t *testing.T) {
+		// arrange
+		db := repository.NewMemoryDB()
+		c := cache.NewLRU(10)
+		svCustomer := service.NewCustomersCached(service.NewCustomersDefault(repository.NewCustomersMemory(db)), c, time.Minute)
+		svInvoice := service.NewInvoicesCached(service.NewInvoicesDefault(repository.NewInvoicesMemory(db)), c, time.Minute)
+		cs := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe"}}
+		require.NoError(t, svCustomer.Save(context.Background(), &cs))
+		iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Total: internal.MustParseMoney("10"), CustomerId: cs.Id}}
+		require.NoError(t, svInvoice.Save(context.Background(), &iv))
+
+		// act
+		first, err1 := svCustomer.GetTopCustomers(context.Background(), internal.CurrencyDefault)
+		second, err2 := svCustomer.GetTopCustomers(context.Background(), internal.CurrencyDefault)
+		iv = internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Total: internal.MustParseMoney("5"), CustomerId: cs.Id}}
+		require.NoError(t, svInvoice.Save(context.Background(), &iv))
+		third, err3 := svCustomer.GetTopCustomers(context.Background(), internal.CurrencyDefault)
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.Equal(t, first, second)
+		require.Equal(t, internal.MustParseMoney("15"), third[0].Amount)
+		require.Equal(t, cache.Stats{Hits: 1, Misses: 2, Entries: 1}, c.Stats())
+	})
+
+	t.Run("invoices total by condition - invalidate on update total", func(t *testing.T) {
+		// arrange
+		db := repository.NewMemoryDB()
+		c := cache.NewLRU(10)
+		svInvoice := service.NewInvoicesCached(service.NewInvoicesDefault(repository.NewInvoicesMemory(db)), c, time.Minute)
+		cs := internal.Customer{CustomerAttributes: internal.CustomerAttributes{Condition: 1}}
+		require.NoError(t, repository.NewCustomersMemory(db).Save(context.Background(), &cs))
+		pr := internal.Product{ProductAttributes: internal.ProductAttributes{Price: internal.MustParseMoney("2")}}
+		require.NoError(t, repository.NewProductsMemory(db).Save(internal.WithStockUntracked(context.Background()), &pr))
+		iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{CustomerId: cs.Id}}
+		require.NoError(t, svInvoice.Save(context.Background(), &iv))
+		sa := internal.Sale{SaleAttributes: internal.SaleAttributes{Quantity: 3, ProductId: pr.Id, InvoiceId: iv.Id}}
+		require.NoError(t, repository.NewSalesMemory(db).Save(context.Background(), &sa))
+
+		// act
+		before, err1 := svInvoice.GetInvoicesTotalByCustomerCondition(context.Background(), internal.CurrencyDefault)
+		errUpdate := svInvoice.UpdateInvoicesTotal(context.Background())
+		after, err2 := svInvoice.GetInvoicesTotalByCustomerCondition(context.Background(), internal.CurrencyDefault)
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, errUpdate)
+		require.NoError(t, err2)
+		require.Equal(t, []internal.InvoiceTotalByCustomerCondition{{Condition: 1, Net: 0, Total: 0, Currency: internal.CurrencyDefault}}, before)
+		require.Equal(t, []internal.InvoiceTotalByCustomerCondition{{Condition: 1, Net: internal.MustParseMoney("6"), Total: internal.MustParseMoney("6"), Currency: internal.CurrencyDefault}}, after)
+	})
+
+	t.Run("top customers - invalidate on customer update", func(t *testing.T) {
+		// arrange
+		db := repository.NewMemoryDB()
+		c := cache.NewLRU(10)
+		svCustomer := service.NewCustomersCached(service.NewCustomersDefault(repository.NewCustomersMemory(db)), c, time.Minute)
+		cs := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe", Condition: internal.CustomerConditionActive}}
+		require.NoError(t, svCustomer.Save(context.Background(), &cs))
+		iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Total: internal.MustParseMoney("10"), CustomerId: cs.Id}}
+		require.NoError(t, repository.NewInvoicesMemory(db).Save(context.Background(), &iv))
+
+		// act
+		before, err1 := svCustomer.GetTopCustomers(context.Background(), internal.CurrencyDefault)
+		cs.LastName = "Smith"
+		errUpdate := svCustomer.Update(context.Background(), &cs)
+		after, err2 := svCustomer.GetTopCustomers(context.Background(), internal.CurrencyDefault)
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, errUpdate)
+		require.NoError(t, err2)
+		require.Equal(t, "Doe", before[0].LastName)
+		require.Equal(t, "Smith", after[0].LastName)
+	})
+
+	t.Run("top products - invalidate on sale save", func(t *testing.T) {
+		// arrange
+		db := repository.NewMemoryDB()
+		c := cache.NewLRU(10)
+		svProduct := service.NewProductsCached(service.NewProductsDefault(repository.NewProductsMemory(db)), c, time.Minute)
+		svSale := service.NewSalesCached(service.NewSalesDefault(repository.NewSalesMemory(db)), c)
+		cs := internal.Customer{}
+		require.NoError(t, repository.NewCustomersMemory(db).Save(context.Background(), &cs))
+		iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{CustomerId: cs.Id}}
+		require.NoError(t, repository.NewInvoicesMemory(db).Save(context.Background(), &iv))
+		pr := internal.Product{ProductAttributes: internal.ProductAttributes{Description: "Product 1"}}
+		require.NoError(t, svProduct.Save(internal.WithStockUntracked(context.Background()), &pr))
+
+		// act
+		before, err1 := svProduct.GetTopProducts(context.Background())
+		sa := internal.Sale{SaleAttributes: internal.SaleAttributes{Quantity: 3, ProductId: pr.Id, InvoiceId: iv.Id}}
+		errSave := svSale.Save(context.Background(), &sa)
+		after, err2 := svProduct.GetTopProducts(context.Background())
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, errSave)
+		require.NoError(t, err2)
+		require.Empty(t, before)
+		require.Equal(t, []internal.TopProduct{{Id: pr.Id, Description: "Product 1", Total: 3}}, after)
+	})
+
+	t.Run("reports - cached by currency and invalidated on exchange rate save", func(t *testing.T) {
+		// arrange
+		db := repository.NewMemoryDB()
+		c := cache.NewLRU(10)
+		svCustomer := service.NewCustomersCached(service.NewCustomersDefault(repository.NewCustomersMemory(db)), c, time.Minute)
+		svRate := service.NewExchangeRatesCached(service.NewExchangeRatesDefault(repository.NewExchangeRatesMemory(db)), c)
+		cs := internal.Customer{}
+		require.NoError(t, repository.NewCustomersMemory(db).Save(context.Background(), &cs))
+		iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Datetime: "2022-05-15 00:00:00", Total: internal.MustParseMoney("10"), CustomerId: cs.Id}}
+		require.NoError(t, repository.NewInvoicesMemory(db).Save(context.Background(), &iv))
+		er := internal.ExchangeRate{ExchangeRateAttributes: internal.ExchangeRateAttributes{From: "USD", To: "EUR", Rate: internal.MustParseRate("0.5"), EffectiveDate: "2022-01-01"}}
+
+		// act
+		usd, errUSD := svCustomer.GetTopCustomers(context.Background(), "USD")
+		_, errMissing := svCustomer.GetTopCustomers(context.Background(), "EUR")
+		errSave := svRate.Save(context.Background(), &er)
+		eur, errEUR := svCustomer.GetTopCustomers(context.Background(), "EUR")
+
+		// assert
+		require.NoError(t, errUSD)
+		require.ErrorIs(t, errMissing, internal.ErrExchangeRateNotFound)
+		require.NoError(t, errSave)
+		require.NoError(t, errEUR)
+		require.Equal(t, internal.MustParseMoney("10"), usd[0].Amount)
+		require.Equal(t, internal.MustParseMoney("5"), eur[0].Amount)
+		require.Equal(t, cache.Stats{Misses: 3, Entries: 1}, c.Stats())
+	})
+}
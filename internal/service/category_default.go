@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal"
+)
+
+// NewCategoriesDefault creates new default service for category entity.
+func NewCategoriesDefault(rp internal.RepositoryCategory) *CategoriesDefault {
+	return &CategoriesDefault{rp}
+}
+
+// CategoriesDefault is the default service implementation for category entity.
+type CategoriesDefault struct {
+	// rp is the repository for category entity.
+	rp internal.RepositoryCategory
+}
+
+// FindAll returns all categories.
+func (s *CategoriesDefault) FindAll(ctx context.Context) (c []internal.Category, err error) {
+	c, err = s.rp.FindAll(ctx)
+	return
+}
+
+// Save saves the category.
+func (s *CategoriesDefault) Save(ctx context.Context, c *internal.Category) (err error) {
+	err = s.rp.Save(ctx, c)
+	return
+}
+
+// GetTopCategories returns the 5 children of the category parentId with the highest revenue converted to currency.
+func (s *CategoriesDefault) GetTopCategories(ctx context.Context, currency internal.Currency, parentId int) (t []internal.TopCategory, err error) {
+	c, err := s.rp.FindAll(ctx)
+	if err != nil {
+		return
+	}
+	if !categoryExists(c, parentId) {
+		return nil, fmt.Errorf("%w: %d", internal.ErrCategoryNotFound, parentId)
+	}
+
+	r, err := s.rp.GetCategoryRevenue(ctx, currency)
+	if err != nil {
+		return
+	}
+	t = internal.Categories(c).Top(parentId, r, currency)
+	return
+}
+
+// categoryExists reports whether the category id is among c, which the root 0 always is.
+func categoryExists(c []internal.Category, id int) bool {
+	if id == 0 {
+		return true
+	}
+	for _, v := range c {
+		if v.Id == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewCategoriesTraced creates new tracing service for category entity, decorating sv.
+func NewCategoriesTraced(sv internal.ServiceCategory) *CategoriesTraced {
+	return &CategoriesTraced{sv: sv}
+}
+
+// CategoriesTraced is the tracing service implementation for category entity.
+// Every call is traced as a child span of the span carried by the context.
+type CategoriesTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceCategory
+}
+
+// FindAll returns all categories.
+func (s *CategoriesTraced) FindAll(ctx context.Context) ([]internal.Category, error) {
+	return traced(ctx, "categories.FindAll", func(ctx context.Context) ([]internal.Category, error) { return s.sv.FindAll(ctx) })
+}
+
+// Save saves the category.
+func (s *CategoriesTraced) Save(ctx context.Context, c *internal.Category) error {
+	return tracedErr(ctx, "categories.Save", func(ctx context.Context) error { return s.sv.Save(ctx, c) })
+}
+
+// GetTopCategories returns the 5 children of the category parentId with the highest revenue converted to currency.
+func (s *CategoriesTraced) GetTopCategories(ctx context.Context, currency internal.Currency, parentId int) ([]internal.TopCategory, error) {
+	return traced(ctx, "categories.GetTopCategories", func(ctx context.Context) ([]internal.TopCategory, error) {
+		return s.sv.GetTopCategories(ctx, currency, parentId)
+	})
+}
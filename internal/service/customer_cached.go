@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"app/internal"
+	"app/platform/cache"
+)
+
+// NewCustomersCached creates new caching service for customer entity, decorating sv.
+func NewCustomersCached(sv internal.ServiceCustomer, c cache.Cache, ttl time.Duration) *CustomersCached {
+	return &CustomersCached{sv: sv, c: c, ttl: ttl}
+}
+
+// CustomersCached is the caching service implementation for customer entity.
+// It caches the top customers report.
+type CustomersCached struct {
+	// sv is the decorated service.
+	sv internal.ServiceCustomer
+	// c is the cache.
+	c cache.Cache
+	// ttl is the time to live of the cached reports.
+	ttl time.Duration
+}
+
+// FindAll returns all customers.
+func (s *CustomersCached) FindAll(ctx context.Context) (c []internal.Customer, err error) {
+	c, err = s.sv.FindAll(ctx)
+	return
+}
+
+// Save saves the customer.
+// A new customer has no invoices yet, so no cached report is affected.
+func (s *CustomersCached) Save(ctx context.Context, c *internal.Customer) (err error) {
+	err = s.sv.Save(ctx, c)
+	return
+}
+
+// Update updates the customer and invalidates the reports showing its name or grouped by its condition.
+func (s *CustomersCached) Update(ctx context.Context, c *internal.Customer) (err error) {
+	err = s.sv.Update(ctx, c)
+	if err != nil {
+		return
+	}
+
+	s.c.DeletePrefix(CacheKeyTopCustomers, CacheKeyInvoicesTotalByCustomerCondition)
+	return
+}
+
+// GetTopCustomers returns the top customers converted to currency, from the cache if present.
+func (s *CustomersCached) GetTopCustomers(ctx context.Context, currency internal.Currency) ([]internal.TopCustomer, error) {
+	return readThrough(ctx, s.c, currencyKey(CacheKeyTopCustomers, currency), s.ttl, func(ctx context.Context) ([]internal.TopCustomer, error) {
+		return s.sv.GetTopCustomers(ctx, currency)
+	})
+}
+
+// Search returns the page of q of the customers matching its text.
+// Searches are not cached, as their queries rarely repeat.
+func (s *CustomersCached) Search(ctx context.Context, q internal.SearchQuery) (m internal.CustomerMatches, err error) {
+	m, err = s.sv.Search(ctx, q)
+	return
+}
@@ -1,6 +1,10 @@
 package service
 
-import "app/internal"
+import (
+	"context"
+
+	"app/internal"
+)
 
 // NewCustomersDefault creates new default service for customer entity.
 func NewCustomersDefault(rp internal.RepositoryCustomer) *CustomersDefault {
@@ -14,17 +18,29 @@ type CustomersDefault struct {
 }
 
 // FindAll returns all customers.
-func (s *CustomersDefault) FindAll() (c []internal.Customer, err error) {
-	c, err = s.rp.FindAll()
+func (s *CustomersDefault) FindAll(ctx context.Context) (c []internal.Customer, err error) {
+	c, err = s.rp.FindAll(ctx)
 	return
 }
 
 // Save saves the customer.
-func (s *CustomersDefault) Save(c *internal.Customer) (err error) {
-	err = s.rp.Save(c)
+func (s *CustomersDefault) Save(ctx context.Context, c *internal.Customer) (err error) {
+	err = s.rp.Save(ctx, c)
+	return
+}
+
+// Update updates the customer.
+func (s *CustomersDefault) Update(ctx context.Context, c *internal.Customer) (err error) {
+	err = s.rp.Update(ctx, c)
 	return
 }
 
-func (s *CustomersDefault) GetTopCustomers() ([]internal.TopCustomer, error) {
-	return s.rp.GetTopCustomers()
+func (s *CustomersDefault) GetTopCustomers(ctx context.Context, currency internal.Currency) ([]internal.TopCustomer, error) {
+	return s.rp.GetTopCustomers(ctx, currency)
+}
+
+// Search returns the page of q of the customers matching its text.
+func (s *CustomersDefault) Search(ctx context.Context, q internal.SearchQuery) (m internal.CustomerMatches, err error) {
+	m, err = s.rp.Search(ctx, q)
+	return
 }
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewCustomersTraced creates new tracing service for customer entity, decorating sv.
+func NewCustomersTraced(sv internal.ServiceCustomer) *CustomersTraced {
+	return &CustomersTraced{sv: sv}
+}
+
+// CustomersTraced is the tracing service implementation for customer entity.
+// Every call is traced as a child span of the span carried by the context.
+type CustomersTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceCustomer
+}
+
+// FindAll returns all customers.
+func (s *CustomersTraced) FindAll(ctx context.Context) ([]internal.Customer, error) {
+	return traced(ctx, "customers.FindAll", s.sv.FindAll)
+}
+
+// Save saves the customer.
+func (s *CustomersTraced) Save(ctx context.Context, c *internal.Customer) error {
+	return tracedErr(ctx, "customers.Save", func(ctx context.Context) error { return s.sv.Save(ctx, c) })
+}
+
+// Update updates the customer.
+func (s *CustomersTraced) Update(ctx context.Context, c *internal.Customer) error {
+	return tracedErr(ctx, "customers.Update", func(ctx context.Context) error { return s.sv.Update(ctx, c) })
+}
+
+// GetTopCustomers returns the top customers converted to currency.
+func (s *CustomersTraced) GetTopCustomers(ctx context.Context, currency internal.Currency) ([]internal.TopCustomer, error) {
+	return traced(ctx, "customers.GetTopCustomers", func(ctx context.Context) ([]internal.TopCustomer, error) {
+		return s.sv.GetTopCustomers(ctx, currency)
+	})
+}
+
+// Search returns the page of q of the customers matching its text.
+func (s *CustomersTraced) Search(ctx context.Context, q internal.SearchQuery) (internal.CustomerMatches, error) {
+	return traced(ctx, "customers.Search", func(ctx context.Context) (internal.CustomerMatches, error) { return s.sv.Search(ctx, q) })
+}
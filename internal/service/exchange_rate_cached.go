@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+	"app/platform/cache"
+)
+
+// NewExchangeRatesCached creates new caching service for exchange rate entity, decorating sv.
+// Exchange rates are not cached, but saving one invalidates the reports converted with them.
+func NewExchangeRatesCached(sv internal.ServiceExchangeRate, c cache.Cache) *ExchangeRatesCached {
+	return &ExchangeRatesCached{sv: sv, c: c}
+}
+
+// ExchangeRatesCached is the caching service implementation for exchange rate entity.
+type ExchangeRatesCached struct {
+	// sv is the decorated service.
+	sv internal.ServiceExchangeRate
+	// c is the cache.
+	c cache.Cache
+}
+
+// FindAll returns all exchange rates.
+func (s *ExchangeRatesCached) FindAll(ctx context.Context) (e []internal.ExchangeRate, err error) {
+	e, err = s.sv.FindAll(ctx)
+	return
+}
+
+// Save saves the exchange rate and invalidates the reports converted to a currency.
+func (s *ExchangeRatesCached) Save(ctx context.Context, e *internal.ExchangeRate) (err error) {
+	err = s.sv.Save(ctx, e)
+	if err != nil {
+		return
+	}
+
+	s.c.DeletePrefix(CacheKeyTopCustomers, CacheKeyInvoicesTotalByCustomerCondition)
+	return
+}
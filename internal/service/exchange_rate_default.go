@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewExchangeRatesDefault creates new default service for exchange rate entity.
+func NewExchangeRatesDefault(rp internal.RepositoryExchangeRate) *ExchangeRatesDefault {
+	return &ExchangeRatesDefault{rp}
+}
+
+// ExchangeRatesDefault is the default service implementation for exchange rate entity.
+type ExchangeRatesDefault struct {
+	// rp is the repository for exchange rate entity.
+	rp internal.RepositoryExchangeRate
+}
+
+// FindAll returns all exchange rates.
+func (s *ExchangeRatesDefault) FindAll(ctx context.Context) (e []internal.ExchangeRate, err error) {
+	e, err = s.rp.FindAll(ctx)
+	return
+}
+
+// Save saves the exchange rate.
+func (s *ExchangeRatesDefault) Save(ctx context.Context, e *internal.ExchangeRate) (err error) {
+	err = s.rp.Save(ctx, e)
+	return
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewExchangeRatesTraced creates new tracing service for exchange rate entity, decorating sv.
+func NewExchangeRatesTraced(sv internal.ServiceExchangeRate) *ExchangeRatesTraced {
+	return &ExchangeRatesTraced{sv: sv}
+}
+
+// ExchangeRatesTraced is the tracing service implementation for exchange rate entity.
+// Every call is traced as a child span of the span carried by the context.
+type ExchangeRatesTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceExchangeRate
+}
+
+// FindAll returns all exchange rates.
+func (s *ExchangeRatesTraced) FindAll(ctx context.Context) ([]internal.ExchangeRate, error) {
+	return traced(ctx, "exchange_rates.FindAll", s.sv.FindAll)
+}
+
+// Save saves the exchange rate.
+func (s *ExchangeRatesTraced) Save(ctx context.Context, e *internal.ExchangeRate) error {
+	return tracedErr(ctx, "exchange_rates.Save", func(ctx context.Context) error { return s.sv.Save(ctx, e) })
+}
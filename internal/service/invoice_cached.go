@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"app/internal"
+	"app/platform/cache"
+)
+
+// NewInvoicesCached creates new caching service for invoice entity, decorating sv.
+func NewInvoicesCached(sv internal.ServiceInvoice, c cache.Cache, ttl time.Duration) *InvoicesCached {
+	return &InvoicesCached{sv: sv, c: c, ttl: ttl}
+}
+
+// InvoicesCached is the caching service implementation for invoice entity.
+// It caches the invoices total by customer condition report.
+type InvoicesCached struct {
+	// sv is the decorated service.
+	sv internal.ServiceInvoice
+	// c is the cache.
+	c cache.Cache
+	// ttl is the time to live of the cached reports.
+	ttl time.Duration
+}
+
+// FindAll returns all invoices.
+func (s *InvoicesCached) FindAll(ctx context.Context) (i []internal.Invoice, err error) {
+	i, err = s.sv.FindAll(ctx)
+	return
+}
+
+// Save saves the invoice and invalidates the reports built from invoice totals.
+func (s *InvoicesCached) Save(ctx context.Context, i *internal.Invoice) (err error) {
+	err = s.sv.Save(ctx, i)
+	if err != nil {
+		return
+	}
+
+	s.c.DeletePrefix(CacheKeyTopCustomers, CacheKeyInvoicesTotalByCustomerCondition)
+	return
+}
+
+// UpdateInvoicesTotal updates the invoices total and invalidates the reports built from them.
+func (s *InvoicesCached) UpdateInvoicesTotal(ctx context.Context) error {
+	// invalidate even on error, as the update may have been partially applied
+	defer s.c.DeletePrefix(CacheKeyTopCustomers, CacheKeyInvoicesTotalByCustomerCondition)
+
+	return s.sv.UpdateInvoicesTotal(ctx)
+}
+
+// GetInvoicesTotalByCustomerCondition returns the invoices total by customer condition converted to currency,
+// from the cache if present.
+func (s *InvoicesCached) GetInvoicesTotalByCustomerCondition(ctx context.Context, currency internal.Currency) ([]internal.InvoiceTotalByCustomerCondition, error) {
+	return readThrough(ctx, s.c, currencyKey(CacheKeyInvoicesTotalByCustomerCondition, currency), s.ttl, func(ctx context.Context) ([]internal.InvoiceTotalByCustomerCondition, error) {
+		return s.sv.GetInvoicesTotalByCustomerCondition(ctx, currency)
+	})
+}
@@ -1,6 +1,10 @@
 package service
 
-import "app/internal"
+import (
+	"context"
+
+	"app/internal"
+)
 
 // NewInvoicesDefault creates new default service for invoice entity.
 func NewInvoicesDefault(rp internal.RepositoryInvoice) *InvoicesDefault {
@@ -14,21 +18,21 @@ type InvoicesDefault struct {
 }
 
 // FindAll returns all invoices.
-func (s *InvoicesDefault) FindAll() (i []internal.Invoice, err error) {
-	i, err = s.rp.FindAll()
+func (s *InvoicesDefault) FindAll(ctx context.Context) (i []internal.Invoice, err error) {
+	i, err = s.rp.FindAll(ctx)
 	return
 }
 
 // Save saves the invoice.
-func (s *InvoicesDefault) Save(i *internal.Invoice) (err error) {
-	err = s.rp.Save(i)
+func (s *InvoicesDefault) Save(ctx context.Context, i *internal.Invoice) (err error) {
+	err = s.rp.Save(ctx, i)
 	return
 }
 
-func (s *InvoicesDefault) UpdateInvoicesTotal() error {
-	return s.rp.UpdateInvoicesTotal()
+func (s *InvoicesDefault) UpdateInvoicesTotal(ctx context.Context) error {
+	return s.rp.UpdateInvoicesTotal(ctx)
 }
 
-func (s *InvoicesDefault) GetInvoicesTotalByCustomerCondition() ([]internal.InvoiceTotalByCustomerCondition, error) {
-	return s.rp.GetInvoicesTotalByCustomerCondition()
+func (s *InvoicesDefault) GetInvoicesTotalByCustomerCondition(ctx context.Context, currency internal.Currency) ([]internal.InvoiceTotalByCustomerCondition, error) {
+	return s.rp.GetInvoicesTotalByCustomerCondition(ctx, currency)
 }
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewInvoicesTraced creates new tracing service for invoice entity, decorating sv.
+func NewInvoicesTraced(sv internal.ServiceInvoice) *InvoicesTraced {
+	return &InvoicesTraced{sv: sv}
+}
+
+// InvoicesTraced is the tracing service implementation for invoice entity.
+// Every call is traced as a child span of the span carried by the context.
+type InvoicesTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceInvoice
+}
+
+// FindAll returns all invoices.
+func (s *InvoicesTraced) FindAll(ctx context.Context) ([]internal.Invoice, error) {
+	return traced(ctx, "invoices.FindAll", s.sv.FindAll)
+}
+
+// Save saves the invoice.
+func (s *InvoicesTraced) Save(ctx context.Context, i *internal.Invoice) error {
+	return tracedErr(ctx, "invoices.Save", func(ctx context.Context) error { return s.sv.Save(ctx, i) })
+}
+
+// UpdateInvoicesTotal recalculates the total of every invoice.
+func (s *InvoicesTraced) UpdateInvoicesTotal(ctx context.Context) error {
+	return tracedErr(ctx, "invoices.UpdateInvoicesTotal", s.sv.UpdateInvoicesTotal)
+}
+
+// GetInvoicesTotalByCustomerCondition returns the invoiced total grouped by customer condition, converted to currency.
+func (s *InvoicesTraced) GetInvoicesTotalByCustomerCondition(ctx context.Context, currency internal.Currency) ([]internal.InvoiceTotalByCustomerCondition, error) {
+	return traced(ctx, "invoices.GetInvoicesTotalByCustomerCondition", func(ctx context.Context) ([]internal.InvoiceTotalByCustomerCondition, error) {
+		return s.sv.GetInvoicesTotalByCustomerCondition(ctx, currency)
+	})
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"app/internal"
+	"app/platform/cache"
+)
+
+// NewProductsCached creates new caching service for product entity, decorating sv.
+func NewProductsCached(sv internal.ServiceProduct, c cache.Cache, ttl time.Duration) *ProductsCached {
+	return &ProductsCached{sv: sv, c: c, ttl: ttl}
+}
+
+// ProductsCached is the caching service implementation for product entity.
+// It caches the top products report.
+type ProductsCached struct {
+	// sv is the decorated service.
+	sv internal.ServiceProduct
+	// c is the cache.
+	c cache.Cache
+	// ttl is the time to live of the cached reports.
+	ttl time.Duration
+}
+
+// FindAll returns all products.
+func (s *ProductsCached) FindAll(ctx context.Context) (p []internal.Product, err error) {
+	p, err = s.sv.FindAll(ctx)
+	return
+}
+
+// Save saves the product.
+// A new product has no sales yet, so no cached report is affected.
+func (s *ProductsCached) Save(ctx context.Context, p *internal.Product) (err error) {
+	err = s.sv.Save(ctx, p)
+	return
+}
+
+// GetTopProducts returns the top products, from the cache if present.
+func (s *ProductsCached) GetTopProducts(ctx context.Context) ([]internal.TopProduct, error) {
+	return readThrough(ctx, s.c, CacheKeyTopProducts, s.ttl, s.sv.GetTopProducts)
+}
+
+// FindPrices returns the price history of the product id.
+func (s *ProductsCached) FindPrices(ctx context.Context, id int) (pp []internal.ProductPrice, err error) {
+	pp, err = s.sv.FindPrices(ctx, id)
+	return
+}
+
+// UpdatePrice changes the price of the product.
+// Sales keep the price they were saved at, so no cached report is affected.
+func (s *ProductsCached) UpdatePrice(ctx context.Context, p *internal.Product) (err error) {
+	err = s.sv.UpdatePrice(ctx, p)
+	return
+}
+
+// UpdateArchived archives or restores the product.
+// The sales of an archived product stay in the history, so no cached report is affected.
+func (s *ProductsCached) UpdateArchived(ctx context.Context, p *internal.Product) (err error) {
+	err = s.sv.UpdateArchived(ctx, p)
+	return
+}
+
+// Search returns the page of q of the products matching its text.
+// Searches are not cached, as their queries rarely repeat.
+func (s *ProductsCached) Search(ctx context.Context, q internal.SearchQuery) (m internal.ProductMatches, err error) {
+	m, err = s.sv.Search(ctx, q)
+	return
+}
@@ -1,6 +1,10 @@
 package service
 
-import "app/internal"
+import (
+	"context"
+
+	"app/internal"
+)
 
 // NewProductsDefault creates new default service for product entity.
 func NewProductsDefault(rp internal.RepositoryProduct) *ProductsDefault {
@@ -14,17 +18,41 @@ type ProductsDefault struct {
 }
 
 // FindAll returns all products.
-func (s *ProductsDefault) FindAll() (p []internal.Product, err error) {
-	p, err = s.rp.FindAll()
+func (s *ProductsDefault) FindAll(ctx context.Context) (p []internal.Product, err error) {
+	p, err = s.rp.FindAll(ctx)
 	return
 }
 
 // Save saves the product.
-func (s *ProductsDefault) Save(p *internal.Product) (err error) {
-	err = s.rp.Save(p)
+func (s *ProductsDefault) Save(ctx context.Context, p *internal.Product) (err error) {
+	err = s.rp.Save(ctx, p)
 	return
 }
 
-func (s *ProductsDefault) GetTopProducts() ([]internal.TopProduct, error) {
-	return s.rp.GetTopProducts()
+func (s *ProductsDefault) GetTopProducts(ctx context.Context) ([]internal.TopProduct, error) {
+	return s.rp.GetTopProducts(ctx)
+}
+
+// FindPrices returns the price history of the product id.
+func (s *ProductsDefault) FindPrices(ctx context.Context, id int) (pp []internal.ProductPrice, err error) {
+	pp, err = s.rp.FindPrices(ctx, id)
+	return
+}
+
+// UpdatePrice changes the price of the product.
+func (s *ProductsDefault) UpdatePrice(ctx context.Context, p *internal.Product) (err error) {
+	err = s.rp.UpdatePrice(ctx, p)
+	return
+}
+
+// UpdateArchived archives or restores the product.
+func (s *ProductsDefault) UpdateArchived(ctx context.Context, p *internal.Product) (err error) {
+	err = s.rp.UpdateArchived(ctx, p)
+	return
+}
+
+// Search returns the page of q of the products matching its text.
+func (s *ProductsDefault) Search(ctx context.Context, q internal.SearchQuery) (m internal.ProductMatches, err error) {
+	m, err = s.rp.Search(ctx, q)
+	return
 }
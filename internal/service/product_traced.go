@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewProductsTraced creates new tracing service for product entity, decorating sv.
+func NewProductsTraced(sv internal.ServiceProduct) *ProductsTraced {
+	return &ProductsTraced{sv: sv}
+}
+
+// ProductsTraced is the tracing service implementation for product entity.
+// Every call is traced as a child span of the span carried by the context.
+type ProductsTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceProduct
+}
+
+// FindAll returns all products.
+func (s *ProductsTraced) FindAll(ctx context.Context) ([]internal.Product, error) {
+	return traced(ctx, "products.FindAll", s.sv.FindAll)
+}
+
+// Save saves the product.
+func (s *ProductsTraced) Save(ctx context.Context, p *internal.Product) error {
+	return tracedErr(ctx, "products.Save", func(ctx context.Context) error { return s.sv.Save(ctx, p) })
+}
+
+// GetTopProducts returns the top products.
+func (s *ProductsTraced) GetTopProducts(ctx context.Context) ([]internal.TopProduct, error) {
+	return traced(ctx, "products.GetTopProducts", s.sv.GetTopProducts)
+}
+
+// FindPrices returns the price history of the product id.
+func (s *ProductsTraced) FindPrices(ctx context.Context, id int) ([]internal.ProductPrice, error) {
+	return traced(ctx, "products.FindPrices", func(ctx context.Context) ([]internal.ProductPrice, error) { return s.sv.FindPrices(ctx, id) })
+}
+
+// UpdatePrice changes the price of the product.
+func (s *ProductsTraced) UpdatePrice(ctx context.Context, p *internal.Product) error {
+	return tracedErr(ctx, "products.UpdatePrice", func(ctx context.Context) error { return s.sv.UpdatePrice(ctx, p) })
+}
+
+// UpdateArchived archives or restores the product.
+func (s *ProductsTraced) UpdateArchived(ctx context.Context, p *internal.Product) error {
+	return tracedErr(ctx, "products.UpdateArchived", func(ctx context.Context) error { return s.sv.UpdateArchived(ctx, p) })
+}
+
+// Search returns the page of q of the products matching its text.
+func (s *ProductsTraced) Search(ctx context.Context, q internal.SearchQuery) (internal.ProductMatches, error) {
+	return traced(ctx, "products.Search", func(ctx context.Context) (internal.ProductMatches, error) { return s.sv.Search(ctx, q) })
+}
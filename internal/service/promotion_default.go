@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewPromotionsDefault creates new default service for promotion entity.
+func NewPromotionsDefault(rp internal.RepositoryPromotion) *PromotionsDefault {
+	return &PromotionsDefault{rp}
+}
+
+// PromotionsDefault is the default service implementation for promotion entity.
+type PromotionsDefault struct {
+	// rp is the repository for promotion entity.
+	rp internal.RepositoryPromotion
+}
+
+// FindAll returns all promotions.
+func (s *PromotionsDefault) FindAll(ctx context.Context) (p []internal.Promotion, err error) {
+	p, err = s.rp.FindAll(ctx)
+	return
+}
+
+// Save saves the promotion.
+func (s *PromotionsDefault) Save(ctx context.Context, p *internal.Promotion) (err error) {
+	err = s.rp.Save(ctx, p)
+	return
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewPromotionsTraced creates new tracing service for promotion entity, decorating sv.
+func NewPromotionsTraced(sv internal.ServicePromotion) *PromotionsTraced {
+	return &PromotionsTraced{sv: sv}
+}
+
+// PromotionsTraced is the tracing service implementation for promotion entity.
+// Every call is traced as a child span of the span carried by the context.
+type PromotionsTraced struct {
+	// sv is the decorated service.
+	sv internal.ServicePromotion
+}
+
+// FindAll returns all promotions.
+func (s *PromotionsTraced) FindAll(ctx context.Context) ([]internal.Promotion, error) {
+	return traced(ctx, "promotions.FindAll", s.sv.FindAll)
+}
+
+// Save saves the promotion.
+func (s *PromotionsTraced) Save(ctx context.Context, p *internal.Promotion) error {
+	return tracedErr(ctx, "promotions.Save", func(ctx context.Context) error { return s.sv.Save(ctx, p) })
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"app/internal"
+)
+
+// NewReorderDefault creates new default service for the reorder suggestions and the stock alerts, computed with
+// policy. If webhook is true, every new alert is queued for the outbound webhook.
+func NewReorderDefault(rpStock internal.RepositoryStock, rpAlert internal.RepositoryStockAlert, policy internal.ReorderPolicy, webhook bool) *ReorderDefault {
+	return &ReorderDefault{rpStock: rpStock, rpAlert: rpAlert, policy: policy, webhook: webhook, now: time.Now}
+}
+
+// ReorderDefault is the default service implementation for the reorder suggestions and the stock alerts.
+type ReorderDefault struct {
+	// rpStock is the repository for stock entity.
+	rpStock internal.RepositoryStock
+	// rpAlert is the repository for stock alert entity.
+	rpAlert internal.RepositoryStockAlert
+	// policy is the policy of the suggestions.
+	policy internal.ReorderPolicy
+	// webhook reports whether the new alerts are queued for the outbound webhook.
+	webhook bool
+	// now returns the current time.
+	now func() time.Time
+}
+
+// stockAlertEvent is the payload of the WebhookEventStockLow event.
+type stockAlertEvent struct {
+	Date        string  `json:"date"`
+	ProductId   int     `json:"product_id"`
+	Description string  `json:"description"`
+	Stock       int     `json:"stock"`
+	Sold        int     `json:"sold"`
+	DailySales  float64 `json:"daily_sales"`
+	DaysOfCover float64 `json:"days_of_cover"`
+	Quantity    int     `json:"quantity"`
+}
+
+// Suggestions returns the reorder suggestions of the products whose stock runs low.
+func (s *ReorderDefault) Suggestions(ctx context.Context) (r []internal.ReorderSuggestion, err error) {
+	st, err := s.rpStock.FindStockSales(ctx, s.policy.Since(s.now()))
+	if err != nil {
+		return
+	}
+	r = s.policy.Suggestions(st)
+	return
+}
+
+// Alerts returns all stock alerts.
+func (s *ReorderDefault) Alerts(ctx context.Context) (a []internal.StockAlert, err error) {
+	a, err = s.rpAlert.FindAll(ctx)
+	return
+}
+
+// RaiseAlerts saves an alert, dated today, for every product whose stock runs low and that was not alerted today.
+func (s *ReorderDefault) RaiseAlerts(ctx context.Context) (a []internal.StockAlert, err error) {
+	r, err := s.Suggestions(ctx)
+	if err != nil {
+		return
+	}
+
+	date := s.now().UTC().Format(time.DateOnly)
+	a = []internal.StockAlert{}
+	for _, v := range r {
+		al := internal.StockAlert{Date: date, ReorderSuggestion: v}
+		var d *internal.WebhookDelivery
+		if s.webhook {
+			d = &internal.WebhookDelivery{Event: internal.WebhookEventStockLow}
+			d.Payload, err = json.Marshal(stockAlertEvent{
+				Date:        al.Date,
+				ProductId:   v.ProductId,
+				Description: v.Description,
+				Stock:       v.Stock,
+				Sold:        v.Sold,
+				DailySales:  v.DailySales,
+				DaysOfCover: v.DaysOfCover,
+				Quantity:    v.Quantity,
+			})
+			if err != nil {
+				return
+			}
+		}
+
+		err = s.rpAlert.Save(ctx, &al, d)
+		if errors.Is(err, internal.ErrStockAlertExists) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			return
+		}
+		a = append(a, al)
+	}
+	return
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"app/platform/webhook"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the reorder and webhook services
+func TestReorderWebhook(t *testing.T) {
+	t.Run("alerts are raised once a day and delivered to the webhook, retrying the failures", func(t *testing.T) {
+		// arrange
+		// - webhook: rejects the first request
+		var (
+			mu       sync.Mutex
+			requests int
+			events   []map[string]any
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, webhook.Sign("s3cret", body), r.Header.Get(webhook.HeaderSignature))
+			var ev map[string]any
+			require.NoError(t, json.Unmarshal(body, &ev))
+			events = append(events, ev)
+		}))
+		defer srv.Close()
+		// - a product selling 30 units a month with 2 left
+		db := repository.NewMemoryDB()
+		cs := internal.Customer{CustomerAttributes: internal.CustomerAttributes{FirstName: "John", LastName: "Doe"}}
+		require.NoError(t, repository.NewCustomersMemory(db).Save(context.Background(), &cs))
+		pr := internal.Product{ProductAttributes: internal.ProductAttributes{Description: "Product 1", Price: internal.MustParseMoney("1")}}
+		require.NoError(t, repository.NewProductsMemory(db).Save(context.Background(), &pr))
+		rpStock := repository.NewStocksMemory(db)
+		m := internal.StockMovement{StockMovementAttributes: internal.StockMovementAttributes{ProductId: pr.Id, Kind: internal.StockMovementReceipt, Quantity: 32}}
+		require.NoError(t, rpStock.SaveMovement(context.Background(), &m))
+		iv := internal.Invoice{InvoiceAttributes: internal.InvoiceAttributes{Datetime: time.Now().UTC().Format(time.DateTime), CustomerId: cs.Id}}
+		require.NoError(t, repository.NewInvoicesMemory(db).Save(context.Background(), &iv))
+		sa := internal.Sale{SaleAttributes: internal.SaleAttributes{Quantity: 30, ProductId: pr.Id, InvoiceId: iv.Id}}
+		require.NoError(t, repository.NewSalesMemory(db).Save(context.Background(), &sa))
+
+		svReorder := service.NewReorderDefault(rpStock, repository.NewStockAlertsMemory(db), internal.ReorderPolicyDefault, true)
+		svWebhook := service.NewWebhookDefault(repository.NewWebhooksMemory(db), webhook.New(webhook.Config{URL: srv.URL, Secret: "s3cret"}), 5, 10)
+
+		// act
+		first, err1 := svReorder.RaiseAlerts(context.Background())
+		second, err2 := svReorder.RaiseAlerts(context.Background())
+		nFailed, errFailed := svWebhook.Deliver(context.Background())
+		nRetried, errRetried := svWebhook.Deliver(context.Background())
+		nNone, errNone := svWebhook.Deliver(context.Background())
+
+		// assert
+		require.NoError(t, err1)
+		require.Len(t, first, 1)
+		require.Equal(t, 28, first[0].Quantity)
+		require.NoError(t, err2)
+		require.Empty(t, second)
+		require.NoError(t, errFailed)
+		require.Equal(t, 0, nFailed)
+		require.NoError(t, errRetried)
+		require.Equal(t, 1, nRetried)
+		require.NoError(t, errNone)
+		require.Equal(t, 0, nNone)
+		require.Equal(t, 2, requests)
+		require.Equal(t, []map[string]any{{
+			"date":          first[0].Date,
+			"product_id":    float64(pr.Id),
+			"description":   "Product 1",
+			"stock":         float64(2),
+			"sold":          float64(30),
+			"daily_sales":   float64(1),
+			"days_of_cover": float64(2),
+			"quantity":      float64(28),
+		}}, events)
+	})
+}
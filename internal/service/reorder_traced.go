@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewReorderTraced creates new tracing service for the reorder suggestions and the stock alerts, decorating sv.
+func NewReorderTraced(sv internal.ServiceReorder) *ReorderTraced {
+	return &ReorderTraced{sv: sv}
+}
+
+// ReorderTraced is the tracing service implementation for the reorder suggestions and the stock alerts.
+// Every call is traced as a child span of the span carried by the context.
+type ReorderTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceReorder
+}
+
+// Suggestions returns the reorder suggestions of the products whose stock runs low.
+func (s *ReorderTraced) Suggestions(ctx context.Context) ([]internal.ReorderSuggestion, error) {
+	return traced(ctx, "reorder.Suggestions", func(ctx context.Context) ([]internal.ReorderSuggestion, error) { return s.sv.Suggestions(ctx) })
+}
+
+// Alerts returns all stock alerts.
+func (s *ReorderTraced) Alerts(ctx context.Context) ([]internal.StockAlert, error) {
+	return traced(ctx, "reorder.Alerts", func(ctx context.Context) ([]internal.StockAlert, error) { return s.sv.Alerts(ctx) })
+}
+
+// RaiseAlerts saves an alert for every product whose stock runs low and that was not alerted today.
+func (s *ReorderTraced) RaiseAlerts(ctx context.Context) ([]internal.StockAlert, error) {
+	return traced(ctx, "reorder.RaiseAlerts", func(ctx context.Context) ([]internal.StockAlert, error) { return s.sv.RaiseAlerts(ctx) })
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+	"app/platform/cache"
+)
+
+// NewSalesCached creates new caching service for sale entity, decorating sv.
+// Sales are not cached, but saving one invalidates the reports built from sales.
+func NewSalesCached(sv internal.ServiceSale, c cache.Cache) *SalesCached {
+	return &SalesCached{sv: sv, c: c}
+}
+
+// SalesCached is the caching service implementation for sale entity.
+type SalesCached struct {
+	// sv is the decorated service.
+	sv internal.ServiceSale
+	// c is the cache.
+	c cache.Cache
+}
+
+// FindAll returns all sales.
+func (sv *SalesCached) FindAll(ctx context.Context) (s []internal.Sale, err error) {
+	s, err = sv.sv.FindAll(ctx)
+	return
+}
+
+// Save saves the sale and invalidates the top products report.
+func (sv *SalesCached) Save(ctx context.Context, s *internal.Sale) (err error) {
+	err = sv.sv.Save(ctx, s)
+	if err != nil {
+		return
+	}
+
+	sv.c.Delete(CacheKeyTopProducts)
+	return
+}
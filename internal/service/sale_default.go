@@ -1,6 +1,10 @@
 package service
 
-import "app/internal"
+import (
+	"context"
+
+	"app/internal"
+)
 
 // NewSalesDefault creates new default service for sale entity.
 func NewSalesDefault(rp internal.RepositorySale) *SalesDefault {
@@ -14,13 +18,13 @@ type SalesDefault struct {
 }
 
 // FindAll returns all sales.
-func (sv *SalesDefault) FindAll() (s []internal.Sale, err error) {
-	s, err = sv.rp.FindAll()
+func (sv *SalesDefault) FindAll(ctx context.Context) (s []internal.Sale, err error) {
+	s, err = sv.rp.FindAll(ctx)
 	return
 }
 
 // Save saves the sale.
-func (sv *SalesDefault) Save(s *internal.Sale) (err error) {
-	err = sv.rp.Save(s)
+func (sv *SalesDefault) Save(ctx context.Context, s *internal.Sale) (err error) {
+	err = sv.rp.Save(ctx, s)
 	return
 }
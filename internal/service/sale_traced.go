@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewSalesTraced creates new tracing service for sale entity, decorating sv.
+func NewSalesTraced(sv internal.ServiceSale) *SalesTraced {
+	return &SalesTraced{sv: sv}
+}
+
+// SalesTraced is the tracing service implementation for sale entity.
+// Every call is traced as a child span of the span carried by the context.
+type SalesTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceSale
+}
+
+// FindAll returns all sales.
+func (sv *SalesTraced) FindAll(ctx context.Context) ([]internal.Sale, error) {
+	return traced(ctx, "sales.FindAll", sv.sv.FindAll)
+}
+
+// Save saves the sale.
+func (sv *SalesTraced) Save(ctx context.Context, s *internal.Sale) error {
+	return tracedErr(ctx, "sales.Save", func(ctx context.Context) error { return sv.sv.Save(ctx, s) })
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewStocksDefault creates new default service for stock entity.
+func NewStocksDefault(rp internal.RepositoryStock) *StocksDefault {
+	return &StocksDefault{rp}
+}
+
+// StocksDefault is the default service implementation for stock entity.
+type StocksDefault struct {
+	// rp is the repository for stock entity.
+	rp internal.RepositoryStock
+}
+
+// FindByProduct returns the stock of the product id.
+func (s *StocksDefault) FindByProduct(ctx context.Context, id int) (st internal.ProductStock, err error) {
+	st, err = s.rp.FindByProduct(ctx, id)
+	return
+}
+
+// FindMovements returns the stock movements of the product id.
+func (s *StocksDefault) FindMovements(ctx context.Context, id int) (m []internal.StockMovement, err error) {
+	m, err = s.rp.FindMovements(ctx, id)
+	return
+}
+
+// SaveMovement saves the receipt or adjustment.
+func (s *StocksDefault) SaveMovement(ctx context.Context, m *internal.StockMovement) (err error) {
+	err = s.rp.SaveMovement(ctx, m)
+	return
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewStocksTraced creates new tracing service for stock entity, decorating sv.
+func NewStocksTraced(sv internal.ServiceStock) *StocksTraced {
+	return &StocksTraced{sv: sv}
+}
+
+// StocksTraced is the tracing service implementation for stock entity.
+// Every call is traced as a child span of the span carried by the context.
+type StocksTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceStock
+}
+
+// FindByProduct returns the stock of the product id.
+func (s *StocksTraced) FindByProduct(ctx context.Context, id int) (internal.ProductStock, error) {
+	return traced(ctx, "stocks.FindByProduct", func(ctx context.Context) (internal.ProductStock, error) { return s.sv.FindByProduct(ctx, id) })
+}
+
+// FindMovements returns the stock movements of the product id.
+func (s *StocksTraced) FindMovements(ctx context.Context, id int) ([]internal.StockMovement, error) {
+	return traced(ctx, "stocks.FindMovements", func(ctx context.Context) ([]internal.StockMovement, error) { return s.sv.FindMovements(ctx, id) })
+}
+
+// SaveMovement saves the receipt or adjustment.
+func (s *StocksTraced) SaveMovement(ctx context.Context, m *internal.StockMovement) error {
+	return tracedErr(ctx, "stocks.SaveMovement", func(ctx context.Context) error { return s.sv.SaveMovement(ctx, m) })
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewTaxRatesDefault creates new default service for tax rate entity.
+func NewTaxRatesDefault(rp internal.RepositoryTaxRate) *TaxRatesDefault {
+	return &TaxRatesDefault{rp}
+}
+
+// TaxRatesDefault is the default service implementation for tax rate entity.
+type TaxRatesDefault struct {
+	// rp is the repository for tax rate entity.
+	rp internal.RepositoryTaxRate
+}
+
+// FindAll returns all tax rates.
+func (s *TaxRatesDefault) FindAll(ctx context.Context) (t []internal.TaxRate, err error) {
+	t, err = s.rp.FindAll(ctx)
+	return
+}
+
+// Save saves the tax rate.
+func (s *TaxRatesDefault) Save(ctx context.Context, t *internal.TaxRate) (err error) {
+	err = s.rp.Save(ctx, t)
+	return
+}
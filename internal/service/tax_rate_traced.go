@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewTaxRatesTraced creates new tracing service for tax rate entity, decorating sv.
+func NewTaxRatesTraced(sv internal.ServiceTaxRate) *TaxRatesTraced {
+	return &TaxRatesTraced{sv: sv}
+}
+
+// TaxRatesTraced is the tracing service implementation for tax rate entity.
+// Every call is traced as a child span of the span carried by the context.
+type TaxRatesTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceTaxRate
+}
+
+// FindAll returns all tax rates.
+func (s *TaxRatesTraced) FindAll(ctx context.Context) ([]internal.TaxRate, error) {
+	return traced(ctx, "tax_rates.FindAll", s.sv.FindAll)
+}
+
+// Save saves the tax rate.
+func (s *TaxRatesTraced) Save(ctx context.Context, t *internal.TaxRate) error {
+	return tracedErr(ctx, "tax_rates.Save", func(ctx context.Context) error { return s.sv.Save(ctx, t) })
+}
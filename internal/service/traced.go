@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"app/platform/trace"
+)
+
+// traced runs fn within a span named "service.<name>", child of the span carried by ctx, if any.
+func traced[T any](ctx context.Context, name string, fn func(context.Context) (T, error)) (v T, err error) {
+	ctx, sp := trace.Start(ctx, "service."+name)
+	defer func() { sp.End(err) }()
+
+	v, err = fn(ctx)
+	return
+}
+
+// tracedErr is traced for functions that only return an error.
+func tracedErr(ctx context.Context, name string, fn func(context.Context) error) (err error) {
+	_, err = traced(ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return
+}
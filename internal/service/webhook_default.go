@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"app/internal"
+)
+
+// WebhookSender is the interface that wraps the method posting an event to the outbound webhook.
+type WebhookSender interface {
+	// Send posts the JSON payload of the event of the delivery id.
+	Send(ctx context.Context, event string, id int, payload []byte) (err error)
+}
+
+// webhookErrorMaxLen is the maximum length of the last error of a delivery, the one of its column.
+const webhookErrorMaxLen = 255
+
+// NewWebhookDefault creates new default service for the outbound webhook queue. Every call of Deliver posts at most
+// batch deliveries, each attempted maxAttempts times at most.
+func NewWebhookDefault(rp internal.RepositoryWebhook, sender WebhookSender, maxAttempts, batch int) *WebhookDefault {
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	if batch <= 0 {
+		batch = 100
+	}
+	return &WebhookDefault{rp: rp, sender: sender, maxAttempts: maxAttempts, batch: batch}
+}
+
+// WebhookDefault is the default service implementation for the outbound webhook queue.
+type WebhookDefault struct {
+	// rp is the repository for webhook delivery entity.
+	rp internal.RepositoryWebhook
+	// sender posts the events.
+	sender WebhookSender
+	// maxAttempts is the number of attempts after which a delivery is given up.
+	maxAttempts int
+	// batch is the number of deliveries posted by a call.
+	batch int
+}
+
+// Deliver posts the pending deliveries to the webhook, oldest first, returning the number of delivered ones.
+// A failed delivery is kept pending with its attempts and error, so it is retried on the next call.
+func (s *WebhookDefault) Deliver(ctx context.Context) (n int, err error) {
+	d, err := s.rp.FindPending(ctx, s.maxAttempts, s.batch)
+	if err != nil {
+		return
+	}
+
+	for _, v := range d {
+		if ctx.Err() != nil {
+			return n, ctx.Err()
+		}
+
+		errSend := s.sender.Send(ctx, v.Event, v.Id, v.Payload)
+		if errSend != nil {
+			v.Attempts++
+			v.LastError = truncate(errSend.Error(), webhookErrorMaxLen)
+		} else {
+			v.Delivered = true
+			v.LastError = ""
+			n++
+		}
+
+		err = s.rp.Update(ctx, v)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// truncate returns s cut to at most max runes.
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"app/internal"
+)
+
+// NewWebhookTraced creates new tracing service for the outbound webhook queue, decorating sv.
+func NewWebhookTraced(sv internal.ServiceWebhook) *WebhookTraced {
+	return &WebhookTraced{sv: sv}
+}
+
+// WebhookTraced is the tracing service implementation for the outbound webhook queue.
+// Every call is traced as a child span of the span carried by the context.
+type WebhookTraced struct {
+	// sv is the decorated service.
+	sv internal.ServiceWebhook
+}
+
+// Deliver posts the pending deliveries to the webhook, returning the number of delivered ones.
+func (s *WebhookTraced) Deliver(ctx context.Context) (int, error) {
+	return traced(ctx, "webhook.Deliver", func(ctx context.Context) (int, error) { return s.sv.Deliver(ctx) })
+}
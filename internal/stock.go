@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrStockInsufficient is used when a warehouse holds fewer units of a product than a sale or an adjustment takes.
+	ErrStockInsufficient = errors.New("insufficient stock")
+	// ErrStockMovementInvalid is used when a movement does not move its units in the direction of its kind,
+	// e.g. a sale that would add units.
+	ErrStockMovementInvalid = errors.New("invalid stock movement")
+)
+
+// WarehouseDefault is the warehouse of the sales and stock movements recorded without one.
+const WarehouseDefault = "main"
+
+// untrackedKey is the context key of the creation of products whose stock is not tracked.
+type untrackedKey struct{}
+
+// WithStockUntracked returns a copy of ctx under which the products created do not track their stock, so their sales
+// are not limited until their first receipt or adjustment, e.g. to import a catalog along with its past sales.
+func WithStockUntracked(ctx context.Context) context.Context {
+	return context.WithValue(ctx, untrackedKey{}, true)
+}
+
+// StockUntracked reports whether the products created under ctx do not track their stock.
+func StockUntracked(ctx context.Context) bool {
+	untracked, _ := ctx.Value(untrackedKey{}).(bool)
+	return untracked
+}
+
+// Stock movement kinds.
+const (
+	// StockMovementReceipt is the receipt of units into a warehouse.
+	StockMovementReceipt = "receipt"
+	// StockMovementAdjustment is a correction of the units of a warehouse, e.g. after a count, in either direction.
+	StockMovementAdjustment = "adjustment"
+	// StockMovementSale is the units taken out of a warehouse by a sale.
+	StockMovementSale = "sale"
+)
+
+// StockMovementAttributes is the struct that represents the attributes of a stock movement.
+type StockMovementAttributes struct {
+	// ProductId is the product id of the movement.
+	ProductId int
+	// Warehouse is the warehouse of the movement.
+	Warehouse string
+	// Kind is the kind of the movement, StockMovementReceipt, StockMovementAdjustment or StockMovementSale.
+	Kind string
+	// Quantity is the change of the stock: positive for the units in, negative for the units out.
+	Quantity int
+	// SaleId is the sale id of a StockMovementSale movement, 0 otherwise.
+	SaleId int
+	// Datetime is the datetime of the movement, YYYY-MM-DD HH:MM:SS in UTC, set by the repository.
+	Datetime string
+}
+
+// StockMovement is the struct that represents a stock movement.
+type StockMovement struct {
+	// Id is the unique identifier of the stock movement.
+	Id int
+	// StockMovementAttributes is the attributes of the stock movement.
+	StockMovementAttributes
+}
+
+// WarehouseStock is the struct that represents the units of a product held in a warehouse.
+type WarehouseStock struct {
+	// Warehouse is the warehouse holding the units.
+	Warehouse string
+	// Quantity is the number of units held.
+	Quantity int
+}
+
+// ProductStock is the struct that represents the stock of a product.
+type ProductStock struct {
+	// ProductId is the product id of the stock.
+	ProductId int
+	// Tracked reports whether the stock of the product is tracked: from its creation, or for the products created
+	// untracked, from their first receipt or adjustment. The sales of a product whose stock is not tracked are not limited.
+	Tracked bool
+	// Quantity is the number of units held in every warehouse.
+	Quantity int
+	// Warehouses are the units held in each warehouse, by warehouse.
+	Warehouses []WarehouseStock
+}
@@ -0,0 +1,12 @@
+package internal
+
+import "context"
+
+// RepositoryStockAlert is the interface that wraps the basic methods that a stock alert repository should implement.
+type RepositoryStockAlert interface {
+	// FindAll returns all stock alerts saved in the database, oldest first.
+	FindAll(ctx context.Context) (a []StockAlert, err error)
+	// Save saves a stock alert and, if d is not nil, queues d for the outbound webhook in the same transaction,
+	// setting their ids. It fails with ErrStockAlertExists if the product was already alerted on the date of a.
+	Save(ctx context.Context, a *StockAlert, d *WebhookDelivery) (err error)
+}
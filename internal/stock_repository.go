@@ -0,0 +1,17 @@
+package internal
+
+import "context"
+
+// RepositoryStock is the interface that wraps the basic methods that a stock repository should implement.
+type RepositoryStock interface {
+	// FindByProduct returns the stock of the product id. It fails with ErrProductNotFound.
+	FindByProduct(ctx context.Context, id int) (s ProductStock, err error)
+	// FindMovements returns the stock movements of the product id, oldest first. It fails with ErrProductNotFound.
+	FindMovements(ctx context.Context, id int) (m []StockMovement, err error)
+	// SaveMovement saves a receipt or an adjustment, changing the stock of its warehouse and setting its datetime.
+	// It fails with ErrProductNotFound, and with ErrStockInsufficient if the stock would go below 0.
+	SaveMovement(ctx context.Context, m *StockMovement) (err error)
+	// FindStockSales returns the stock of every tracked product, by id, with the units sold on the invoices
+	// dated from since, YYYY-MM-DD HH:MM:SS.
+	FindStockSales(ctx context.Context, since string) (s []StockSales, err error)
+}
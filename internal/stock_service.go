@@ -0,0 +1,14 @@
+package internal
+
+import "context"
+
+// ServiceStock is the interface that wraps the basic methods that a stock service should implement.
+type ServiceStock interface {
+	// FindByProduct returns the stock of the product id. It fails with ErrProductNotFound.
+	FindByProduct(ctx context.Context, id int) (s ProductStock, err error)
+	// FindMovements returns the stock movements of the product id, oldest first. It fails with ErrProductNotFound.
+	FindMovements(ctx context.Context, id int) (m []StockMovement, err error)
+	// SaveMovement saves a receipt or an adjustment.
+	// It fails with ErrProductNotFound, and with ErrStockInsufficient if the stock would go below 0.
+	SaveMovement(ctx context.Context, m *StockMovement) (err error)
+}
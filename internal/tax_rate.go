@@ -0,0 +1,69 @@
+package internal
+
+import "errors"
+
+var (
+	// ErrTaxRateExists is used when a tax rate of the same category and condition already exists.
+	ErrTaxRateExists = errors.New("tax rate already exists")
+)
+
+// TaxCategoryDefault is the tax category of the products saved without one.
+const TaxCategoryDefault = "standard"
+
+// TaxRateAttributes is the struct that represents the attributes of a tax rate.
+type TaxRateAttributes struct {
+	// Category is the tax category of the products the rate applies to, empty for every category.
+	Category string
+	// Condition is the condition of the customers the rate applies to, nil for every condition.
+	Condition *int
+	// Rate is the rate, e.g. 0.21 for 21%.
+	Rate Rate
+}
+
+// TaxRate is the struct that represents a tax rate.
+type TaxRate struct {
+	// Id is the unique identifier of the tax rate.
+	Id int
+	// TaxRateAttributes is the attributes of the tax rate.
+	TaxRateAttributes
+}
+
+// Matches reports whether the rate applies to a product of category sold to a customer of condition.
+func (t TaxRateAttributes) Matches(category string, condition int) bool {
+	return (t.Category == "" || t.Category == category) && (t.Condition == nil || *t.Condition == condition)
+}
+
+// Same reports whether both rates apply to the same category and condition.
+func (t TaxRateAttributes) Same(o TaxRateAttributes) bool {
+	if t.Category != o.Category || (t.Condition == nil) != (o.Condition == nil) {
+		return false
+	}
+	return t.Condition == nil || *t.Condition == *o.Condition
+}
+
+// TaxRates are the configured tax rates, from which the rate of every sale is chosen.
+type TaxRates []TaxRate
+
+// For returns the rate of a product of category sold to a customer of condition: the rate of the most
+// specific matching tax rate, 0 if none matches. A rate of the condition wins over a rate of the category,
+// so a rate of 0 for a condition exempts its customers of every category.
+func (t TaxRates) For(category string, condition int) (r Rate) {
+	best := -1
+	for _, v := range t {
+		if !v.Matches(category, condition) {
+			continue
+		}
+
+		specificity := 0
+		if v.Condition != nil {
+			specificity += 2
+		}
+		if v.Category != "" {
+			specificity++
+		}
+		if specificity > best {
+			best, r = specificity, v.Rate
+		}
+	}
+	return
+}
@@ -0,0 +1,12 @@
+package internal
+
+import "context"
+
+// RepositoryTaxRate is the interface that wraps the basic methods that a tax rate repository should implement.
+type RepositoryTaxRate interface {
+	// FindAll returns all tax rates saved in the database.
+	FindAll(ctx context.Context) (t []TaxRate, err error)
+	// Save saves a tax rate into the database. It fails with ErrTaxRateExists
+	// if a rate of the same category and condition exists.
+	Save(ctx context.Context, t *TaxRate) (err error)
+}
@@ -0,0 +1,11 @@
+package internal
+
+import "context"
+
+// ServiceTaxRate is the interface that wraps the basic methods that a tax rate service should implement.
+type ServiceTaxRate interface {
+	// FindAll returns all tax rates.
+	FindAll(ctx context.Context) (t []TaxRate, err error)
+	// Save saves a tax rate.
+	Save(ctx context.Context, t *TaxRate) (err error)
+}
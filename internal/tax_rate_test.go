@@ -0,0 +1,46 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for TaxRates.For method
+func TestTaxRatesFor(t *testing.T) {
+	exempt := 0
+	rate := func(category string, condition *int, r string) internal.TaxRate {
+		return internal.TaxRate{TaxRateAttributes: internal.TaxRateAttributes{Category: category, Condition: condition, Rate: internal.MustParseRate(r)}}
+	}
+	rates := internal.TaxRates{
+		rate("", nil, "0.21"),
+		rate("food", nil, "0.10"),
+		rate("", &exempt, "0"),
+		rate("alcohol", &exempt, "0.05"),
+	}
+
+	testCases := []struct {
+		name       string
+		rates      internal.TaxRates
+		category   string
+		condition  int
+		expectRate internal.Rate
+	}{
+		{name: "every category and condition", rates: rates, category: "standard", condition: 1, expectRate: internal.MustParseRate("0.21")},
+		{name: "category", rates: rates, category: "food", condition: 1, expectRate: internal.MustParseRate("0.10")},
+		{name: "condition wins over category", rates: rates, category: "food", condition: 0, expectRate: 0},
+		{name: "category and condition", rates: rates, category: "alcohol", condition: 0, expectRate: internal.MustParseRate("0.05")},
+		{name: "no rate", rates: nil, category: "food", condition: 1, expectRate: 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			r := testCase.rates.For(testCase.category, testCase.condition)
+
+			// assert
+			require.Equal(t, testCase.expectRate, r)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package internal
+
+import "context"
+
+// RepositoryWebhook is the interface that wraps the basic methods that a webhook queue repository should implement.
+type RepositoryWebhook interface {
+	// FindPending returns at most limit deliveries not delivered yet and attempted fewer than maxAttempts times,
+	// oldest first.
+	FindPending(ctx context.Context, maxAttempts, limit int) (d []WebhookDelivery, err error)
+	// Update saves the attempts, the delivered flag and the last error of the delivery d.
+	Update(ctx context.Context, d WebhookDelivery) (err error)
+}
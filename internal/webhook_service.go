@@ -0,0 +1,9 @@
+package internal
+
+import "context"
+
+// ServiceWebhook is the interface that wraps the basic methods that a webhook service should implement.
+type ServiceWebhook interface {
+	// Deliver posts the pending deliveries to the webhook, returning the number of delivered ones.
+	Deliver(ctx context.Context) (n int, err error)
+}
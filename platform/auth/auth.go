@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Role is the role of a principal. Roles are ordered: every role is granted the permissions of the lower ones.
+type Role string
+
+const (
+	// RoleReader can read every resource.
+	RoleReader Role = "reader"
+	// RoleClerk can also create invoices, sales and customers.
+	RoleClerk Role = "clerk"
+	// RoleAdmin can also manage the catalog and run bulk operations.
+	RoleAdmin Role = "admin"
+)
+
+// rank is the order of the roles.
+var rank = map[Role]int{RoleReader: 1, RoleClerk: 2, RoleAdmin: 3}
+
+// IsValid reports whether r is a known role.
+func (r Role) IsValid() bool {
+	_, ok := rank[r]
+	return ok
+}
+
+// Allows reports whether r is granted the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return r.IsValid() && rank[r] >= rank[required]
+}
+
+const (
+	// MethodAPIKey is the authentication method of API keys.
+	MethodAPIKey = "api_key"
+	// MethodJWT is the authentication method of JWTs.
+	MethodJWT = "jwt"
+
+	// APIKeyHeader is the header carrying an API key.
+	APIKeyHeader = "X-API-Key"
+)
+
+var (
+	// ErrCredentialsMissing is used when the request carries no credentials.
+	ErrCredentialsMissing = errors.New("credentials missing")
+	// ErrCredentialsInvalid is used when the credentials are unknown or malformed.
+	ErrCredentialsInvalid = errors.New("credentials invalid")
+	// ErrTokenExpired is used when the JWT is expired or not valid yet.
+	ErrTokenExpired = errors.New("token expired")
+	// ErrRoleUnknown is used when a role is not one of the known roles.
+	ErrRoleUnknown = errors.New("role unknown")
+)
+
+// Principal is the authenticated caller.
+type Principal struct {
+	// Subject identifies the caller, e.g. the user or the service name.
+	Subject string
+	// Role is the role of the caller.
+	Role Role
+	// Method is the authentication method, MethodAPIKey or MethodJWT.
+	Method string
+}
+
+// Config is the configuration for NewAuthenticator.
+type Config struct {
+	// APIKeys are the principals, by API key.
+	APIKeys map[string]Principal
+	// JWTSecret is the HMAC secret of the HS256 JWTs. Empty disables JWTs.
+	JWTSecret []byte
+	// JWTIssuer is the required iss claim of the JWTs. Empty accepts any issuer.
+	JWTIssuer string
+}
+
+// NewAuthenticator creates a new authenticator.
+func NewAuthenticator(cfg Config) *Authenticator {
+	// keys are kept hashed, so the lookup time does not depend on how much of a key matches
+	keys := make(map[[sha256.Size]byte]Principal, len(cfg.APIKeys))
+	for k, p := range cfg.APIKeys {
+		p.Method = MethodAPIKey
+		keys[sha256.Sum256([]byte(k))] = p
+	}
+
+	return &Authenticator{keys: keys, secret: cfg.JWTSecret, issuer: cfg.JWTIssuer, now: time.Now}
+}
+
+// Authenticator authenticates requests by API key or by bearer JWT.
+type Authenticator struct {
+	// keys are the principals, by API key hash.
+	keys map[[sha256.Size]byte]Principal
+	// secret is the HMAC secret of the JWTs.
+	secret []byte
+	// issuer is the required iss claim.
+	issuer string
+	// now returns the current time.
+	now func() time.Time
+}
+
+// Authenticate returns the principal of the request credentials:
+// an API key in the X-API-Key header, or a JWT in the Authorization header as a bearer token.
+func (a *Authenticator) Authenticate(r *http.Request) (p Principal, err error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		p, ok := a.keys[sha256.Sum256([]byte(key))]
+		if !ok {
+			return Principal{}, fmt.Errorf("%w: unknown api key", ErrCredentialsInvalid)
+		}
+		return p, nil
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		err = ErrCredentialsMissing
+		return
+	}
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		err = fmt.Errorf("%w: authorization scheme not supported", ErrCredentialsInvalid)
+		return
+	}
+	if len(a.secret) == 0 {
+		err = fmt.Errorf("%w: tokens not accepted", ErrCredentialsInvalid)
+		return
+	}
+
+	claims, err := VerifyJWT(strings.TrimSpace(token), a.secret, a.now())
+	if err != nil {
+		return
+	}
+	if a.issuer != "" && claims.Issuer != a.issuer {
+		err = fmt.Errorf("%w: issuer", ErrCredentialsInvalid)
+		return
+	}
+
+	p = Principal{Subject: claims.Subject, Role: claims.Role, Method: MethodJWT}
+	return
+}
+
+// ParseAPIKeys parses a comma separated list of key:role:subject entries, e.g. from an environment variable.
+func ParseAPIKeys(s string) (keys map[string]Principal, err error) {
+	keys = make(map[string]Principal)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			err = fmt.Errorf("%w: api key entry must be key:role:subject", ErrCredentialsInvalid)
+			return
+		}
+		role := Role(parts[1])
+		if !role.IsValid() {
+			err = fmt.Errorf("%w: %s", ErrRoleUnknown, parts[1])
+			return
+		}
+		keys[parts[0]] = Principal{Subject: parts[2], Role: role}
+	}
+	return
+}
+
+// ctxKey is the key of the principal in a context.
+type ctxKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, if any.
+func PrincipalFromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(ctxKey{}).(Principal)
+	return
+}
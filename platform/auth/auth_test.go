@@ -0,0 +1,93 @@
+package auth_test
+
+import (
+	"app/platform/auth"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the Authenticator
+func TestAuthenticate(t *testing.T) {
+	secret := []byte("secret")
+	valid, err := auth.SignJWT(auth.Claims{Subject: "jane", Role: auth.RoleClerk, ExpiresAt: time.Now().Add(time.Hour).Unix()}, secret)
+	require.NoError(t, err)
+	expired, err := auth.SignJWT(auth.Claims{Subject: "jane", Role: auth.RoleClerk, ExpiresAt: time.Now().Add(-time.Hour).Unix()}, secret)
+	require.NoError(t, err)
+	forged, err := auth.SignJWT(auth.Claims{Subject: "jane", Role: auth.RoleAdmin, ExpiresAt: time.Now().Add(time.Hour).Unix()}, []byte("other"))
+	require.NoError(t, err)
+	// - alg none: the payload of a valid token with an empty signature
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	none := header + "." + strings.Split(valid, ".")[1] + "."
+
+	a := auth.NewAuthenticator(auth.Config{
+		APIKeys:   map[string]auth.Principal{"key": {Subject: "svc", Role: auth.RoleReader}},
+		JWTSecret: secret,
+	})
+
+	testCases := []struct {
+		name            string
+		header          string
+		value           string
+		expectPrincipal auth.Principal
+		expectErr       error
+	}{
+		{name: "api key", header: auth.APIKeyHeader, value: "key", expectPrincipal: auth.Principal{Subject: "svc", Role: auth.RoleReader, Method: auth.MethodAPIKey}},
+		{name: "unknown api key", header: auth.APIKeyHeader, value: "nope", expectErr: auth.ErrCredentialsInvalid},
+		{name: "jwt", header: "Authorization", value: "Bearer " + valid, expectPrincipal: auth.Principal{Subject: "jane", Role: auth.RoleClerk, Method: auth.MethodJWT}},
+		{name: "expired jwt", header: "Authorization", value: "Bearer " + expired, expectErr: auth.ErrTokenExpired},
+		{name: "forged jwt", header: "Authorization", value: "Bearer " + forged, expectErr: auth.ErrCredentialsInvalid},
+		{name: "alg none jwt", header: "Authorization", value: "Bearer " + none, expectErr: auth.ErrCredentialsInvalid},
+		{name: "basic scheme", header: "Authorization", value: "Basic dTpw", expectErr: auth.ErrCredentialsInvalid},
+		{name: "no credentials", expectErr: auth.ErrCredentialsMissing},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+
+			// act
+			p, err := a.Authenticate(req)
+
+			// assert
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectPrincipal, p)
+		})
+	}
+}
+
+// Tests for ParseAPIKeys function
+func TestParseAPIKeys(t *testing.T) {
+	t.Run("entries are parsed", func(t *testing.T) {
+		// act
+		keys, err := auth.ParseAPIKeys("k1:reader:alice, k2:admin:ops")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]auth.Principal{
+			"k1": {Subject: "alice", Role: auth.RoleReader},
+			"k2": {Subject: "ops", Role: auth.RoleAdmin},
+		}, keys)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		// act
+		_, err := auth.ParseAPIKeys("k1:root:alice")
+
+		// assert
+		require.ErrorIs(t, err, auth.ErrRoleUnknown)
+	})
+}
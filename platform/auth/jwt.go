@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims are the JWT claims this package reads.
+type Claims struct {
+	// Subject is the sub claim.
+	Subject string `json:"sub"`
+	// Role is the role claim.
+	Role Role `json:"role"`
+	// Issuer is the iss claim.
+	Issuer string `json:"iss,omitempty"`
+	// ExpiresAt is the exp claim, in unix seconds. Required.
+	ExpiresAt int64 `json:"exp"`
+	// NotBefore is the nbf claim, in unix seconds.
+	NotBefore int64 `json:"nbf,omitempty"`
+	// IssuedAt is the iat claim, in unix seconds.
+	IssuedAt int64 `json:"iat,omitempty"`
+}
+
+// jwtHeader is the JOSE header of the tokens.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// SignJWT returns the claims as a HS256 JWT signed with secret.
+func SignJWT(c Claims, secret []byte) (token string, err error) {
+	header, err := json.Marshal(jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	token = signed + "." + base64.RawURLEncoding.EncodeToString(sign(signed, secret))
+	return
+}
+
+// VerifyJWT verifies the HS256 signature and the time claims of token at now, and returns its claims.
+// Any other algorithm, including none, is rejected.
+func VerifyJWT(token string, secret []byte, now time.Time) (c Claims, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		err = fmt.Errorf("%w: malformed token", ErrCredentialsInvalid)
+		return
+	}
+
+	// header
+	var h jwtHeader
+	if err = decodeSegment(parts[0], &h); err != nil {
+		return
+	}
+	if h.Alg != "HS256" {
+		err = fmt.Errorf("%w: algorithm %q not supported", ErrCredentialsInvalid, h.Alg)
+		return
+	}
+
+	// signature
+	signature, errDecode := base64.RawURLEncoding.DecodeString(parts[2])
+	if errDecode != nil || !hmac.Equal(signature, sign(parts[0]+"."+parts[1], secret)) {
+		err = fmt.Errorf("%w: signature", ErrCredentialsInvalid)
+		return
+	}
+
+	// claims
+	if err = decodeSegment(parts[1], &c); err != nil {
+		return
+	}
+	if c.ExpiresAt == 0 || now.Unix() >= c.ExpiresAt || (c.NotBefore != 0 && now.Unix() < c.NotBefore) {
+		err = ErrTokenExpired
+		return
+	}
+	if c.Subject == "" {
+		err = fmt.Errorf("%w: subject missing", ErrCredentialsInvalid)
+		return
+	}
+	if !c.Role.IsValid() {
+		err = fmt.Errorf("%w: %s", ErrRoleUnknown, c.Role)
+		return
+	}
+
+	return
+}
+
+// sign returns the HMAC SHA-256 of s.
+func sign(s string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+// decodeSegment decodes a base64url JSON segment of a token into v.
+func decodeSegment(s string, v any) (err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: malformed token", ErrCredentialsInvalid)
+	}
+	return
+}
@@ -0,0 +1,30 @@
+package cache
+
+import "time"
+
+// Cache is the interface that wraps the basic methods of a key/value cache.
+// Values are raw bytes so that out of process stores can implement it.
+type Cache interface {
+	// Get returns the value stored under key, and whether it was found and not expired.
+	Get(key string) (value []byte, ok bool)
+	// Set stores value under key for ttl. A ttl of 0 means the entry does not expire.
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete removes the entries stored under keys.
+	Delete(keys ...string)
+	// DeletePrefix removes the entries whose key starts with any of prefixes.
+	DeletePrefix(prefixes ...string)
+	// Stats returns the usage counters of the cache.
+	Stats() Stats
+}
+
+// Stats is the struct that represents the usage counters of a cache.
+type Stats struct {
+	// Hits is the number of Get calls that found a value.
+	Hits uint64
+	// Misses is the number of Get calls that did not find a value.
+	Misses uint64
+	// Evictions is the number of entries removed to make room for new ones.
+	Evictions uint64
+	// Entries is the number of entries currently stored.
+	Entries int
+}
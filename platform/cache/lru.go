@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewLRU creates a new in-process cache holding at most capacity entries.
+// A capacity lower than 1 defaults to 128.
+func NewLRU(capacity int) *LRU {
+	if capacity < 1 {
+		capacity = 128
+	}
+
+	return &LRU{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// LRU is an in-process Cache that evicts the least recently used entry when full
+// and drops entries once their ttl is over.
+type LRU struct {
+	// mu guards every field below.
+	mu sync.Mutex
+	// capacity is the maximum number of entries.
+	capacity int
+	// ll keeps the entries ordered from most to least recently used.
+	ll *list.List
+	// items indexes the entries of ll by key.
+	items map[string]*list.Element
+	// stats are the usage counters.
+	stats Stats
+	// now returns the current time, replaceable in tests.
+	now func() time.Time
+}
+
+// entry is the value of every element of the list.
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// Get returns the value stored under key.
+func (c *LRU) Get(key string) (value []byte, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	// expired entries are removed lazily
+	e := el.Value.(*entry)
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		c.remove(el)
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	c.stats.Hits++
+	return e.value, true
+}
+
+// Set stores value under key for ttl.
+func (c *LRU) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	// update
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	// insert, evicting the least recently used entry if full
+	if c.ll.Len() >= c.capacity {
+		c.remove(c.ll.Back())
+		c.stats.Evictions++
+	}
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+}
+
+// Delete removes the entries stored under keys.
+func (c *LRU) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+}
+
+// DeletePrefix removes the entries whose key starts with any of prefixes.
+func (c *LRU) DeletePrefix(prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.items {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				c.remove(el)
+				break
+			}
+		}
+	}
+}
+
+// Stats returns the usage counters of the cache.
+func (c *LRU) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st := c.stats
+	st.Entries = c.ll.Len()
+	return st
+}
+
+// remove removes el from the cache. The caller must hold mu.
+func (c *LRU) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
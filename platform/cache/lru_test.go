@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for LRU cache
+func TestLRU(t *testing.T) {
+	t.Run("hit and miss", func(t *testing.T) {
+		// arrange
+		c := NewLRU(2)
+		c.Set("a", []byte("1"), 0)
+
+		// act
+		valueA, okA := c.Get("a")
+		valueB, okB := c.Get("b")
+
+		// assert
+		require.True(t, okA)
+		require.Equal(t, []byte("1"), valueA)
+		require.False(t, okB)
+		require.Nil(t, valueB)
+		require.Equal(t, Stats{Hits: 1, Misses: 1, Entries: 1}, c.Stats())
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		// arrange
+		c := NewLRU(2)
+		c.Set("a", []byte("1"), 0)
+		c.Set("b", []byte("2"), 0)
+		c.Get("a")
+
+		// act
+		c.Set("c", []byte("3"), 0)
+
+		// assert
+		_, okA := c.Get("a")
+		_, okB := c.Get("b")
+		_, okC := c.Get("c")
+		require.True(t, okA)
+		require.False(t, okB)
+		require.True(t, okC)
+		require.Equal(t, uint64(1), c.Stats().Evictions)
+		require.Equal(t, 2, c.Stats().Entries)
+	})
+
+	t.Run("expires after ttl", func(t *testing.T) {
+		// arrange
+		now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := NewLRU(2)
+		c.now = func() time.Time { return now }
+		c.Set("a", []byte("1"), time.Minute)
+
+		// act
+		_, okBefore := c.Get("a")
+		now = now.Add(time.Minute)
+		_, okAfter := c.Get("a")
+
+		// assert
+		require.True(t, okBefore)
+		require.False(t, okAfter)
+		require.Equal(t, 0, c.Stats().Entries)
+	})
+
+	t.Run("set overrides and delete removes", func(t *testing.T) {
+		// arrange
+		c := NewLRU(2)
+		c.Set("a", []byte("1"), 0)
+		c.Set("a", []byte("2"), 0)
+
+		// act
+		value, ok := c.Get("a")
+		c.Delete("a", "missing")
+		_, okDeleted := c.Get("a")
+
+		// assert
+		require.True(t, ok)
+		require.Equal(t, []byte("2"), value)
+		require.False(t, okDeleted)
+	})
+
+	t.Run("delete prefix removes the matching keys", func(t *testing.T) {
+		// arrange
+		c := NewLRU(4)
+		c.Set("customers:top:USD", []byte("1"), 0)
+		c.Set("customers:top:EUR", []byte("2"), 0)
+		c.Set("products:top", []byte("3"), 0)
+
+		// act
+		c.DeletePrefix("customers:top", "missing")
+		_, okUSD := c.Get("customers:top:USD")
+		_, okEUR := c.Get("customers:top:EUR")
+		_, okProducts := c.Get("products:top")
+
+		// assert
+		require.False(t, okUSD)
+		require.False(t, okEUR)
+		require.True(t, okProducts)
+		require.Equal(t, 1, c.Stats().Entries)
+	})
+}
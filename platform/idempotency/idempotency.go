@@ -0,0 +1,141 @@
+package idempotency
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is a stored response, replayed to the retries of a request.
+type Response struct {
+	// Code is the status code.
+	Code int
+	// Header is the response header.
+	Header http.Header
+	// Body is the response body.
+	Body []byte
+}
+
+// Record is the state of a request made with an idempotency key.
+type Record struct {
+	// Fingerprint identifies the request made with the key, so a reuse of the key for another request is detected.
+	Fingerprint string
+	// Response is the response to the request, nil while the request is in flight.
+	Response *Response
+}
+
+// Store is the interface that wraps the methods of an idempotency key store.
+type Store interface {
+	// Begin reserves key for the request identified by fingerprint, for ttl.
+	// If the key is already reserved it returns its record and false.
+	Begin(key, fingerprint string, ttl time.Duration) (rec Record, ok bool)
+	// Complete stores the response of the request that reserved key, for ttl.
+	Complete(key string, res Response, ttl time.Duration)
+	// Release frees key, so the request can be retried, e.g. after a server error.
+	Release(key string)
+}
+
+// MemoryRecordsMax is the maximum number of records a Memory store keeps, the oldest reserved evicted first.
+// An evicted key can be reserved again, so a retry after the eviction is served anew.
+const MemoryRecordsMax = 10000
+
+// NewMemory creates a new in-memory store.
+func NewMemory() *Memory {
+	return &Memory{ll: list.New(), records: make(map[string]*list.Element), now: time.Now}
+}
+
+// Memory is the in-memory implementation of Store. Expired records are removed as new keys are reserved,
+// and the oldest ones once MemoryRecordsMax are kept.
+type Memory struct {
+	// mu guards ll, records and lastSweep.
+	mu sync.Mutex
+	// ll keeps the entries ordered from the oldest to the most recently reserved.
+	ll *list.List
+	// records indexes the elements of ll by key.
+	records map[string]*list.Element
+	// lastSweep is the time of the last removal of the expired records.
+	lastSweep time.Time
+	// now returns the current time.
+	now func() time.Time
+}
+
+// entry is a record, its key and its expiration.
+type entry struct {
+	key       string
+	rec       Record
+	expiresAt time.Time
+}
+
+// sweepInterval is the interval between two removals of the expired records.
+const sweepInterval = time.Minute
+
+// Begin reserves key for the request identified by fingerprint.
+func (m *Memory) Begin(key, fingerprint string, ttl time.Duration) (rec Record, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if el, found := m.records[key]; found {
+		e := el.Value.(*entry)
+		if now.Before(e.expiresAt) {
+			return e.rec, false
+		}
+		m.remove(el)
+	}
+
+	rec = Record{Fingerprint: fingerprint}
+	m.records[key] = m.ll.PushBack(&entry{key: key, rec: rec, expiresAt: now.Add(ttl)})
+	if m.ll.Len() > MemoryRecordsMax {
+		m.remove(m.ll.Front())
+	}
+	return rec, true
+}
+
+// Complete stores the response of the request that reserved key.
+func (m *Memory) Complete(key string, res Response, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.records[key]
+	if !ok {
+		return
+	}
+	e := el.Value.(*entry)
+	e.rec.Response = &res
+	e.expiresAt = m.now().Add(ttl)
+}
+
+// Release frees key.
+func (m *Memory) Release(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.records[key]; ok {
+		m.remove(el)
+	}
+}
+
+// remove removes the entry of el. The caller must hold the lock.
+func (m *Memory) remove(el *list.Element) {
+	m.ll.Remove(el)
+	delete(m.records, el.Value.(*entry).key)
+}
+
+// sweep removes the expired records. The caller must hold the lock.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for el := m.ll.Front(); el != nil; {
+		next := el.Next()
+		if !now.Before(el.Value.(*entry).expiresAt) {
+			m.remove(el)
+		}
+		el = next
+	}
+}
@@ -0,0 +1,86 @@
+package idempotency
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the Memory store
+func TestMemory(t *testing.T) {
+	t.Run("key is reserved, completed and expires", func(t *testing.T) {
+		// arrange
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		m := NewMemory()
+		m.now = func() time.Time { return now }
+
+		// act
+		_, first := m.Begin("a", "f1", time.Hour)
+		inFlight, second := m.Begin("a", "f1", time.Hour)
+		m.Complete("a", Response{Code: http.StatusCreated, Body: []byte("ok")}, time.Hour)
+		done, third := m.Begin("a", "f2", time.Hour)
+		now = now.Add(time.Hour)
+		_, expired := m.Begin("a", "f2", time.Hour)
+
+		// assert
+		require.True(t, first)
+		require.False(t, second)
+		require.Equal(t, Record{Fingerprint: "f1"}, inFlight)
+		require.False(t, third)
+		require.Equal(t, "f1", done.Fingerprint)
+		require.Equal(t, &Response{Code: http.StatusCreated, Body: []byte("ok")}, done.Response)
+		require.True(t, expired)
+	})
+
+	t.Run("released key can be reserved again", func(t *testing.T) {
+		// arrange
+		m := NewMemory()
+		m.Begin("a", "f1", time.Hour)
+
+		// act
+		m.Release("a")
+		_, ok := m.Begin("a", "f1", time.Hour)
+
+		// assert
+		require.True(t, ok)
+	})
+
+	t.Run("expired records are swept", func(t *testing.T) {
+		// arrange
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		m := NewMemory()
+		m.now = func() time.Time { return now }
+		m.Begin("a", "f1", time.Second)
+		m.Begin("b", "f1", time.Hour)
+
+		// act
+		now = now.Add(2 * sweepInterval)
+		m.Begin("c", "f1", time.Hour)
+
+		// assert
+		require.Len(t, m.records, 2)
+		require.NotContains(t, m.records, "a")
+	})
+
+	t.Run("oldest records are evicted over the maximum", func(t *testing.T) {
+		// arrange
+		m := NewMemory()
+		for ix := 0; ix < MemoryRecordsMax; ix++ {
+			m.Begin(fmt.Sprintf("k%d", ix), "f1", time.Hour)
+		}
+
+		// act
+		_, ok := m.Begin("new", "f1", time.Hour)
+		_, evicted := m.Begin("k0", "f2", time.Hour)
+
+		// assert
+		require.True(t, ok)
+		require.True(t, evicted)
+		require.Len(t, m.records, MemoryRecordsMax)
+		require.Equal(t, MemoryRecordsMax, m.ll.Len())
+		require.NotContains(t, m.records, "k1")
+	})
+}
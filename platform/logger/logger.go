@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	// FormatText is the logfmt like output format.
+	FormatText = "text"
+	// FormatJSON is the JSON output format.
+	FormatJSON = "json"
+)
+
+var (
+	// ErrLevelUnknown is used when the level is not one of debug, info, warn or error.
+	ErrLevelUnknown = errors.New("logger level unknown")
+	// ErrFormatUnknown is used when the format is not text or json.
+	ErrFormatUnknown = errors.New("logger format unknown")
+)
+
+// Config is the configuration of New.
+type Config struct {
+	// Level is the minimum level logged: debug, info, warn or error. Defaults to info.
+	Level string
+	// Format is the output format: text or json. Defaults to text.
+	Format string
+}
+
+// New creates a new structured logger writing to w.
+func New(w io.Writer, cfg Config) (l *slog.Logger, err error) {
+	// level
+	var level slog.Level
+	if cfg.Level != "" {
+		err = level.UnmarshalText([]byte(cfg.Level))
+		if err != nil {
+			return nil, errors.Join(ErrLevelUnknown, err)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	// format
+	switch strings.ToLower(cfg.Format) {
+	case FormatText, "":
+		l = slog.New(slog.NewTextHandler(w, opts))
+	case FormatJSON:
+		l = slog.New(slog.NewJSONHandler(w, opts))
+	default:
+		err = ErrFormatUnknown
+	}
+
+	return
+}
+
+// ctxKey is the key of the logger in a context.
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying l.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger if there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
@@ -0,0 +1,71 @@
+package logger_test
+
+import (
+	"app/platform/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for New function
+func TestNew(t *testing.T) {
+	t.Run("json format filters by level", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+
+		// act
+		l, err := logger.New(&buf, logger.Config{Level: "warn", Format: "json"})
+		require.NoError(t, err)
+		l.Info("ignored")
+		l.Warn("slow query", "name", "customers.FindAll")
+
+		// assert
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		require.Equal(t, "WARN", record["level"])
+		require.Equal(t, "slow query", record["msg"])
+		require.Equal(t, "customers.FindAll", record["name"])
+	})
+
+	t.Run("error - unknown level", func(t *testing.T) {
+		// act
+		_, err := logger.New(&bytes.Buffer{}, logger.Config{Level: "verbose"})
+
+		// assert
+		require.ErrorIs(t, err, logger.ErrLevelUnknown)
+	})
+
+	t.Run("error - unknown format", func(t *testing.T) {
+		// act
+		_, err := logger.New(&bytes.Buffer{}, logger.Config{Format: "xml"})
+
+		// assert
+		require.ErrorIs(t, err, logger.ErrFormatUnknown)
+	})
+}
+
+// Tests for FromContext function
+func TestFromContext(t *testing.T) {
+	t.Run("carried logger", func(t *testing.T) {
+		// arrange
+		l := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+		// act
+		got := logger.FromContext(logger.WithContext(context.Background(), l))
+
+		// assert
+		require.Same(t, l, got)
+	})
+
+	t.Run("default logger", func(t *testing.T) {
+		// act
+		got := logger.FromContext(context.Background())
+
+		// assert
+		require.Same(t, slog.Default(), got)
+	})
+}
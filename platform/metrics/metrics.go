@@ -0,0 +1,372 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Default is the registry used by the packages that record metrics on their own,
+// such as the repositories and the loaders.
+var Default = NewRegistry()
+
+// DefBuckets are the default histogram buckets, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]collector),
+	}
+}
+
+// Registry is a set of metrics that can be written in the Prometheus text exposition format.
+type Registry struct {
+	// mu guards collectors.
+	mu sync.Mutex
+	// collectors are the metrics, by name.
+	collectors map[string]collector
+}
+
+// collector is the interface that wraps the methods of every kind of metric.
+type collector interface {
+	// kind returns the prometheus type of the metric.
+	kind() string
+	// help returns the description of the metric.
+	help() string
+	// write writes the samples of the metric named name.
+	write(w io.Writer, name string)
+}
+
+// Counter returns the counter named name, registering it if needed.
+// A counter only goes up; it is reset when the process restarts.
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	return register(r, name, func() *CounterVec {
+		return &CounterVec{vec: newVec[*counterValue](help, labels, func() *counterValue { return &counterValue{} })}
+	})
+}
+
+// Histogram returns the histogram named name, registering it if needed.
+// Buckets are the upper bounds of the buckets, in ascending order.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return register(r, name, func() *HistogramVec {
+		return &HistogramVec{vec: newVec[*histogramValue](help, labels, func() *histogramValue {
+			return &histogramValue{buckets: buckets, counts: make([]uint64, len(buckets))}
+		})}
+	})
+}
+
+// GaugeFunc registers a gauge whose value is read from fn on every scrape,
+// replacing any previous gauge named name.
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors[name] = &funcMetric{kindName: "gauge", helpText: help, fn: fn}
+}
+
+// CounterFunc registers a counter whose value is read from fn on every scrape,
+// replacing any previous counter named name.
+func (r *Registry) CounterFunc(name, help string, fn func() float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors[name] = &funcMetric{kindName: "counter", helpText: help, fn: fn}
+}
+
+// register returns the collector named name, creating it with build if it does not exist.
+// It panics if name is already used by another kind of metric, as that is a programming error.
+func register[T collector](r *Registry, name string, build func() T) T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.collectors[name]; ok {
+		t, ok := c.(T)
+		if !ok {
+			panic(fmt.Sprintf("metrics: %s already registered as %s", name, c.kind()))
+		}
+		return t
+	}
+
+	c := build()
+	r.collectors[name] = c
+	return c
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format, sorted by name.
+func (r *Registry) WriteTo(w io.Writer) (n int64, err error) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	collectors := make(map[string]collector, len(r.collectors))
+	for k, v := range r.collectors {
+		collectors[k] = v
+	}
+	r.mu.Unlock()
+	sort.Strings(names)
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, name := range names {
+		c := collectors[name]
+		fmt.Fprintf(cw, "# HELP %s %s\n", name, escapeHelp(c.help()))
+		fmt.Fprintf(cw, "# TYPE %s %s\n", name, c.kind())
+		c.write(cw, name)
+	}
+
+	err = cw.w.(*bufio.Writer).Flush()
+	return cw.n, err
+}
+
+// Handler returns a http.Handler that serves the registry to Prometheus scrapers.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		r.WriteTo(w)
+	})
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	*vec[*counterValue]
+}
+
+// With returns the counter of the given label values, in the order the labels were declared.
+func (c *CounterVec) With(values ...string) *Counter {
+	return &Counter{c.get(values)}
+}
+
+// Counter is a single counter.
+type Counter struct {
+	v *counterValue
+}
+
+// Inc adds 1 to the counter.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds v, which must not be negative, to the counter.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	c.v.mu.Lock()
+	c.v.value += v
+	c.v.mu.Unlock()
+}
+
+// counterValue is the value of a counter.
+type counterValue struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (c *CounterVec) kind() string { return "counter" }
+
+func (c *CounterVec) write(w io.Writer, name string) {
+	c.each(func(labels string, v *counterValue) {
+		v.mu.Lock()
+		value := v.value
+		v.mu.Unlock()
+		fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(value))
+	})
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	*vec[*histogramValue]
+}
+
+// With returns the histogram of the given label values, in the order the labels were declared.
+func (h *HistogramVec) With(values ...string) *Histogram {
+	return &Histogram{h.get(values)}
+}
+
+// Histogram is a single histogram.
+type Histogram struct {
+	v *histogramValue
+}
+
+// Observe adds an observation to the histogram.
+func (h *Histogram) Observe(value float64) {
+	h.v.mu.Lock()
+	defer h.v.mu.Unlock()
+
+	for i, upper := range h.v.buckets {
+		if value <= upper {
+			h.v.counts[i]++
+			break
+		}
+	}
+	h.v.count++
+	h.v.sum += value
+}
+
+// histogramValue is the value of a histogram. Bucket counts are not cumulative.
+type histogramValue struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func (h *HistogramVec) kind() string { return "histogram" }
+
+func (h *HistogramVec) write(w io.Writer, name string) {
+	h.each(func(labels string, v *histogramValue) {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+
+		var cumulative uint64
+		for i, upper := range v.buckets {
+			cumulative += v.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, withLabel(labels, "le", formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, withLabel(labels, "le", "+Inf"), v.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, formatFloat(v.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", name, labels, v.count)
+	})
+}
+
+// funcMetric is a metric whose value is read from a function.
+type funcMetric struct {
+	kindName string
+	helpText string
+	fn       func() float64
+}
+
+func (f *funcMetric) kind() string { return f.kindName }
+
+func (f *funcMetric) help() string { return f.helpText }
+
+func (f *funcMetric) write(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(f.fn()))
+}
+
+// vec holds the values of a metric for every combination of label values.
+type vec[T any] struct {
+	mu       sync.Mutex
+	helpText string
+	labels   []string
+	values   map[string]T
+	newValue func() T
+}
+
+// newVec creates a new vec.
+func newVec[T any](help string, labels []string, newValue func() T) *vec[T] {
+	return &vec[T]{helpText: help, labels: labels, values: make(map[string]T), newValue: newValue}
+}
+
+func (v *vec[T]) help() string { return v.helpText }
+
+// get returns the value of the given label values, creating it if needed.
+// Missing label values are empty and extra ones are ignored.
+func (v *vec[T]) get(values []string) T {
+	key := v.format(values)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	value, ok := v.values[key]
+	if !ok {
+		value = v.newValue()
+		v.values[key] = value
+	}
+	return value
+}
+
+// each calls fn for every value, sorted by labels.
+func (v *vec[T]) each(fn func(labels string, value T)) {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	values := make(map[string]T, len(v.values))
+	for k, value := range v.values {
+		values[k] = value
+	}
+	v.mu.Unlock()
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		fn(k, values[k])
+	}
+}
+
+// format formats label values as {name="value",...}.
+func (v *vec[T]) format(values []string) string {
+	if len(v.labels) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, name := range v.labels {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		sb.WriteString(name)
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabel(value))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// withLabel adds the label name=value to the formatted labels.
+func withLabel(labels, name, value string) string {
+	pair := name + `="` + value + `"`
+	if labels == "" {
+		return "{" + pair + "}"
+	}
+	return labels[:len(labels)-1] + "," + pair + "}"
+}
+
+// formatFloat formats v as the exposition format expects.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabel escapes a label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// escapeHelp escapes a help text.
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,71 @@
+package metrics_test
+
+import (
+	"app/platform/metrics"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the registry
+func TestRegistry(t *testing.T) {
+	t.Run("counters, histograms and funcs are written in the text format", func(t *testing.T) {
+		// arrange
+		reg := metrics.NewRegistry()
+		reg.Counter("requests_total", "Number of requests.", "route").With("/a\"b").Add(2)
+		h := reg.Histogram("duration_seconds", "Duration.", []float64{0.1, 1}, "route").With("/a")
+		h.Observe(0.05)
+		h.Observe(0.5)
+		h.Observe(2)
+		reg.GaugeFunc("entries", "Number of entries.", func() float64 { return 3 })
+
+		// act
+		var buf bytes.Buffer
+		_, err := reg.WriteTo(&buf)
+
+		// assert
+		require.NoError(t, err)
+		expected := `# HELP duration_seconds Duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/a",le="0.1"} 1
+duration_seconds_bucket{route="/a",le="1"} 2
+duration_seconds_bucket{route="/a",le="+Inf"} 3
+duration_seconds_sum{route="/a"} 2.55
+duration_seconds_count{route="/a"} 3
+# HELP entries Number of entries.
+# TYPE entries gauge
+entries 3
+# HELP requests_total Number of requests.
+# TYPE requests_total counter
+requests_total{route="/a\"b"} 2
+`
+		require.Equal(t, expected, buf.String())
+	})
+
+	t.Run("registering the same metric returns the existing one", func(t *testing.T) {
+		// arrange
+		reg := metrics.NewRegistry()
+		reg.Counter("requests_total", "Number of requests.").With().Inc()
+
+		// act
+		reg.Counter("requests_total", "Number of requests.").With().Inc()
+
+		// assert
+		rr := httptest.NewRecorder()
+		reg.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rr.Header().Get("Content-Type"))
+		require.Contains(t, rr.Body.String(), "requests_total 2\n")
+	})
+
+	t.Run("registering a name with another kind panics", func(t *testing.T) {
+		// arrange
+		reg := metrics.NewRegistry()
+		reg.Counter("requests_total", "Number of requests.")
+
+		// act / assert
+		require.Panics(t, func() { reg.Histogram("requests_total", "Number of requests.", metrics.DefBuckets) })
+	})
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the interval between two removals of the idle buckets.
+const sweepInterval = time.Minute
+
+// NewLimiter creates a new limiter allowing, per key, rate requests per second with bursts of up to burst requests.
+// A burst lower than 1 is 1.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Limiter is a token bucket rate limiter, with a bucket per key.
+// Buckets start full and are refilled at rate tokens per second; every request takes a token.
+type Limiter struct {
+	// rate is the number of tokens added per second.
+	rate float64
+	// burst is the capacity of the buckets.
+	burst float64
+	// mu guards buckets and lastSweep.
+	mu sync.Mutex
+	// buckets are the buckets, by key.
+	buckets map[string]*bucket
+	// lastSweep is the time of the last removal of the idle buckets.
+	lastSweep time.Time
+	// now returns the current time.
+	now func() time.Time
+}
+
+// bucket is the token bucket of a key.
+type bucket struct {
+	// tokens is the number of tokens left at last.
+	tokens float64
+	// last is the time tokens was last updated.
+	last time.Time
+}
+
+// Result is the outcome of Allow.
+type Result struct {
+	// Allowed reports whether the request can go on.
+	Allowed bool
+	// Limit is the capacity of the bucket.
+	Limit int
+	// Remaining is the number of whole tokens left.
+	Remaining int
+	// RetryAfter is the time until a token is available, zero if the request is allowed.
+	RetryAfter time.Duration
+}
+
+// Allow takes a token from the bucket of key, if there is one.
+func (l *Limiter) Allow(key string) (res Result) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	// refill
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	res.Limit = int(l.burst)
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+		res.Remaining = int(b.tokens)
+		return
+	}
+
+	if l.rate > 0 {
+		res.RetryAfter = time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	} else {
+		res.RetryAfter = time.Duration(math.MaxInt64)
+	}
+	return
+}
+
+// sweep removes the buckets that would be full by now, as they behave like new ones.
+// The caller must hold the lock.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
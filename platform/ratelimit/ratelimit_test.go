@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the Limiter
+func TestLimiter(t *testing.T) {
+	t.Run("burst then refill at rate", func(t *testing.T) {
+		// arrange
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		l := NewLimiter(2, 3)
+		l.now = func() time.Time { return now }
+
+		// act
+		var allowed []bool
+		for ix := 0; ix < 4; ix++ {
+			allowed = append(allowed, l.Allow("a").Allowed)
+		}
+		denied := l.Allow("a")
+		other := l.Allow("b")
+		now = now.Add(500 * time.Millisecond)
+		refilled := l.Allow("a")
+
+		// assert
+		require.Equal(t, []bool{true, true, true, false}, allowed)
+		require.False(t, denied.Allowed)
+		require.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+		require.True(t, other.Allowed)
+		require.Equal(t, Result{Allowed: true, Limit: 3, Remaining: 0}, refilled)
+	})
+
+	t.Run("idle buckets are swept", func(t *testing.T) {
+		// arrange
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		l := NewLimiter(1, 1)
+		l.now = func() time.Time { return now }
+		l.Allow("a")
+
+		// act
+		now = now.Add(2 * sweepInterval)
+		l.Allow("b")
+
+		// assert
+		require.Len(t, l.buckets, 1)
+		require.Contains(t, l.buckets, "b")
+	})
+}
@@ -0,0 +1,261 @@
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = ""
+	// ExporterStdout writes the spans as JSON lines to stdout.
+	ExporterStdout = "stdout"
+	// ExporterOTLPFile writes the spans as OTLP/JSON lines to a file.
+	ExporterOTLPFile = "otlp-file"
+)
+
+var (
+	// ErrExporterUnknown is used when the exporter is not supported.
+	ErrExporterUnknown = errors.New("trace exporter unknown")
+	// ErrExporterPathMissing is used when the file exporter has no path.
+	ErrExporterPathMissing = errors.New("trace exporter path missing")
+)
+
+// Config is the configuration for New.
+type Config struct {
+	// Exporter is one of ExporterNone, ExporterStdout or ExporterOTLPFile.
+	Exporter string
+	// Path is the file written by ExporterOTLPFile. Spans are appended.
+	Path string
+	// ServiceName is the service.name resource attribute of the OTLP spans.
+	ServiceName string
+}
+
+// New creates a new tracer from cfg. It returns a nil tracer for ExporterNone.
+func New(cfg Config) (t *Tracer, err error) {
+	switch cfg.Exporter {
+	case ExporterNone:
+		return
+	case ExporterStdout:
+		t = NewTracer(NewJSONExporter(os.Stdout))
+	case ExporterOTLPFile:
+		if cfg.Path == "" {
+			err = ErrExporterPathMissing
+			return
+		}
+		var f *os.File
+		f, err = os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return
+		}
+		t = NewTracer(NewOTLPFileExporter(f, cfg.ServiceName))
+		t.closer = f.Close
+	default:
+		err = fmt.Errorf("%w: %s", ErrExporterUnknown, cfg.Exporter)
+	}
+	return
+}
+
+// NewJSONExporter creates a new exporter writing every span as a JSON line to w.
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{enc: json.NewEncoder(w)}
+}
+
+// JSONExporter writes spans as JSON lines, meant to be read by humans.
+type JSONExporter struct {
+	// mu guards enc.
+	mu sync.Mutex
+	// enc is the encoder of the output.
+	enc *json.Encoder
+}
+
+// spanJSON is a span in the JSONExporter format.
+type spanJSON struct {
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id"`
+	ParentID   string         `json:"parent_span_id,omitempty"`
+	Name       string         `json:"name"`
+	Start      string         `json:"start"`
+	DurationMs float64        `json:"duration_ms"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Export writes the span. Write errors are dropped, tracing must not fail the traced operation.
+func (e *JSONExporter) Export(s SpanData) {
+	sj := spanJSON{
+		TraceID:    s.SpanContext.TraceID.String(),
+		SpanID:     s.SpanContext.SpanID.String(),
+		Name:       s.Name,
+		Start:      s.Start.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		DurationMs: float64(s.End.Sub(s.Start).Microseconds()) / 1000,
+		Error:      s.Error,
+	}
+	if s.Parent.IsValid() {
+		sj.ParentID = s.Parent.String()
+	}
+	if len(s.Attributes) > 0 {
+		sj.Attributes = make(map[string]any, len(s.Attributes))
+		for _, a := range s.Attributes {
+			sj.Attributes[a.Key] = a.Value
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.enc.Encode(sj)
+}
+
+// NewOTLPFileExporter creates a new exporter writing every span to w as a line holding
+// an OTLP/JSON ExportTraceServiceRequest, the format of the collector file exporter and receiver.
+func NewOTLPFileExporter(w io.Writer, serviceName string) *OTLPFileExporter {
+	return &OTLPFileExporter{enc: json.NewEncoder(w), serviceName: serviceName}
+}
+
+// OTLPFileExporter writes spans as OTLP/JSON lines.
+type OTLPFileExporter struct {
+	// mu guards enc.
+	mu sync.Mutex
+	// enc is the encoder of the output.
+	enc *json.Encoder
+	// serviceName is the service.name resource attribute.
+	serviceName string
+}
+
+// OTLP/JSON types, restricted to the fields this exporter writes.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+	}
+)
+
+const (
+	// otlpSpanKindInternal is the SPAN_KIND_INTERNAL value.
+	otlpSpanKindInternal = 1
+	// otlpStatusError is the STATUS_CODE_ERROR value.
+	otlpStatusError = 2
+)
+
+// Export writes the span. Write errors are dropped, tracing must not fail the traced operation.
+func (e *OTLPFileExporter) Export(s SpanData) {
+	sp := otlpSpan{
+		TraceID:           s.SpanContext.TraceID.String(),
+		SpanID:            s.SpanContext.SpanID.String(),
+		Name:              s.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+	}
+	if s.Parent.IsValid() {
+		sp.ParentSpanID = s.Parent.String()
+	}
+	for _, a := range s.Attributes {
+		sp.Attributes = append(sp.Attributes, otlpAttr(a))
+	}
+	if s.Error != "" {
+		sp.Status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+	}
+
+	req := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpKeyValue{otlpAttr(String("service.name", e.serviceName))}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "app/platform/trace"}, Spans: []otlpSpan{sp}}},
+	}}}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.enc.Encode(req)
+}
+
+// otlpAttr converts an attribute to its OTLP representation.
+func otlpAttr(a Attr) (kv otlpKeyValue) {
+	kv.Key = a.Key
+	switch v := a.Value.(type) {
+	case string:
+		kv.Value.StringValue = &v
+	case int:
+		s := strconv.Itoa(v)
+		kv.Value.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		kv.Value.IntValue = &s
+	case float64:
+		kv.Value.DoubleValue = &v
+	case bool:
+		kv.Value.BoolValue = &v
+	default:
+		s := fmt.Sprint(v)
+		kv.Value.StringValue = &s
+	}
+	return
+}
+
+// NewRecorder creates a new exporter keeping the spans in memory.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Recorder keeps the exported spans in memory, so tests can assert on them.
+type Recorder struct {
+	// mu guards spans.
+	mu sync.Mutex
+	// spans are the exported spans, in the order they ended.
+	spans []SpanData
+}
+
+// Export keeps the span.
+func (r *Recorder) Export(s SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, s)
+}
+
+// Spans returns the exported spans, in the order they ended.
+func (r *Recorder) Spans() []SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]SpanData(nil), r.spans...)
+}
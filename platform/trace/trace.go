@@ -0,0 +1,283 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTraceparentInvalid is used when a traceparent header does not follow the W3C trace context format.
+	ErrTraceparentInvalid = errors.New("traceparent invalid")
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// IsValid reports whether the id is not all zeros.
+func (id TraceID) IsValid() bool { return id != TraceID{} }
+
+// String returns the id hex encoded.
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// IsValid reports whether the id is not all zeros.
+func (id SpanID) IsValid() bool { return id != SpanID{} }
+
+// String returns the id hex encoded.
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// SpanContext is the part of a span that is propagated across process boundaries.
+type SpanContext struct {
+	// TraceID is the id of the trace.
+	TraceID TraceID
+	// SpanID is the id of the span.
+	SpanID SpanID
+	// Sampled is the sampled flag of the trace.
+	Sampled bool
+}
+
+// IsValid reports whether both ids are valid.
+func (sc SpanContext) IsValid() bool { return sc.TraceID.IsValid() && sc.SpanID.IsValid() }
+
+// Traceparent returns the span context in the W3C traceparent format.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent parses a W3C traceparent header: version-traceid-parentid-flags.
+func ParseTraceparent(s string) (sc SpanContext, err error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		err = fmt.Errorf("%w: %q", ErrTraceparentInvalid, s)
+		return
+	}
+	// - version ff is forbidden, and version 00 has exactly four fields
+	if parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		err = fmt.Errorf("%w: %q", ErrTraceparentInvalid, s)
+		return
+	}
+
+	var flags [1]byte
+	_, errVersion := hex.Decode(make([]byte, 1), []byte(parts[0]))
+	_, errTrace := hex.Decode(sc.TraceID[:], []byte(parts[1]))
+	_, errSpan := hex.Decode(sc.SpanID[:], []byte(parts[2]))
+	_, errFlags := hex.Decode(flags[:], []byte(parts[3]))
+	if err = errors.Join(errVersion, errTrace, errSpan, errFlags); err != nil || !sc.IsValid() {
+		err = fmt.Errorf("%w: %q", ErrTraceparentInvalid, s)
+		return
+	}
+	sc.Sampled = flags[0]&1 == 1
+
+	return
+}
+
+// Attr is a span attribute. Values are strings, ints, int64s, float64s or bools.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr { return Attr{Key: key, Value: value} }
+
+// Int returns an int attribute.
+func Int(key string, value int) Attr { return Attr{Key: key, Value: value} }
+
+// SpanData is a finished span, as handed to the exporters.
+type SpanData struct {
+	// Name is the name of the operation.
+	Name string
+	// SpanContext is the context of the span.
+	SpanContext SpanContext
+	// Parent is the id of the parent span, zero for a root span.
+	Parent SpanID
+	// Start is the start time of the span.
+	Start time.Time
+	// End is the end time of the span.
+	End time.Time
+	// Attributes are the attributes of the span, in the order they were set.
+	Attributes []Attr
+	// Error is the error the operation ended with, empty if it succeeded.
+	Error string
+}
+
+// Exporter is the interface that wraps the method to export finished spans.
+// Exporters must be safe for concurrent use, and must not fail the traced operation.
+type Exporter interface {
+	// Export exports a finished span.
+	Export(s SpanData)
+}
+
+// NewTracer creates a new tracer exporting to e.
+func NewTracer(e Exporter) *Tracer {
+	return &Tracer{exporter: e}
+}
+
+// Tracer starts spans and exports them once ended.
+type Tracer struct {
+	// exporter is the exporter of the finished spans.
+	exporter Exporter
+	// closer closes the resource written by the exporter, if any.
+	closer func() error
+}
+
+// Close closes the resource written by the exporter, if any.
+func (t *Tracer) Close() (err error) {
+	if t != nil && t.closer != nil {
+		err = t.closer()
+	}
+	return
+}
+
+// Start starts a span named name, child of the span or the remote span context carried by ctx,
+// or the root of a new trace if there is none. The returned context carries the new span.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return t.start(ctx, name, time.Now(), attrs)
+}
+
+// start starts a span at the given time.
+func (t *Tracer) start(ctx context.Context, name string, start time.Time, attrs []Attr) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+
+	sc := SpanContext{TraceID: parent.TraceID, Sampled: true}
+	if !parent.IsValid() {
+		rand.Read(sc.TraceID[:])
+	} else {
+		sc.Sampled = parent.Sampled
+	}
+	rand.Read(sc.SpanID[:])
+
+	sp := &Span{tracer: t, data: SpanData{
+		Name:        name,
+		SpanContext: sc,
+		Parent:      parent.SpanID,
+		Start:       start,
+		Attributes:  attrs,
+	}}
+	return context.WithValue(ctx, spanKey{}, sp), sp
+}
+
+// Span is an operation being traced. A nil span is valid and records nothing,
+// so code can be traced whether or not a tracer is configured.
+type Span struct {
+	// tracer is the tracer that started the span.
+	tracer *Tracer
+	// mu guards data and ended.
+	mu sync.Mutex
+	// data is the span being recorded.
+	data SpanData
+	// ended is set once the span has been exported.
+	ended bool
+}
+
+// SpanContext returns the context of the span.
+func (s *Span) SpanContext() (sc SpanContext) {
+	if s == nil {
+		return
+	}
+	return s.data.SpanContext
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// SetName renames the span, e.g. once the route of a request is known.
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Name = name
+}
+
+// End ends the span, failed if err is not nil, and exports it. Only the first call has effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	if err != nil {
+		s.data.Error = err.Error()
+	}
+	data := s.data
+	s.mu.Unlock()
+
+	if data.SpanContext.Sampled && s.tracer.exporter != nil {
+		s.tracer.exporter.Export(data)
+	}
+}
+
+// spanKey is the key of the active span in a context.
+type spanKey struct{}
+
+// remoteKey is the key of the remote span context in a context.
+type remoteKey struct{}
+
+// WithRemote returns a copy of ctx carrying sc, the span context received from the caller,
+// so the next started span continues its trace.
+func WithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanFromContext returns the active span carried by ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	sp, _ := ctx.Value(spanKey{}).(*Span)
+	return sp
+}
+
+// SpanContextFromContext returns the context of the active span carried by ctx,
+// or the remote span context if there is no active span.
+func SpanContextFromContext(ctx context.Context) (sc SpanContext) {
+	if sp := SpanFromContext(ctx); sp != nil {
+		return sp.SpanContext()
+	}
+	sc, _ = ctx.Value(remoteKey{}).(SpanContext)
+	return
+}
+
+// Start starts a span named name, child of the active span carried by ctx, with the same tracer.
+// Without an active span nothing is traced: ctx is returned as is with a nil span.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name, attrs...)
+}
+
+// Record records an already finished operation that started at start, as a child of the active span carried by ctx.
+// It is meant for leaf operations timed by the caller, such as queries.
+func Record(ctx context.Context, name string, start time.Time, err error, attrs ...Attr) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return
+	}
+	_, sp := parent.tracer.start(ctx, name, start, attrs)
+	sp.End(err)
+}
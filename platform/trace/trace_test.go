@@ -0,0 +1,145 @@
+package trace_test
+
+import (
+	"app/platform/trace"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the tracer
+func TestTracer(t *testing.T) {
+	t.Run("child spans share the trace of their parent", func(t *testing.T) {
+		// arrange
+		rec := trace.NewRecorder()
+		tr := trace.NewTracer(rec)
+
+		// act
+		ctx, root := tr.Start(context.Background(), "root")
+		_, child := trace.Start(ctx, "child", trace.String("k", "v"))
+		trace.Record(ctx, "query", time.Now(), errors.New("boom"))
+		child.End(nil)
+		root.End(nil)
+
+		// assert
+		spans := rec.Spans()
+		require.Len(t, spans, 3)
+		require.Equal(t, "query", spans[0].Name)
+		require.Equal(t, "boom", spans[0].Error)
+		require.Equal(t, "child", spans[1].Name)
+		require.Equal(t, []trace.Attr{trace.String("k", "v")}, spans[1].Attributes)
+		require.Equal(t, "root", spans[2].Name)
+		require.False(t, spans[2].Parent.IsValid())
+		for _, sp := range spans[:2] {
+			require.Equal(t, spans[2].SpanContext.TraceID, sp.SpanContext.TraceID)
+			require.Equal(t, spans[2].SpanContext.SpanID, sp.Parent)
+		}
+	})
+
+	t.Run("nothing is traced without an active span", func(t *testing.T) {
+		// act
+		ctx, sp := trace.Start(context.Background(), "orphan")
+		sp.SetAttributes(trace.Int("k", 1))
+		sp.End(nil)
+
+		// assert
+		require.Nil(t, sp)
+		require.Nil(t, trace.SpanFromContext(ctx))
+	})
+
+	t.Run("remote span context is continued", func(t *testing.T) {
+		// arrange
+		rec := trace.NewRecorder()
+		sc, err := trace.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		require.NoError(t, err)
+
+		// act
+		_, sp := trace.NewTracer(rec).Start(trace.WithRemote(context.Background(), sc), "root")
+		sp.End(nil)
+
+		// assert
+		spans := rec.Spans()
+		require.Len(t, spans, 1)
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID.String())
+		require.Equal(t, "00f067aa0ba902b7", spans[0].Parent.String())
+	})
+}
+
+// Tests for ParseTraceparent function
+func TestParseTraceparent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		header    string
+		expectErr bool
+	}{
+		{name: "valid", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "future version with extra fields", header: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "empty", header: "", expectErr: true},
+		{name: "forbidden version", header: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expectErr: true},
+		{name: "zero trace id", header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", expectErr: true},
+		{name: "not hex", header: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", expectErr: true},
+		{name: "extra fields in version 00", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			sc, err := trace.ParseTraceparent(tc.header)
+
+			// assert
+			if tc.expectErr {
+				require.ErrorIs(t, err, trace.ErrTraceparentInvalid)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, sc.Sampled)
+			require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+		})
+	}
+}
+
+// Tests for OTLPFileExporter
+func TestOTLPFileExporter(t *testing.T) {
+	t.Run("spans are written as OTLP/JSON lines", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		tr := trace.NewTracer(trace.NewOTLPFileExporter(&buf, "test"))
+
+		// act
+		_, sp := tr.Start(context.Background(), "GET /customers/top", trace.Int("http.status_code", 500))
+		sp.End(errors.New("500 Internal Server Error"))
+
+		// assert
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						TraceID    string `json:"traceId"`
+						Name       string `json:"name"`
+						Attributes []struct {
+							Key   string `json:"key"`
+							Value struct {
+								IntValue string `json:"intValue"`
+							} `json:"value"`
+						} `json:"attributes"`
+						Status struct {
+							Code int `json:"code"`
+						} `json:"status"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &req))
+		span := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		require.Len(t, span.TraceID, 32)
+		require.Equal(t, "GET /customers/top", span.Name)
+		require.Equal(t, "http.status_code", span.Attributes[0].Key)
+		require.Equal(t, "500", span.Attributes[0].Value.IntValue)
+		require.Equal(t, 2, span.Status.Code)
+	})
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"app/platform/auth"
+	"app/platform/logger"
+	"app/platform/web/response"
+)
+
+// Authenticate returns a middleware that authenticates the request credentials with a and carries the principal
+// in the request context, along with what each of with derives from it. Requests without credentials go on anonymous,
+// so public routes stay reachable; requests with invalid credentials are rejected with 401.
+// A nil authenticator accepts no credentials.
+func Authenticate(a *auth.Authenticator, with ...func(ctx context.Context, p auth.Principal) context.Context) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if a == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			p, err := a.Authenticate(r)
+			switch {
+			case errors.Is(err, auth.ErrCredentialsMissing):
+				next.ServeHTTP(w, r)
+			case err != nil:
+				logger.FromContext(r.Context()).Warn("authentication failed", "error", err)
+				unauthorized(w, "invalid credentials")
+			default:
+				ctx := auth.WithPrincipal(r.Context(), p)
+				for _, fn := range with {
+					ctx = fn(ctx, p)
+				}
+				next.ServeHTTP(w, r.WithContext(ctx))
+			}
+		})
+	}
+}
+
+// RequireRole returns a middleware that only lets through principals granted role:
+// anonymous requests get 401 and principals with a lower role get 403. It must run after Authenticate.
+func RequireRole(role auth.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := auth.PrincipalFromContext(r.Context())
+			if !ok {
+				unauthorized(w, "authentication required")
+				return
+			}
+			if !p.Role.Allows(role) {
+				logger.FromContext(r.Context()).Warn("authorization denied", "subject", p.Subject, "role", p.Role, "required", role)
+				response.Errorf(w, http.StatusForbidden, "role %s required", role)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// unauthorized writes a 401 response with the supported authentication scheme.
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="app"`)
+	response.Error(w, http.StatusUnauthorized, message)
+}
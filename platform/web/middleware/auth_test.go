@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"app/platform/auth"
+	"app/platform/web/middleware"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Authenticate and RequireRole middlewares
+func TestAuth(t *testing.T) {
+	a := auth.NewAuthenticator(auth.Config{APIKeys: map[string]auth.Principal{
+		"reader-key": {Subject: "r", Role: auth.RoleReader},
+		"admin-key":  {Subject: "a", Role: auth.RoleAdmin},
+	}})
+	h := middleware.Authenticate(a)(middleware.RequireRole(auth.RoleClerk)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := auth.PrincipalFromContext(r.Context())
+		w.Write([]byte(p.Subject))
+	})))
+
+	testCases := []struct {
+		name       string
+		key        string
+		expectCode int
+		expectBody string
+	}{
+		{name: "anonymous", expectCode: http.StatusUnauthorized, expectBody: `{"type":"urn:app:problem:unauthorized","title":"Unauthorized","status":401,"detail":"authentication required","code":"unauthorized"}`},
+		{name: "invalid key", key: "nope", expectCode: http.StatusUnauthorized, expectBody: `{"type":"urn:app:problem:unauthorized","title":"Unauthorized","status":401,"detail":"invalid credentials","code":"unauthorized"}`},
+		{name: "lower role", key: "reader-key", expectCode: http.StatusForbidden, expectBody: `{"type":"urn:app:problem:forbidden","title":"Forbidden","status":403,"detail":"role clerk required","code":"forbidden"}`},
+		{name: "higher role", key: "admin-key", expectCode: http.StatusOK, expectBody: "a"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			req := httptest.NewRequest(http.MethodPost, "/invoices", nil)
+			if tc.key != "" {
+				req.Header.Set(auth.APIKeyHeader, tc.key)
+			}
+			res := httptest.NewRecorder()
+
+			// act
+			h.ServeHTTP(res, req)
+
+			// assert
+			require.Equal(t, tc.expectCode, res.Code)
+			require.Equal(t, tc.expectBody, res.Body.String())
+			if tc.expectCode == http.StatusUnauthorized {
+				require.NotEmpty(t, res.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+// Tests for Authenticate middleware with context derived from the principal
+func TestAuthenticateWith(t *testing.T) {
+	// arrange
+	type subjectKey struct{}
+	a := auth.NewAuthenticator(auth.Config{APIKeys: map[string]auth.Principal{"clerk-key": {Subject: "c", Role: auth.RoleClerk}}})
+	with := func(ctx context.Context, p auth.Principal) context.Context {
+		return context.WithValue(ctx, subjectKey{}, "subject "+p.Subject)
+	}
+	h := middleware.Authenticate(a, with)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, _ := r.Context().Value(subjectKey{}).(string)
+		w.Write([]byte(s))
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/invoices", nil)
+	req.Header.Set(auth.APIKeyHeader, "clerk-key")
+	res := httptest.NewRecorder()
+
+	// act
+	h.ServeHTTP(res, req)
+	anonymous := httptest.NewRecorder()
+	h.ServeHTTP(anonymous, httptest.NewRequest(http.MethodPost, "/invoices", nil))
+
+	// assert
+	require.Equal(t, "subject c", res.Body.String())
+	require.Empty(t, anonymous.Body.String())
+}
@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ETagVersionsMax is the maximum number of versions an ETag middleware remembers, the least recently served
+// evicted first. An evicted version is registered again as modified when next served.
+const ETagVersionsMax = 1024
+
+// NewETag returns a new ETag middleware. cacheControl is sent as the Cache-Control header
+// of the responses, unless empty. params are the query parameters the route reads: the others
+// do not change the response, so they do not make a version of their own.
+func NewETag(cacheControl string, params ...string) *ETag {
+	return &ETag{
+		cacheControl: cacheControl,
+		params:       params,
+		ll:           list.New(),
+		versions:     make(map[string]*list.Element),
+		now:          time.Now,
+	}
+}
+
+// ETag is a middleware that makes GET and HEAD responses conditional.
+// It computes a strong ETag from the response body and remembers when the body of
+// each path and query parameters last changed, to answer If-None-Match and If-Modified-Since with 304 Not Modified.
+type ETag struct {
+	// cacheControl is the Cache-Control header of the responses.
+	cacheControl string
+	// params are the query parameters of the route.
+	params []string
+	// mu guards ll and versions.
+	mu sync.Mutex
+	// ll keeps the versions ordered from most to least recently served.
+	ll *list.List
+	// versions indexes the elements of ll by key.
+	versions map[string]*list.Element
+	// now returns the current time, replaceable in tests.
+	now func() time.Time
+}
+
+// version is a version of a response body.
+type version struct {
+	key          string
+	etag         string
+	lastModified time.Time
+}
+
+// Len returns the number of versions remembered, ETagVersionsMax at most.
+func (m *ETag) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ll.Len()
+}
+
+// Handler wraps next.
+func (m *ETag) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// record the response
+		rec := &bufferedWriter{header: make(http.Header), code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// copy headers
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+
+		// only successful responses are conditional
+		if rec.code != http.StatusOK {
+			w.WriteHeader(rec.code)
+			w.Write(rec.body.Bytes())
+			return
+		}
+
+		// version
+		v := m.version(m.key(r), rec.body.Bytes())
+		w.Header().Set("ETag", v.etag)
+		w.Header().Set("Last-Modified", v.lastModified.Format(http.TimeFormat))
+		if m.cacheControl != "" {
+			w.Header().Set("Cache-Control", m.cacheControl)
+		}
+
+		// conditions
+		if notModified(r, v) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(rec.code)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+// key returns the key of the versions of r: its path and the values of the query parameters of the route.
+func (m *ETag) key(r *http.Request) string {
+	q := r.URL.Query()
+	params := url.Values{}
+	for _, p := range m.params {
+		if v, ok := q[p]; ok {
+			params[p] = v
+		}
+	}
+	if len(params) == 0 {
+		return r.URL.Path
+	}
+	return r.URL.Path + "?" + params.Encode()
+}
+
+// version returns the version of body served for key, registering it if it changed.
+func (m *ETag) version(key string, body []byte) version {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.versions[key]; ok {
+		m.ll.MoveToFront(el)
+		if v := el.Value.(*version); v.etag == etag {
+			return *v
+		}
+		m.ll.Remove(el)
+		delete(m.versions, key)
+	}
+
+	// http dates have a precision of seconds
+	v := &version{key: key, etag: etag, lastModified: m.now().UTC().Truncate(time.Second)}
+	m.versions[key] = m.ll.PushFront(v)
+	if m.ll.Len() > ETagVersionsMax {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.versions, oldest.Value.(*version).key)
+	}
+
+	return *v
+}
+
+// notModified reports whether the request conditions match v.
+// If-None-Match takes precedence over If-Modified-Since (RFC 9110, section 13.2.2).
+func notModified(r *http.Request, v version) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			// If-None-Match uses the weak comparison
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == v.etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !v.lastModified.After(t)
+	}
+
+	return false
+}
+
+// bufferedWriter is a http.ResponseWriter that keeps the response in memory.
+type bufferedWriter struct {
+	header      http.Header
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// Header returns the response headers.
+func (b *bufferedWriter) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader records the status code.
+func (b *bufferedWriter) WriteHeader(code int) {
+	if b.wroteHeader {
+		return
+	}
+	b.code = code
+	b.wroteHeader = true
+}
+
+// Write records the body.
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
@@ -0,0 +1,156 @@
+package middleware_test
+
+import (
+	"app/platform/web/middleware"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ETag middleware
+func TestETag(t *testing.T) {
+	body := `{"data":[]}`
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	})
+
+	t.Run("200 - sets validators and cache control", func(t *testing.T) {
+		// arrange
+		h := middleware.NewETag("no-cache").Handler(next)
+
+		// act
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/customers/top", nil))
+
+		// assert
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, body, rr.Body.String())
+		require.Regexp(t, `^"[0-9a-f]{32}"$`, rr.Header().Get("ETag"))
+		require.NotEmpty(t, rr.Header().Get("Last-Modified"))
+		require.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+		require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	})
+
+	t.Run("304 - if none match", func(t *testing.T) {
+		// arrange
+		h := middleware.NewETag("").Handler(next)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/customers/top", nil))
+		etag := rr.Header().Get("ETag")
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/customers/top", nil)
+		req.Header.Set("If-None-Match", `"other", `+etag)
+		rr = httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		// assert
+		require.Equal(t, http.StatusNotModified, rr.Code)
+		require.Empty(t, rr.Body.String())
+		require.Equal(t, etag, rr.Header().Get("ETag"))
+		require.Empty(t, rr.Header().Get("Cache-Control"))
+	})
+
+	t.Run("200 - if none match with stale etag", func(t *testing.T) {
+		// arrange
+		h := middleware.NewETag("").Handler(next)
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/customers/top", nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		req.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		// assert
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, body, rr.Body.String())
+	})
+
+	t.Run("304 - if modified since", func(t *testing.T) {
+		// arrange
+		h := middleware.NewETag("").Handler(next)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/customers/top", nil))
+		lastModified := rr.Header().Get("Last-Modified")
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/customers/top", nil)
+		req.Header.Set("If-Modified-Since", lastModified)
+		rr = httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		// assert
+		require.Equal(t, http.StatusNotModified, rr.Code)
+	})
+
+	t.Run("error responses are not conditional", func(t *testing.T) {
+		// arrange
+		h := middleware.NewETag("no-cache").Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/customers/top", nil)
+		req.Header.Set("If-None-Match", "*")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+		require.Empty(t, rr.Header().Get("ETag"))
+	})
+
+	t.Run("other methods pass through", func(t *testing.T) {
+		// arrange
+		h := middleware.NewETag("no-cache").Handler(next)
+
+		// act
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/customers", nil))
+
+		// assert
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Empty(t, rr.Header().Get("ETag"))
+	})
+
+	t.Run("query parameters the route does not read do not make versions", func(t *testing.T) {
+		// arrange
+		m := middleware.NewETag("", "currency")
+		h := m.Handler(next)
+
+		// act
+		for i := 0; i < 100; i++ {
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/customers/top?junk=%d", i), nil))
+		}
+		for _, currency := range []string{"EUR", "USD"} {
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/customers/top?junk=1&currency="+currency, nil))
+		}
+
+		// assert
+		require.Equal(t, 3, m.Len())
+	})
+
+	t.Run("versions are capped", func(t *testing.T) {
+		// arrange
+		m := middleware.NewETag("")
+		h := m.Handler(next)
+
+		// act
+		for i := 0; i < middleware.ETagVersionsMax+10; i++ {
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/products/%d/stock", i), nil))
+		}
+
+		// assert
+		require.Equal(t, middleware.ETagVersionsMax, m.Len())
+	})
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"app/platform/idempotency"
+	"app/platform/logger"
+	"app/platform/web/response"
+)
+
+const (
+	// IdempotencyKeyHeader is the header carrying the idempotency key of a request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on the responses replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// idempotencyKeyMaxLength is the maximum length of an idempotency key.
+	idempotencyKeyMaxLength = 255
+)
+
+// Problem codes of the idempotency errors.
+const (
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeIdempotencyKeyInUse  = "idempotency_key_in_use"
+)
+
+// NewIdempotency creates a new idempotency middleware keeping the responses in store for ttl.
+func NewIdempotency(store idempotency.Store, ttl time.Duration) *Idempotency {
+	return &Idempotency{store: store, ttl: ttl}
+}
+
+// Idempotency is a middleware that makes the requests carrying an Idempotency-Key header safe to retry.
+// The first response to a key, per client, is stored and replayed to the retries; the key cannot be
+// reused for a request with another method, path or body (422), nor while the first request is in flight (409).
+// Only the successful (2xx) responses are stored: the errors, e.g. a conflict or a validation error, release the key,
+// so the request can be retried once their cause is fixed. Clients are identified by RateLimitKey,
+// so it must run after Authenticate.
+type Idempotency struct {
+	// store is the store of the responses.
+	store idempotency.Store
+	// ttl is the time the responses are kept.
+	ttl time.Duration
+}
+
+// Handler wraps next.
+func (m *Idempotency) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > idempotencyKeyMaxLength {
+			response.Errorf(w, http.StatusBadRequest, "%s must be at most %d characters", IdempotencyKeyHeader, idempotencyKeyMaxLength)
+			return
+		}
+
+		// fingerprint the request, keeping its body for the handler
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				response.Error(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			response.Error(w, http.StatusBadRequest, "error reading request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + "\n" + string(body)))
+		fingerprint := hex.EncodeToString(sum[:])
+
+		// reserve the key, scoped to the client
+		storeKey := RateLimitKey(r) + "\n" + key
+		rec, ok := m.store.Begin(storeKey, fingerprint, m.ttl)
+		if !ok {
+			switch {
+			case rec.Fingerprint != fingerprint:
+				response.ErrorCode(w, http.StatusUnprocessableEntity, CodeIdempotencyKeyReused, IdempotencyKeyHeader+" already used for another request")
+			case rec.Response == nil:
+				w.Header().Set("Retry-After", "1")
+				response.ErrorCode(w, http.StatusConflict, CodeIdempotencyKeyInUse, "request with the same "+IdempotencyKeyHeader+" in progress")
+			default:
+				logger.FromContext(r.Context()).Debug("idempotent response replayed", "key", key)
+				replay(w, rec.Response)
+			}
+			return
+		}
+
+		// serve and store the response
+		// - errors and panics release the key, so the request can be retried
+		completed := false
+		defer func() {
+			if !completed {
+				m.store.Release(storeKey)
+			}
+		}()
+		bw := &bufferedWriter{header: make(http.Header), code: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		res := &idempotency.Response{Code: bw.code, Header: bw.header.Clone(), Body: bw.body.Bytes()}
+		if res.Code >= http.StatusOK && res.Code < http.StatusMultipleChoices {
+			m.store.Complete(storeKey, *res, m.ttl)
+			completed = true
+		}
+		write(w, res)
+	})
+}
+
+// replay writes a stored response, flagged as replayed.
+func replay(w http.ResponseWriter, res *idempotency.Response) {
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	write(w, res)
+}
+
+// write writes a response.
+func write(w http.ResponseWriter, res *idempotency.Response) {
+	for k, v := range res.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(res.Code)
+	w.Write(res.Body)
+}
@@ -0,0 +1,130 @@
+package middleware_test
+
+import (
+	"app/platform/idempotency"
+	"app/platform/web/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Idempotency middleware
+func TestIdempotency(t *testing.T) {
+	// handler counting the requests it serves
+	newHandler := func(code int) (http.Handler, *int) {
+		calls := 0
+		h := middleware.NewIdempotency(idempotency.NewMemory(), time.Hour).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			w.Write([]byte(`{"call":` + strconv.Itoa(calls) + `,"body":` + string(body) + `}`))
+		}))
+		return h, &calls
+	}
+	newRequest := func(key, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(body))
+		req.RemoteAddr = "10.0.0.1:1234"
+		if key != "" {
+			req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		}
+		return req
+	}
+
+	t.Run("retry replays the first response", func(t *testing.T) {
+		// arrange
+		h, calls := newHandler(http.StatusCreated)
+
+		// act
+		first := httptest.NewRecorder()
+		h.ServeHTTP(first, newRequest("k1", `{"a":1}`))
+		retry := httptest.NewRecorder()
+		h.ServeHTTP(retry, newRequest("k1", `{"a":1}`))
+
+		// assert
+		require.Equal(t, 1, *calls)
+		require.Equal(t, http.StatusCreated, first.Code)
+		require.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+		require.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		require.Equal(t, `{"call":1,"body":{"a":1}}`, retry.Body.String())
+	})
+
+	t.Run("same key with another body gets 422", func(t *testing.T) {
+		// arrange
+		h, calls := newHandler(http.StatusCreated)
+
+		// act
+		h.ServeHTTP(httptest.NewRecorder(), newRequest("k1", `{"a":1}`))
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, newRequest("k1", `{"a":2}`))
+
+		// assert
+		require.Equal(t, 1, *calls)
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	})
+
+	t.Run("keys are scoped by client", func(t *testing.T) {
+		// arrange
+		h, calls := newHandler(http.StatusCreated)
+		other := newRequest("k1", `{"a":1}`)
+		other.RemoteAddr = "10.0.0.2:1234"
+
+		// act
+		h.ServeHTTP(httptest.NewRecorder(), newRequest("k1", `{"a":1}`))
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, other)
+
+		// assert
+		require.Equal(t, 2, *calls)
+		require.Empty(t, res.Header().Get(middleware.IdempotentReplayedHeader))
+	})
+
+	t.Run("errors are not stored", func(t *testing.T) {
+		for _, code := range []int{http.StatusInternalServerError, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusTooManyRequests} {
+			// arrange
+			h, calls := newHandler(code)
+
+			// act
+			h.ServeHTTP(httptest.NewRecorder(), newRequest("k1", `{"a":1}`))
+			retry := httptest.NewRecorder()
+			h.ServeHTTP(retry, newRequest("k1", `{"a":1}`))
+
+			// assert
+			require.Equal(t, 2, *calls, "code %d", code)
+			require.Empty(t, retry.Header().Get(middleware.IdempotentReplayedHeader), "code %d", code)
+		}
+	})
+
+	t.Run("requests without key pass through", func(t *testing.T) {
+		// arrange
+		h, calls := newHandler(http.StatusCreated)
+
+		// act
+		h.ServeHTTP(httptest.NewRecorder(), newRequest("", `{"a":1}`))
+		h.ServeHTTP(httptest.NewRecorder(), newRequest("", `{"a":1}`))
+
+		// assert
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("key too long gets 400", func(t *testing.T) {
+		// arrange
+		h, calls := newHandler(http.StatusCreated)
+		res := httptest.NewRecorder()
+
+		// act
+		h.ServeHTTP(res, newRequest(strings.Repeat("k", 256), `{"a":1}`))
+
+		// assert
+		require.Equal(t, 0, *calls)
+		require.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
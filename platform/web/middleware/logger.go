@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"app/platform/logger"
+	"app/platform/trace"
+)
+
+// Logger returns a middleware that carries a request scoped logger in the request context,
+// with the request id, method, path and trace id as fields, and logs every response once written.
+// It must run after RequestID and, if tracing, after Trace.
+func Logger(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// request scoped logger
+			rl := l.With(
+				slog.String("request_id", GetRequestID(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+			)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				rl = rl.With(slog.String("trace_id", sc.TraceID.String()))
+			}
+			ww := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+			next.ServeHTTP(ww, r.WithContext(logger.WithContext(r.Context(), rl)))
+
+			// access log, server errors are logged as errors
+			level := slog.LevelInfo
+			if ww.code >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			rl.LogAttrs(r.Context(), level, "request completed",
+				slog.Int("status", ww.code),
+				slog.Int("bytes", ww.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// statusWriter is a http.ResponseWriter that records the status code and the body size.
+type statusWriter struct {
+	http.ResponseWriter
+	code        int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code.
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.code = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the body size.
+func (w *statusWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"app/platform/logger"
+	"app/platform/web/middleware"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for RequestID and Logger middlewares
+func TestLogger(t *testing.T) {
+	t.Run("request id is generated and carried by the logger", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := slog.New(slog.NewJSONHandler(&buf, nil))
+		h := middleware.RequestID(middleware.Logger(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger.FromContext(r.Context()).Error("error getting customers", "error", "boom")
+			w.WriteHeader(http.StatusInternalServerError)
+		})))
+
+		// act
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/customers", nil))
+
+		// assert
+		id := rr.Header().Get(middleware.RequestIDHeader)
+		require.Len(t, id, 32)
+		dec := json.NewDecoder(&buf)
+		var handlerRecord, accessRecord map[string]any
+		require.NoError(t, dec.Decode(&handlerRecord))
+		require.NoError(t, dec.Decode(&accessRecord))
+		require.Equal(t, id, handlerRecord["request_id"])
+		require.Equal(t, "boom", handlerRecord["error"])
+		require.Equal(t, id, accessRecord["request_id"])
+		require.Equal(t, "/customers", accessRecord["path"])
+		require.Equal(t, float64(http.StatusInternalServerError), accessRecord["status"])
+		require.Equal(t, "ERROR", accessRecord["level"])
+	})
+
+	t.Run("request id sent by the client is kept", func(t *testing.T) {
+		// arrange
+		h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(middleware.GetRequestID(r.Context())))
+		}))
+
+		// act
+		req := httptest.NewRequest(http.MethodGet, "/customers", nil)
+		req.Header.Set(middleware.RequestIDHeader, "abc")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		// assert
+		require.Equal(t, "abc", rr.Header().Get(middleware.RequestIDHeader))
+		require.Equal(t, "abc", rr.Body.String())
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/platform/metrics"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Metrics returns a middleware that records the number and the latency of the requests in reg,
+// labelled by method, chi route pattern and status. Requests that match no route are labelled "unmatched",
+// so unknown paths do not create new series.
+func Metrics(reg *metrics.Registry) func(http.Handler) http.Handler {
+	requests := reg.Counter("http_requests_total",
+		"Number of HTTP requests.", "method", "route", "status")
+	duration := reg.Histogram("http_request_duration_seconds",
+		"Duration of the HTTP requests in seconds.", metrics.DefBuckets, "method", "route")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ww := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+			next.ServeHTTP(ww, r)
+
+			// the route pattern is only known once the router has routed the request
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			requests.With(r.Method, route, strconv.Itoa(ww.code)).Inc()
+			duration.With(r.Method, route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"app/platform/metrics"
+	"app/platform/web/middleware"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Metrics middleware
+func TestMetrics(t *testing.T) {
+	t.Run("requests are labelled by route pattern and status", func(t *testing.T) {
+		// arrange
+		reg := metrics.NewRegistry()
+		rt := chi.NewRouter()
+		rt.Use(middleware.Metrics(reg))
+		rt.Get("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		// act
+		rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/1", nil))
+		rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/2", nil))
+		rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+		// assert
+		var buf bytes.Buffer
+		_, err := reg.WriteTo(&buf)
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), `http_requests_total{method="GET",route="/products/{id}",status="404"} 2`)
+		require.Contains(t, buf.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+		require.Contains(t, buf.String(), `http_request_duration_seconds_count{method="GET",route="/products/{id}"} 2`)
+	})
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"app/platform/auth"
+	"app/platform/logger"
+	"app/platform/ratelimit"
+	"app/platform/web/response"
+)
+
+// RateLimit returns a middleware that takes a token from the bucket of the client in l for every request,
+// and rejects the request with 429 and a Retry-After header when the bucket is empty.
+// Clients are identified by RateLimitKey: by their IP address before Authenticate, which limits the requests with
+// invalid credentials too, and by their subject after it.
+func RateLimit(l *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := RateLimitKey(r)
+			res := l.Allow(key)
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			if !res.Allowed {
+				// - whole seconds, rounded up so the client does not retry too early
+				w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(res.RetryAfter.Seconds())), 10))
+				logger.FromContext(r.Context()).Debug("rate limit exceeded", "key", key)
+				response.Error(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitKey returns the key that identifies the client of r: the authenticated subject if any,
+// so every client behind an API key shares a bucket, or the remote IP address.
+func RateLimitKey(r *http.Request) string {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return "subject:" + p.Subject
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// MaxBodySize returns a middleware that limits the request body to n bytes.
+// Reading past the limit fails, which request.JSON reports as request.ErrRequestBodyTooLarge.
+func MaxBodySize(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				response.Error(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
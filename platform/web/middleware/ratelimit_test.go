@@ -0,0 +1,102 @@
+package middleware_test
+
+import (
+	"app/platform/auth"
+	"app/platform/ratelimit"
+	"app/platform/web/middleware"
+	"app/platform/web/request"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for RateLimit middleware
+func TestRateLimit(t *testing.T) {
+	t.Run("requests over the burst get 429 with Retry-After", func(t *testing.T) {
+		// arrange
+		h := middleware.RateLimit(ratelimit.NewLimiter(0.5, 1))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		newRequest := func(addr string) *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/customers/top", nil)
+			req.RemoteAddr = addr
+			return req
+		}
+
+		// act
+		first := httptest.NewRecorder()
+		h.ServeHTTP(first, newRequest("10.0.0.1:1234"))
+		second := httptest.NewRecorder()
+		h.ServeHTTP(second, newRequest("10.0.0.1:5678"))
+		other := httptest.NewRecorder()
+		h.ServeHTTP(other, newRequest("10.0.0.2:1234"))
+
+		// assert
+		require.Equal(t, http.StatusOK, first.Code)
+		require.Equal(t, http.StatusTooManyRequests, second.Code)
+		require.Equal(t, "2", second.Header().Get("Retry-After"))
+		require.Equal(t, `{"type":"urn:app:problem:rate_limited","title":"Too Many Requests","status":429,"detail":"rate limit exceeded","code":"rate_limited"}`, second.Body.String())
+		require.Equal(t, http.StatusOK, other.Code)
+	})
+
+	t.Run("before Authenticate, invalid credentials are limited by client IP", func(t *testing.T) {
+		// arrange
+		a := auth.NewAuthenticator(auth.Config{APIKeys: map[string]auth.Principal{"reader-key": {Subject: "r", Role: auth.RoleReader}}})
+		h := middleware.RateLimit(ratelimit.NewLimiter(0.5, 2))(middleware.Authenticate(a)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+		newRequest := func(key string) *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/customers", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set(auth.APIKeyHeader, key)
+			return req
+		}
+
+		// act
+		codes := make([]int, 3)
+		for ix, key := range []string{"guess-1", "guess-2", "reader-key"} {
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, newRequest(key))
+			codes[ix] = res.Code
+		}
+
+		// assert
+		require.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	})
+}
+
+// Tests for MaxBodySize middleware
+func TestMaxBodySize(t *testing.T) {
+	h := middleware.MaxBodySize(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := request.JSON(r, &body); err != nil {
+			w.Write([]byte(err.Error()))
+		}
+	}))
+
+	t.Run("declared length over the limit gets 413", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodPost, "/sales", strings.NewReader(`{"quantity": 100000000}`))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+
+		// act
+		h.ServeHTTP(res, req)
+
+		// assert
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+	})
+
+	t.Run("streamed body over the limit fails to decode", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodPost, "/sales", strings.NewReader(`{"quantity": 100000000}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = -1
+		res := httptest.NewRecorder()
+
+		// act
+		h.ServeHTTP(res, req)
+
+		// assert
+		require.Equal(t, request.ErrRequestBodyTooLarge.Error(), res.Body.String())
+	})
+}
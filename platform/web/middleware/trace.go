@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"app/platform/trace"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TraceparentHeader is the W3C trace context header.
+const TraceparentHeader = "traceparent"
+
+// Trace returns a middleware that starts the root span of every request with t, continuing the trace
+// of a valid traceparent header sent by the client, and writes the traceparent of the span in the response.
+// The span is named after the method and the chi route pattern once the request is routed.
+func Trace(t *trace.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if sc, err := trace.ParseTraceparent(r.Header.Get(TraceparentHeader)); err == nil {
+				ctx = trace.WithRemote(ctx, sc)
+			}
+
+			ctx, sp := t.Start(ctx, "HTTP "+r.Method,
+				trace.String("http.method", r.Method),
+				trace.String("http.target", r.URL.RequestURI()),
+			)
+			w.Header().Set(TraceparentHeader, sp.SpanContext().Traceparent())
+
+			ww := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			// the route pattern is only known once the router has routed the request
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				sp.SetName(r.Method + " " + rctx.RoutePattern())
+				sp.SetAttributes(trace.String("http.route", rctx.RoutePattern()))
+			}
+			sp.SetAttributes(trace.Int("http.status_code", ww.code))
+
+			var err error
+			if ww.code >= http.StatusInternalServerError {
+				err = fmt.Errorf("%d %s", ww.code, http.StatusText(ww.code))
+			}
+			sp.End(err)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware_test
+
+import (
+	"app/platform/trace"
+	"app/platform/web/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Trace middleware
+func TestTrace(t *testing.T) {
+	t.Run("root span continues the client trace and is named after the route", func(t *testing.T) {
+		// arrange
+		rec := trace.NewRecorder()
+		rt := chi.NewRouter()
+		rt.Use(middleware.Trace(trace.NewTracer(rec)))
+		rt.Get("/customers/top", func(w http.ResponseWriter, r *http.Request) {
+			_, sp := trace.Start(r.Context(), "service.customers.GetTopCustomers")
+			sp.End(nil)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/customers/top", nil)
+		req.Header.Set(middleware.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		// act
+		res := httptest.NewRecorder()
+		rt.ServeHTTP(res, req)
+
+		// assert
+		spans := rec.Spans()
+		require.Len(t, spans, 2)
+		root := spans[1]
+		require.Equal(t, "GET /customers/top", root.Name)
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", root.SpanContext.TraceID.String())
+		require.Equal(t, "00f067aa0ba902b7", root.Parent.String())
+		require.Equal(t, root.SpanContext.SpanID, spans[0].Parent)
+		require.Equal(t, root.SpanContext.Traceparent(), res.Header().Get(middleware.TraceparentHeader))
+		require.Contains(t, root.Attributes, trace.Int("http.status_code", http.StatusOK))
+	})
+}
@@ -4,31 +4,180 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strings"
 )
 
-// JSON decodes json from request body to ptr
+// MaxBodySize is the maximum size of a request body read by JSON, in bytes.
+var MaxBodySize int64 = 1 << 20
+
 var (
 	// ErrRequestContentTypeNotJSON is used when the request content type is not application/json.
 	ErrRequestContentTypeNotJSON = errors.New("request content type is not application/json")
 	// ErrRequestJSONInvalid is used when the request json is invalid.
 	ErrRequestJSONInvalid = errors.New("request json invalid")
+	// ErrRequestBodyTooLarge is used when the request body is larger than the allowed size.
+	ErrRequestBodyTooLarge = errors.New("request body too large")
 )
 
-// JSON decodes json from request body to ptr
+// JSONError is the error returned when the request json is invalid.
+// It unwraps to ErrRequestJSONInvalid.
+type JSONError struct {
+	// Field is the path of the field that failed, e.g. "customer_id", if known.
+	Field string
+	// Offset is the offset in the body, in bytes, where decoding failed. Unknown fields are
+	// reported at the end of the object holding them.
+	Offset int64
+	// Reason describes the failure.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *JSONError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(ErrRequestJSONInvalid.Error())
+	sb.WriteString(". ")
+	if e.Field != "" {
+		fmt.Fprintf(&sb, "field %q: ", e.Field)
+	}
+	sb.WriteString(e.Reason)
+	fmt.Fprintf(&sb, " (offset %d)", e.Offset)
+	return sb.String()
+}
+
+// Unwrap returns ErrRequestJSONInvalid.
+func (e *JSONError) Unwrap() error {
+	return ErrRequestJSONInvalid
+}
+
+// ConfigDecoder is the configuration for NewDecoder.
+type ConfigDecoder struct {
+	// DisallowUnknownFields rejects the objects with fields the destination does not have.
+	DisallowUnknownFields bool
+	// AllowTrailingData accepts data after the first json value. By default the body must be a single value.
+	AllowTrailingData bool
+	// UseNumber decodes the numbers into an interface{} as json.Number instead of float64.
+	UseNumber bool
+	// MaxBodySize is the maximum size of the body, in bytes. Zero uses the package MaxBodySize.
+	MaxBodySize int64
+}
+
+// NewDecoder creates a new Decoder.
+func NewDecoder(cfg ConfigDecoder) *Decoder {
+	return &Decoder{cfg: cfg}
+}
+
+// Decoder decodes json request bodies. The media type of the Content-Type header
+// must be application/json; parameters, such as charset, are accepted.
+type Decoder struct {
+	// cfg is the configuration.
+	cfg ConfigDecoder
+}
+
+// DefaultDecoder is the decoder used by JSON: it rejects unknown fields and trailing data.
+var DefaultDecoder = NewDecoder(ConfigDecoder{DisallowUnknownFields: true})
+
+// JSON decodes json from request body to ptr, with DefaultDecoder
 func JSON(r *http.Request, ptr any) (err error) {
+	return DefaultDecoder.Decode(r, ptr)
+}
+
+// Decode decodes json from request body to ptr
+func (d *Decoder) Decode(r *http.Request, ptr any) (err error) {
 	// check content type
-	if r.Header.Get("Content-Type") != "application/json" {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
 		err = ErrRequestContentTypeNotJSON
 		return
 	}
 
-	// get body
-	err = json.NewDecoder(r.Body).Decode(ptr)
+	// get body, up to the max size
+	maxBodySize := d.cfg.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = MaxBodySize
+	}
+	body := &limitedReader{r: r.Body, n: maxBodySize}
+	dec := json.NewDecoder(body)
+	if d.cfg.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if d.cfg.UseNumber {
+		dec.UseNumber()
+	}
+
+	err = dec.Decode(ptr)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
+		err = decodeError(dec, body, err)
+		return
+	}
+
+	// - single value: only whitespace may follow
+	if !d.cfg.AllowTrailingData {
+		offset := dec.InputOffset()
+		_, errToken := dec.Token()
+		if errors.Is(errToken, io.EOF) {
+			return
+		}
+		if errToken != nil && decodeError(dec, body, errToken) == ErrRequestBodyTooLarge {
+			err = ErrRequestBodyTooLarge
+			return
+		}
+		err = &JSONError{Offset: offset, Reason: "unexpected data after the json value"}
 		return
 	}
 
 	return
 }
+
+// decodeError maps an error of dec to ErrRequestBodyTooLarge or a *JSONError.
+func decodeError(dec *json.Decoder, body *limitedReader, err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.Is(err, ErrRequestBodyTooLarge) || errors.As(err, &maxBytesErr) {
+		return ErrRequestBodyTooLarge
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &JSONError{Offset: syntaxErr.Offset, Reason: strings.TrimPrefix(syntaxErr.Error(), "json: ")}
+	case errors.As(err, &typeErr):
+		return &JSONError{Field: typeErr.Field, Offset: typeErr.Offset, Reason: fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value)}
+	case errors.Is(err, io.EOF):
+		return &JSONError{Offset: dec.InputOffset(), Reason: "empty body"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		// - the decoder does not consume a truncated value: it failed at the end of the body
+		return &JSONError{Offset: body.read, Reason: "unexpected EOF"}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// - encoding/json has no typed error for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &JSONError{Field: field, Offset: dec.InputOffset(), Reason: "unknown field"}
+	}
+	return &JSONError{Offset: dec.InputOffset(), Reason: strings.TrimPrefix(err.Error(), "json: ")}
+}
+
+// limitedReader reads from r, failing with ErrRequestBodyTooLarge past n bytes.
+type limitedReader struct {
+	r    io.Reader
+	n    int64
+	read int64
+}
+
+func (l *limitedReader) Read(p []byte) (n int, err error) {
+	if l.n < 0 {
+		return 0, ErrRequestBodyTooLarge
+	}
+	// - read one byte more than allowed, to tell a body of exactly n bytes from a larger one
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err = l.r.Read(p)
+	l.n -= int64(n)
+	l.read += int64(n)
+	if l.n < 0 {
+		return n, ErrRequestBodyTooLarge
+	}
+	return
+}
@@ -2,6 +2,7 @@ package request_test
 
 import (
 	"app/platform/web/request"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
@@ -70,7 +71,144 @@ func TestRequestJSON(t *testing.T) {
 		// assert
 		expectedSchema := schema{}
 		require.ErrorIs(t, err, request.ErrRequestJSONInvalid)
-		require.EqualError(t, err, "request json invalid. unexpected EOF")
+		require.EqualError(t, err, "request json invalid. unexpected EOF (offset 14)")
 		require.Equal(t, expectedSchema, inputSchema)
 	})
+
+	t.Run("error - body too large", func(t *testing.T) {
+		// arrange
+		type schema struct {
+			Name string `json:"name"`
+		}
+		defer func(n int64) { request.MaxBodySize = n }(request.MaxBodySize)
+		request.MaxBodySize = 16
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`{"name":"a longer name"}`)),
+		}
+		err := request.JSON(&inputRequest, &inputSchema)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestBodyTooLarge)
+	})
+
+	t.Run("success - body of exactly the max size", func(t *testing.T) {
+		// arrange
+		type schema struct {
+			Name string `json:"name"`
+		}
+		defer func(n int64) { request.MaxBodySize = n }(request.MaxBodySize)
+		request.MaxBodySize = 15
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`{"name":"test"}`)),
+		}
+		err := request.JSON(&inputRequest, &inputSchema)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, schema{Name: "test"}, inputSchema)
+	})
+	t.Run("success - content-type with parameters", func(t *testing.T) {
+		// arrange
+		type schema struct {
+			Name string `json:"name"`
+		}
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			Body:   io.NopCloser(strings.NewReader(`{"name":"test"}` + "\n")),
+		}
+		err := request.JSON(&inputRequest, &inputSchema)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, schema{Name: "test"}, inputSchema)
+	})
+
+	t.Run("error - typed json errors", func(t *testing.T) {
+		type schema struct {
+			Name     string `json:"name"`
+			Quantity int    `json:"quantity"`
+		}
+		testCases := []struct {
+			name        string
+			body        string
+			expectField string
+			expectError string
+		}{
+			{
+				name:        "unknown field",
+				body:        `{"name":"test","nmae":"typo"}`,
+				expectField: "nmae",
+				expectError: `request json invalid. field "nmae": unknown field (offset 29)`,
+			},
+			{
+				name:        "wrong type",
+				body:        `{"quantity":"one"}`,
+				expectField: "quantity",
+				expectError: `request json invalid. field "quantity": expected int, got string (offset 17)`,
+			},
+			{
+				name:        "syntax",
+				body:        `{"name":}`,
+				expectError: `request json invalid. invalid character '}' looking for beginning of value (offset 9)`,
+			},
+			{
+				name:        "trailing data",
+				body:        `{"name":"test"} {"name":"other"}`,
+				expectError: `request json invalid. unexpected data after the json value (offset 15)`,
+			},
+			{
+				name:        "empty body",
+				body:        ``,
+				expectError: `request json invalid. empty body (offset 0)`,
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				// arrange
+				inputRequest := http.Request{
+					Header: http.Header{"Content-Type": []string{"application/json"}},
+					Body:   io.NopCloser(strings.NewReader(testCase.body)),
+				}
+
+				// act
+				err := request.JSON(&inputRequest, &schema{})
+
+				// assert
+				var jsonErr *request.JSONError
+				require.ErrorIs(t, err, request.ErrRequestJSONInvalid)
+				require.ErrorAs(t, err, &jsonErr)
+				require.Equal(t, testCase.expectField, jsonErr.Field)
+				require.EqualError(t, err, testCase.expectError)
+			})
+		}
+	})
+
+	t.Run("success - lenient decoder", func(t *testing.T) {
+		// arrange
+		dec := request.NewDecoder(request.ConfigDecoder{AllowTrailingData: true, UseNumber: true})
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"id":12345678901234567890,"extra":true} garbage`)),
+		}
+
+		// act
+		var body map[string]any
+		err := dec.Decode(&inputRequest, &body)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, json.Number("12345678901234567890"), body["id"])
+	})
 }
@@ -0,0 +1,22 @@
+package response
+
+import "net/http"
+
+// Envelope is the body of every successful response: a human-readable message and the data,
+// which is null when there is none.
+type Envelope struct {
+	// Message describes the outcome.
+	Message string `json:"message"`
+	// Data is the resource, or the list of resources.
+	Data any `json:"data"`
+}
+
+// OK writes a 200 response with the envelope of message and data.
+func OK(w http.ResponseWriter, message string, data any) {
+	JSON(w, http.StatusOK, Envelope{Message: message, Data: data})
+}
+
+// Created writes a 201 response with the envelope of message and the created resource.
+func Created(w http.ResponseWriter, message string, data any) {
+	JSON(w, http.StatusCreated, Envelope{Message: message, Data: data})
+}
@@ -0,0 +1,49 @@
+package response_test
+
+import (
+	"app/platform/web/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for OK and Created functions
+func TestEnvelope(t *testing.T) {
+	t.Run("ok with data", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		response.OK(rr, "customers found", []int{1, 2})
+
+		// assert
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"message":"customers found","data":[1,2]}`, rr.Body.String())
+	})
+
+	t.Run("ok without data", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		response.OK(rr, "invoices total updated", nil)
+
+		// assert
+		require.JSONEq(t, `{"message":"invoices total updated","data":null}`, rr.Body.String())
+	})
+
+	t.Run("created", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		response.Created(rr, "customer created", map[string]int{"id": 1})
+
+		// assert
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.JSONEq(t, `{"message":"customer created","data":{"id":1}}`, rr.Body.String())
+	})
+}
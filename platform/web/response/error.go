@@ -2,41 +2,145 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+
+	"app/platform/web/request"
+)
+
+// ContentTypeProblem is the content type of the error responses (RFC 9457).
+const ContentTypeProblem = "application/problem+json"
+
+// problemTypePrefix prefixes the code of a problem to form its type URI.
+const problemTypePrefix = "urn:app:problem:"
+
+// Problem codes, the machine-readable identifiers of the errors.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeInvalidJSON          = "invalid_json"
+	CodeInvalidParameter     = "invalid_parameter"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeUnprocessable        = "unprocessable"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal"
+	CodeUnavailable          = "unavailable"
 )
 
-type errorResponse struct {
-	Status  string `json:"status"`
+// codeByStatus is the default code of each status.
+var codeByStatus = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodeBodyTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeUnprocessable,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+}
+
+// ProblemDetails is the body of every error response, as defined by RFC 9457, extended
+// with a machine-readable code and the errors of the single fields of the request.
+type ProblemDetails struct {
+	// Type is a URI identifying the problem: urn:app:problem:<code>.
+	Type string `json:"type"`
+	// Title is the status text.
+	Title string `json:"title"`
+	// Status is the status code.
+	Status int `json:"status"`
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Code identifies the problem.
+	Code string `json:"code"`
+	// Errors are the errors of the single fields of the request, if any.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is the error of a single field, or query parameter, of a request.
+type FieldError struct {
+	// Field is the name, or path, of the field.
+	Field string `json:"field"`
+	// Message describes the error.
 	Message string `json:"message"`
 }
 
-func Error(w http.ResponseWriter, statusCode int, message string) {
-	// default status code
-	defaultStatusCode := http.StatusInternalServerError
+// Error returns the error message.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Problem writes p as a problem+json response. Type, title and code default from the status.
+func Problem(w http.ResponseWriter, p ProblemDetails) {
 	// check if status code is valid
-	if statusCode > 299 && statusCode < 600 {
-		defaultStatusCode = statusCode
+	if p.Status < 300 || p.Status > 599 {
+		p.Status = http.StatusInternalServerError
 	}
-
-	// response
-	body := errorResponse{
-		Status:  http.StatusText(defaultStatusCode),
-		Message: message,
+	if p.Code == "" {
+		p.Code = codeByStatus[p.Status]
+		if p.Code == "" {
+			p.Code = CodeBadRequest
+			if p.Status >= 500 {
+				p.Code = CodeInternal
+			}
+		}
+	}
+	if p.Type == "" {
+		p.Type = problemTypePrefix + p.Code
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
 	}
-	bytes, err := json.Marshal(body)
+
+	bytes, err := json.Marshal(p)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	// write response
-	w.WriteHeader(defaultStatusCode)
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(p.Status)
 	w.Write(bytes)
 }
 
+// Error writes an error response of the given status, with the default code of the status.
+func Error(w http.ResponseWriter, statusCode int, message string) {
+	Problem(w, ProblemDetails{Status: statusCode, Detail: message})
+}
+
+// Errorf writes an error response of the given status, with a formatted message.
 func Errorf(w http.ResponseWriter, statusCode int, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	Error(w, statusCode, message)
 }
+
+// ErrorCode writes an error response of the given status and code, with the errors of the fields.
+func ErrorCode(w http.ResponseWriter, statusCode int, code, message string, fields ...FieldError) {
+	Problem(w, ProblemDetails{Status: statusCode, Code: code, Detail: message, Errors: fields})
+}
+
+// RequestError writes the error response of an error of request.JSON:
+// 413 if the body is too large, 415 if it is not json, 400 if the json is invalid.
+func RequestError(w http.ResponseWriter, err error) {
+	var jsonErr *request.JSONError
+	switch {
+	case errors.Is(err, request.ErrRequestBodyTooLarge):
+		ErrorCode(w, http.StatusRequestEntityTooLarge, CodeBodyTooLarge, "request body too large")
+	case errors.Is(err, request.ErrRequestContentTypeNotJSON):
+		ErrorCode(w, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, err.Error())
+	case errors.As(err, &jsonErr) && jsonErr.Field != "":
+		ErrorCode(w, http.StatusBadRequest, CodeInvalidJSON, err.Error(), FieldError{Field: jsonErr.Field, Message: jsonErr.Reason})
+	default:
+		ErrorCode(w, http.StatusBadRequest, CodeInvalidJSON, err.Error())
+	}
+}
@@ -0,0 +1,95 @@
+package response_test
+
+import (
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Error function
+func TestError(t *testing.T) {
+	t.Run("problem with the default code of the status", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		response.Error(rr, http.StatusNotFound, "customer not found")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Equal(t, response.ContentTypeProblem, rr.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"type":"urn:app:problem:not_found","title":"Not Found","status":404,"detail":"customer not found","code":"not_found"}`, rr.Body.String())
+	})
+
+	t.Run("invalid status is a server error", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		response.Error(rr, http.StatusOK, "not an error")
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+		require.JSONEq(t, `{"type":"urn:app:problem:internal","title":"Internal Server Error","status":500,"detail":"not an error","code":"internal"}`, rr.Body.String())
+	})
+
+	t.Run("code and field errors", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		response.ErrorCode(rr, http.StatusBadRequest, response.CodeInvalidParameter, "invalid id", response.FieldError{Field: "id", Message: "must be a positive integer"})
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.JSONEq(t, `{"type":"urn:app:problem:invalid_parameter","title":"Bad Request","status":400,"detail":"invalid id","code":"invalid_parameter","errors":[{"field":"id","message":"must be a positive integer"}]}`, rr.Body.String())
+	})
+}
+
+// Tests for RequestError function
+func TestRequestError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "body too large",
+			err:        request.ErrRequestBodyTooLarge,
+			expectCode: http.StatusRequestEntityTooLarge,
+			expectBody: `{"type":"urn:app:problem:body_too_large","title":"Request Entity Too Large","status":413,"detail":"request body too large","code":"body_too_large"}`,
+		},
+		{
+			name:       "content type",
+			err:        request.ErrRequestContentTypeNotJSON,
+			expectCode: http.StatusUnsupportedMediaType,
+			expectBody: `{"type":"urn:app:problem:unsupported_media_type","title":"Unsupported Media Type","status":415,"detail":"request content type is not application/json","code":"unsupported_media_type"}`,
+		},
+		{
+			name:       "field error",
+			err:        &request.JSONError{Field: "quantity", Offset: 17, Reason: "expected int, got string"},
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"type":"urn:app:problem:invalid_json","title":"Bad Request","status":400,"detail":"request json invalid. field \"quantity\": expected int, got string (offset 17)","code":"invalid_json","errors":[{"field":"quantity","message":"expected int, got string"}]}`,
+		},
+	}
+
+	for idx, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d - %s", idx, testCase.name), func(t *testing.T) {
+			// arrange
+			rr := httptest.NewRecorder()
+
+			// act
+			response.RequestError(rr, testCase.err)
+
+			// assert
+			require.Equal(t, testCase.expectCode, rr.Code)
+			require.JSONEq(t, testCase.expectBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of the webhook requests.
+const (
+	// HeaderEvent is the event of the request.
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderDelivery is the id of the delivery, the same on every retry of an event, so receivers can deduplicate.
+	HeaderDelivery = "X-Webhook-Delivery"
+	// HeaderSignature is the signature of the body, "sha256=" followed by its hex HMAC-SHA256.
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var (
+	// ErrRejected is used when the webhook answers with a status other than 2xx.
+	ErrRejected = errors.New("webhook rejected the event")
+)
+
+// Config is the configuration of a webhook Client.
+type Config struct {
+	// URL is the endpoint the events are posted to.
+	URL string
+	// Secret is the key of the signatures. Empty sends no HeaderSignature.
+	Secret string
+	// Timeout is the time limit of a request. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// New creates a new Client posting to the endpoint of cfg.
+func New(cfg Config) *Client {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	return &Client{url: cfg.URL, secret: cfg.Secret, hc: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// Client posts the events to a webhook endpoint.
+type Client struct {
+	// url is the endpoint.
+	url string
+	// secret is the key of the signatures.
+	secret string
+	// hc is the http client.
+	hc *http.Client
+}
+
+// Send posts the JSON payload of the event of the delivery id. It fails with ErrRejected on a status other than 2xx.
+func (c *Client) Send(ctx context.Context, event string, id int, payload []byte) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(id))
+	if c.secret != "" {
+		req.Header.Set(HeaderSignature, Sign(c.secret, payload))
+	}
+
+	res, err := c.hc.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	// - drain the body, so the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%w: status %d", ErrRejected, res.StatusCode)
+	}
+	return
+}
+
+// Sign returns the signature of payload with secret, as sent in HeaderSignature.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the Client
+func TestClient(t *testing.T) {
+	t.Run("event is posted signed", func(t *testing.T) {
+		// arrange
+		var (
+			header http.Header
+			body   []byte
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Clone()
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+		c := New(Config{URL: srv.URL, Secret: "s3cret"})
+
+		// act
+		err := c.Send(context.Background(), "stock.low", 7, []byte(`{"product_id":1}`))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, `{"product_id":1}`, string(body))
+		require.Equal(t, "application/json", header.Get("Content-Type"))
+		require.Equal(t, "stock.low", header.Get(HeaderEvent))
+		require.Equal(t, "7", header.Get(HeaderDelivery))
+		require.Equal(t, Sign("s3cret", body), header.Get(HeaderSignature))
+	})
+
+	t.Run("no secret, no signature", func(t *testing.T) {
+		// arrange
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Clone()
+		}))
+		defer srv.Close()
+		c := New(Config{URL: srv.URL})
+
+		// act
+		err := c.Send(context.Background(), "stock.low", 1, []byte(`{}`))
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, header.Get(HeaderSignature))
+	})
+
+	t.Run("status other than 2xx is rejected", func(t *testing.T) {
+		// arrange
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+		c := New(Config{URL: srv.URL})
+
+		// act
+		err := c.Send(context.Background(), "stock.low", 1, []byte(`{}`))
+
+		// assert
+		require.ErrorIs(t, err, ErrRejected)
+		require.EqualError(t, err, "webhook rejected the event: status 503")
+	})
+}
+
+// Tests for Sign
+func TestSign(t *testing.T) {
+	// the HMAC-SHA256 test vector of RFC 4231, test case 2
+	require.Equal(t, "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", Sign("Jefe", []byte("what do ya want for nothing?")))
+}